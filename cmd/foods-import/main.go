@@ -0,0 +1,139 @@
+// Command foods-import loads a USDA FoodData Central download into the foods
+// table and can recompute the nutrition of every recipe against it.
+//
+//	foods-import -json FoodData_Central_foundation_food_json.json
+//	foods-import -csv ./FoodData_Central_sr_legacy_food_csv -recompute
+//
+// Foods are upserted by FoodData Central ID, so re-running an import
+// updates them in place.
+package main
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/meal-planner/backend/internal/config"
+	"github.com/meal-planner/backend/internal/database"
+	"github.com/meal-planner/backend/internal/foods"
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/repository"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+const batchSize = 500
+
+func main() {
+	jsonPath := flag.String("json", "", "FoodData Central JSON file to import")
+	csvDir := flag.String("csv", "", "directory of a FoodData Central CSV download to import")
+	branded := flag.Bool("branded", false, "also import branded foods")
+	recompute := flag.Bool("recompute", false, "recompute the nutrition of all recipes after importing")
+	flag.Parse()
+
+	if *jsonPath == "" && *csvDir == "" && !*recompute {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+	cfg := config.Load()
+
+	db, err := database.NewConnection(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := database.Migrate(db); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	foodRepo := repository.NewFoodRepository(db)
+	opts := foods.ReadOptions{Branded: *branded}
+
+	if *jsonPath != "" {
+		file, err := os.Open(*jsonPath)
+		if err != nil {
+			log.Fatalf("Failed to open %s: %v", *jsonPath, err)
+		}
+		imported, err := importFoods(foodRepo, func(fn func(models.Food) error) error {
+			return foods.ReadFDCJSON(file, opts, fn)
+		})
+		file.Close()
+		if err != nil {
+			log.Fatalf("Failed to import %s: %v", *jsonPath, err)
+		}
+		log.Printf("Imported %d foods from %s", imported, *jsonPath)
+	}
+
+	if *csvDir != "" {
+		imported, err := importFoods(foodRepo, func(fn func(models.Food) error) error {
+			return foods.ReadFDCCSV(os.DirFS(*csvDir), opts, fn)
+		})
+		if err != nil {
+			log.Fatalf("Failed to import %s: %v", *csvDir, err)
+		}
+		log.Printf("Imported %d foods from %s", imported, *csvDir)
+	}
+
+	if *recompute {
+		recipeRepo := repository.NewRecipeRepository(db)
+		foodService := services.NewFoodService(foodRepo)
+		updated, skipped := 0, 0
+		err := recipeRepo.ForEach(func(recipe *models.Recipe) error {
+			if err := foodService.ComputeNutrition(recipe); err != nil {
+				var validationErr *services.ValidationError
+				if !errors.As(err, &validationErr) {
+					return err
+				}
+				log.Printf("Skipping recipe %s: %s", recipe.ID, validationErr.Message)
+				skipped++
+				return nil
+			}
+			saved, err := recipeRepo.UpdateNutrition(recipe)
+			if err != nil {
+				return err
+			}
+			if !saved {
+				log.Printf("Skipping recipe %s: edited during the recompute", recipe.ID)
+				skipped++
+				return nil
+			}
+			updated++
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("Failed to recompute recipe nutrition: %v", err)
+		}
+		log.Printf("Recomputed nutrition of %d recipes, skipped %d", updated, skipped)
+	}
+}
+
+// importFoods upserts the foods produced by read in batches and returns how
+// many were imported
+func importFoods(repo repository.FoodRepository, read func(fn func(models.Food) error) error) (int, error) {
+	batch := make([]models.Food, 0, batchSize)
+	imported := 0
+	flush := func() error {
+		if err := repo.Upsert(batch); err != nil {
+			return err
+		}
+		imported += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	err := read(func(food models.Food) error {
+		batch = append(batch, food)
+		if len(batch) == batchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return imported, err
+	}
+	return imported, flush()
+}
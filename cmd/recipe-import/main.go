@@ -0,0 +1,345 @@
+// Command recipe-import bulk-loads recipes from CSV or JSON-lines files,
+// such as the curated recipes the content team keeps in spreadsheets.
+//
+//	recipe-import -author editor@example.com -public recipes.csv more.jsonl
+//	recipe-import -dry-run -report report.json recipes.csv
+//
+// Every row is validated as the API validates a new recipe. Rows named like
+// a recipe the author already has, or like an earlier row, are skipped as
+// duplicates. Valid rows are saved in batches, one transaction per batch.
+// With -dry-run nothing is saved. A JSON report of the rows that were not
+// imported is written to -report, or to stdout; the command exits with
+// status 1 when any row was invalid or failed to save.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/meal-planner/backend/internal/config"
+	"github.com/meal-planner/backend/internal/database"
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/recipeimport"
+	"github.com/meal-planner/backend/internal/repository"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+// Row statuses in the report
+const (
+	statusInvalid   = "invalid"
+	statusDuplicate = "duplicate"
+	statusFailed    = "failed"
+)
+
+// report is the machine-readable outcome of an import
+type report struct {
+	DryRun bool `json:"dryRun"`
+	// Imported counts the rows saved, or that would be saved in a dry run
+	Imported   int         `json:"imported"`
+	Duplicates int         `json:"duplicates"`
+	Invalid    int         `json:"invalid"`
+	Failed     int         `json:"failed"`
+	Errors     []rowReport `json:"errors"`
+}
+
+// rowReport is a row, or a whole file, that was not imported
+type rowReport struct {
+	File   string                    `json:"file"`
+	Line   int                       `json:"line,omitempty"`
+	Name   string                    `json:"name,omitempty"`
+	Status string                    `json:"status"`
+	Errors []recipeimport.FieldError `json:"errors"`
+}
+
+func (r *report) add(row rowReport) {
+	switch row.Status {
+	case statusInvalid:
+		r.Invalid++
+	case statusDuplicate:
+		r.Duplicates++
+	case statusFailed:
+		r.Failed++
+	}
+	r.Errors = append(r.Errors, row)
+}
+
+// pending is a validated row waiting for its batch to be saved
+type pending struct {
+	file     string
+	line     int
+	recipe   *models.Recipe
+	revision *models.RecipeRevision
+}
+
+// importer validates rows and saves them in batches
+type importer struct {
+	recipeRepo  repository.RecipeRepository
+	foodService services.FoodService
+	authorID    *string
+	public      bool
+	dryRun      bool
+	batchSize   int
+
+	// seen holds the normalized names of existing and saved recipes, and
+	// queued those of the batch waiting to be saved
+	seen   map[string]bool
+	queued map[string]bool
+	batch  []pending
+	report *report
+}
+
+func main() {
+	authorEmail := flag.String("author", "", "email of the user the recipes are created by (default: no author)")
+	public := flag.Bool("public", false, "publish the imported recipes")
+	dryRun := flag.Bool("dry-run", false, "validate and check for duplicates without saving")
+	batchSize := flag.Int("batch", 100, "number of recipes saved per transaction")
+	reportPath := flag.String("report", "", "write the JSON report to this file instead of stdout")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] file.csv|file.jsonl ...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 || *batchSize < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	for _, path := range flag.Args() {
+		if _, err := rowReader(path); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+	cfg := config.Load()
+
+	db, err := database.NewConnection(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	// A dry run leaves the schema alone too
+	if !*dryRun {
+		if err := database.Migrate(db); err != nil {
+			log.Fatalf("Failed to run migrations: %v", err)
+		}
+	}
+
+	imp := &importer{
+		recipeRepo:  repository.NewRecipeRepository(db),
+		foodService: services.NewFoodService(repository.NewFoodRepository(db)),
+		public:      *public,
+		dryRun:      *dryRun,
+		batchSize:   *batchSize,
+		seen:        make(map[string]bool),
+		queued:      make(map[string]bool),
+		report:      &report{DryRun: *dryRun, Errors: []rowReport{}},
+	}
+
+	if *authorEmail != "" {
+		author, err := repository.NewUserRepository(db).FindByEmail(*authorEmail)
+		if err != nil {
+			log.Fatalf("Failed to find author: %v", err)
+		}
+		if author == nil {
+			log.Fatalf("No user with email %s", *authorEmail)
+		}
+		imp.authorID = &author.ID
+	}
+
+	names, err := imp.recipeRepo.Names(imp.authorID)
+	if err != nil {
+		log.Fatalf("Failed to load existing recipes: %v", err)
+	}
+	for _, name := range names {
+		imp.seen[dedupeKey(name)] = true
+	}
+
+	for _, path := range flag.Args() {
+		if err := imp.importFile(path); err != nil {
+			log.Fatalf("Failed to import %s: %v", path, err)
+		}
+	}
+	if err := imp.flush(); err != nil {
+		log.Fatalf("Failed to save recipes: %v", err)
+	}
+
+	if err := writeReport(imp.report, *reportPath); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+	verb := "Imported"
+	if *dryRun {
+		verb = "Would import"
+	}
+	r := imp.report
+	log.Printf("%s %d recipes; skipped %d duplicates, %d invalid, %d failed", verb, r.Imported, r.Duplicates, r.Invalid, r.Failed)
+	if r.Invalid > 0 || r.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// rowReader picks the reader for a file by its extension
+func rowReader(path string) (func(io.Reader, func(recipeimport.Row) error) error, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return recipeimport.ReadCSV, nil
+	case ".jsonl", ".ndjson":
+		return recipeimport.ReadJSONL, nil
+	}
+	return nil, fmt.Errorf("%s: files must be .csv, .jsonl or .ndjson", path)
+}
+
+// importFile validates the rows of a file and queues them for saving. A
+// file that cannot be read is reported and skipped; only database errors
+// are returned.
+func (imp *importer) importFile(path string) error {
+	read, err := rowReader(path)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		imp.report.add(rowReport{File: path, Status: statusInvalid, Errors: []recipeimport.FieldError{{Message: err.Error()}}})
+		return nil
+	}
+	defer file.Close()
+
+	err = read(file, func(row recipeimport.Row) error {
+		return imp.importRow(path, row)
+	})
+	var dbErr *databaseError
+	if errors.As(err, &dbErr) {
+		return dbErr.err
+	}
+	if err != nil {
+		imp.report.add(rowReport{File: path, Status: statusInvalid, Errors: []recipeimport.FieldError{{Message: err.Error()}}})
+	}
+	return nil
+}
+
+// databaseError marks an error that stops the import, as opposed to a
+// problem with the file being read
+type databaseError struct {
+	err error
+}
+
+func (e *databaseError) Error() string {
+	return e.err.Error()
+}
+
+func (imp *importer) importRow(path string, row recipeimport.Row) error {
+	entry := rowReport{File: path, Line: row.Line, Name: row.Draft.Name, Errors: row.Errors}
+	if len(row.Errors) > 0 {
+		entry.Status = statusInvalid
+		imp.report.add(entry)
+		return nil
+	}
+
+	input := services.InputFromDraft(&row.Draft)
+	input.IsPublic = &imp.public
+	recipe, err := services.NewRecipe(input, imp.authorID)
+	if err == nil {
+		err = imp.foodService.ComputeNutrition(recipe)
+	}
+	if err != nil {
+		var validationErr *services.ValidationError
+		if !errors.As(err, &validationErr) {
+			return &databaseError{err}
+		}
+		entry.Status = statusInvalid
+		entry.Errors = []recipeimport.FieldError{{Message: validationErr.Message}}
+		imp.report.add(entry)
+		return nil
+	}
+
+	key := dedupeKey(recipe.Name)
+	if imp.seen[key] || imp.queued[key] {
+		entry.Status = statusDuplicate
+		entry.Errors = []recipeimport.FieldError{{Field: "name", Message: fmt.Sprintf("a recipe named %q already exists", recipe.Name)}}
+		imp.report.add(entry)
+		return nil
+	}
+	imp.queued[key] = true
+
+	snapshot := recipe.Snapshot()
+	revision := &models.RecipeRevision{AuthorID: imp.authorID, Summary: "Imported from " + filepath.Base(path), Snapshot: &snapshot}
+	imp.batch = append(imp.batch, pending{file: path, line: row.Line, recipe: recipe, revision: revision})
+	if len(imp.batch) >= imp.batchSize {
+		if err := imp.flush(); err != nil {
+			return &databaseError{err}
+		}
+	}
+	return nil
+}
+
+// flush saves the queued recipes in one transaction. When the transaction
+// fails its rows are reported as failed and the import goes on; their names
+// are not marked as seen, so a later row of the same name can still be saved.
+func (imp *importer) flush() error {
+	batch := imp.batch
+	imp.batch = nil
+	imp.queued = make(map[string]bool)
+	if len(batch) == 0 {
+		return nil
+	}
+	if imp.dryRun {
+		imp.markSeen(batch)
+		imp.report.Imported += len(batch)
+		return nil
+	}
+
+	recipes := make([]*models.Recipe, len(batch))
+	revisions := make([]*models.RecipeRevision, len(batch))
+	for i, p := range batch {
+		recipes[i], revisions[i] = p.recipe, p.revision
+	}
+	if err := imp.recipeRepo.CreateBatch(recipes, revisions); err != nil {
+		log.Printf("Failed to save a batch of %d recipes: %v", len(batch), err)
+		for _, p := range batch {
+			imp.report.add(rowReport{
+				File: p.file, Line: p.line, Name: p.recipe.Name, Status: statusFailed,
+				Errors: []recipeimport.FieldError{{Message: "batch could not be saved: " + err.Error()}},
+			})
+		}
+		return nil
+	}
+	imp.markSeen(batch)
+	imp.report.Imported += len(batch)
+	log.Printf("Saved %d recipes", imp.report.Imported)
+	return nil
+}
+
+func (imp *importer) markSeen(batch []pending) {
+	for _, p := range batch {
+		imp.seen[dedupeKey(p.recipe.Name)] = true
+	}
+}
+
+// dedupeKey compares recipe names without regard to case or spacing
+func dedupeKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+func writeReport(r *report, path string) error {
+	out := os.Stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
+	_ "time/tzdata" // embed the time zone database for user time zones
 
 	"github.com/joho/godotenv"
 	"github.com/meal-planner/backend/internal/config"
 	"github.com/meal-planner/backend/internal/database"
+	"github.com/meal-planner/backend/internal/repository"
 	"github.com/meal-planner/backend/internal/router"
+	"github.com/meal-planner/backend/internal/services"
+	"github.com/meal-planner/backend/internal/storage"
 )
 
 func main() {
@@ -30,8 +36,24 @@ func main() {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
+	// Initialize blob storage for uploads
+	store, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	// Keep similar-recipe lists current in the background
+	if cfg.SimilarityJobEnabled {
+		job := services.NewSimilarityJob(
+			repository.NewRecipeRepository(db),
+			repository.NewRecipeNeighborRepository(db),
+			time.Duration(cfg.SimilarityRefreshSeconds)*time.Second,
+		)
+		go job.Run(context.Background())
+	}
+
 	// Initialize router with dependencies
-	r := router.Setup(db, cfg)
+	r := router.Setup(db, cfg, store)
 
 	// Start server
 	port := os.Getenv("PORT")
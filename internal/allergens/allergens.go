@@ -0,0 +1,209 @@
+// Package allergens detects food allergens in ingredient names using a
+// curated mapping of ingredients, and their derivatives such as whey or
+// tahini, to the major allergen groups.
+package allergens
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Allergen groups
+const (
+	Gluten    = "gluten"
+	Dairy     = "dairy"
+	Egg       = "egg"
+	Peanut    = "peanut"
+	TreeNut   = "tree_nut"
+	Soy       = "soy"
+	Fish      = "fish"
+	Shellfish = "shellfish"
+	Sesame    = "sesame"
+	Mustard   = "mustard"
+	Celery    = "celery"
+	Sulfite   = "sulfite"
+)
+
+// All lists the allergen groups in display order
+var All = []string{Gluten, Dairy, Egg, Peanut, TreeNut, Soy, Fish, Shellfish, Sesame, Mustard, Celery, Sulfite}
+
+// aliases maps the ways users write allergies, such as the onboarding
+// options "Peanuts" and "Tree Nuts", to allergen groups. A wheat allergy is
+// treated as gluten, which covers every wheat product.
+var aliases = map[string]string{
+	"gluten": Gluten, "wheat": Gluten, "celiac": Gluten, "coeliac": Gluten,
+	"dairy": Dairy, "milk": Dairy, "lactose": Dairy, "casein": Dairy,
+	"egg":    Egg,
+	"peanut": Peanut, "groundnut": Peanut,
+	"tree nut": TreeNut, "tree_nut": TreeNut, "nut": TreeNut,
+	"soy": Soy, "soya": Soy, "soybean": Soy,
+	"fish":      Fish,
+	"shellfish": Shellfish, "crustacean": Shellfish, "mollusc": Shellfish, "mollusk": Shellfish,
+	"sesame":  Sesame,
+	"mustard": Mustard,
+	"celery":  Celery,
+	"sulfite": Sulfite, "sulphite": Sulfite,
+}
+
+// Normalize returns the allergen group for an allergy as a user wrote it,
+// e.g. "Tree Nuts" or "eggs", and false when it is not a known group
+func Normalize(allergy string) (string, bool) {
+	words := words(allergy)
+	key := strings.Join(words, " ")
+	if group, ok := aliases[key]; ok {
+		return group, true
+	}
+	key = strings.TrimSuffix(key, " allergy")
+	group, ok := aliases[key]
+	return group, ok
+}
+
+// Detect returns the allergen groups of an ingredient name, in the order of
+// All. Phrases are matched on whole words, longest first, so "peanut butter"
+// is only peanut and "eggplant" is not egg. Names marked free of an allergen,
+// like "gluten-free flour" or "vegan butter", do not report it.
+func Detect(name string) []string {
+	tokens := words(name)
+	found := make(map[string]bool)
+	for i := 0; i < len(tokens); {
+		n, groups := matchAt(tokens, i)
+		if n == 0 {
+			i++
+			continue
+		}
+		for _, g := range groups {
+			found[g] = true
+		}
+		i += n
+	}
+	for _, g := range freeOf(tokens) {
+		delete(found, g)
+	}
+	return sorted(found)
+}
+
+// matchAt finds the longest mapping phrase starting at tokens[i] and returns
+// its length in words and its groups
+func matchAt(tokens []string, i int) (int, []string) {
+	for n := maxPhraseWords; n > 0; n-- {
+		if i+n > len(tokens) {
+			continue
+		}
+		if groups, ok := phrases[strings.Join(tokens[i:i+n], " ")]; ok {
+			return n, groups
+		}
+	}
+	return 0, nil
+}
+
+// freeOf returns the groups a name says it is free of
+func freeOf(tokens []string) []string {
+	var groups []string
+	for i, t := range tokens {
+		if t == "vegan" || t == "plant" && i+1 < len(tokens) && tokens[i+1] == "based" {
+			groups = append(groups, Dairy, Egg)
+		}
+		if t != "free" || i == 0 {
+			continue
+		}
+		switch tokens[i-1] {
+		case "nut":
+			groups = append(groups, TreeNut, Peanut)
+		case "lactose":
+			groups = append(groups, Dairy)
+		default:
+			if group, ok := aliases[tokens[i-1]]; ok {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups
+}
+
+// Merge combines allergen lists into one in the order of All, dropping
+// duplicates and values that are not allergen groups
+func Merge(lists ...[]string) []string {
+	found := make(map[string]bool)
+	for _, list := range lists {
+		for _, g := range list {
+			found[g] = true
+		}
+	}
+	return sorted(found)
+}
+
+// Without returns the groups of list that are not in remove
+func Without(list, remove []string) []string {
+	removed := make(map[string]bool, len(remove))
+	for _, g := range remove {
+		removed[g] = true
+	}
+	var kept []string
+	for _, g := range list {
+		if !removed[g] {
+			kept = append(kept, g)
+		}
+	}
+	return kept
+}
+
+// Conflicts returns the recipe allergens the user is allergic to. Allergies
+// that are not allergen groups are ignored here.
+func Conflicts(recipeAllergens, allergies []string) []string {
+	groups := make(map[string]bool, len(allergies))
+	for _, allergy := range allergies {
+		if group, ok := Normalize(allergy); ok {
+			groups[group] = true
+		}
+	}
+	found := make(map[string]bool)
+	for _, g := range recipeAllergens {
+		if groups[g] {
+			found[g] = true
+		}
+	}
+	return sorted(found)
+}
+
+func sorted(found map[string]bool) []string {
+	if len(found) == 0 {
+		return nil
+	}
+	groups := make([]string, 0, len(found))
+	for _, g := range All {
+		if found[g] {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// words splits a name into lower-case singular words
+func words(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	for i, w := range fields {
+		fields[i] = singular(strings.Trim(w, "'"))
+	}
+	return fields
+}
+
+// singular strips common English plural endings
+func singular(word string) string {
+	switch {
+	case len(word) <= 3:
+		return word
+	case strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "oes"), strings.HasSuffix(word, "ches"),
+		strings.HasSuffix(word, "shes"), strings.HasSuffix(word, "sses"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"),
+		strings.HasSuffix(word, "is"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	}
+	return word
+}
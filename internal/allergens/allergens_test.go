@@ -0,0 +1,104 @@
+package allergens
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"all-purpose flour", []string{Gluten}},
+		{"unsalted butter", []string{Dairy}},
+		{"large eggs", []string{Egg}},
+		{"eggplant", nil},
+		{"whey protein powder", []string{Dairy}},
+		{"tahini", []string{Sesame}},
+		{"hummus", []string{Sesame}},
+		{"creamy peanut butter", []string{Peanut}},
+		{"almond milk", []string{TreeNut}},
+		{"coconut milk", nil},
+		{"cream of tartar", nil},
+		{"heavy cream", []string{Dairy}},
+		{"soy sauce", []string{Gluten, Soy}},
+		{"low-sodium tamari", []string{Soy}},
+		{"egg noodles", []string{Gluten, Egg}},
+		{"rice noodles", nil},
+		{"gluten-free all-purpose flour", nil},
+		{"vegan butter", nil},
+		{"nutmeg", nil},
+		{"butternut squash", nil},
+		{"water chestnuts", nil},
+		{"chopped walnuts", []string{TreeNut}},
+		{"toasted pine nuts", []string{TreeNut}},
+		{"anchovies", []string{Fish}},
+		{"Worcestershire sauce", []string{Fish}},
+		{"raw shrimp, peeled", []string{Shellfish}},
+		{"oyster mushrooms", nil},
+		{"oyster sauce", []string{Shellfish}},
+		{"freshly grated Parmesan cheese", []string{Dairy}},
+		{"crème fraîche", []string{Dairy}},
+		{"basil pesto", []string{Dairy, TreeNut}},
+		{"Dijon mustard", []string{Mustard}},
+		{"dry white wine", []string{Sulfite}},
+		{"white wine vinegar", []string{Sulfite}},
+		{"za'atar", []string{Sesame}},
+		{"butter lettuce", nil},
+		{"olive oil", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Detect(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		allergy string
+		want    string
+		wantOK  bool
+	}{
+		{"Peanuts", Peanut, true},
+		{"Tree Nuts", TreeNut, true},
+		{"Dairy", Dairy, true},
+		{"Eggs", Egg, true},
+		{"Wheat", Gluten, true},
+		{"Shellfish", Shellfish, true},
+		{"milk allergy", Dairy, true},
+		{"tree_nut", TreeNut, true},
+		{"strawberries", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.allergy, func(t *testing.T) {
+			got, ok := Normalize(tt.allergy)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Normalize(%q) = %q, %v, want %q, %v", tt.allergy, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	got := Conflicts([]string{Gluten, Dairy, Sesame}, []string{"Sesame", "Wheat", "Kiwi"})
+	want := []string{Gluten, Sesame}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Conflicts() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeWithout(t *testing.T) {
+	merged := Merge([]string{Sesame, Dairy}, []string{Gluten, Dairy}, []string{"unknown"})
+	if want := []string{Gluten, Dairy, Sesame}; !reflect.DeepEqual(merged, want) {
+		t.Errorf("Merge() = %v, want %v", merged, want)
+	}
+	if got, want := Without(merged, []string{Dairy}), []string{Gluten, Sesame}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Without() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,124 @@
+package allergens
+
+import "strings"
+
+// phrases maps ingredient words and phrases, in the singular form produced
+// by words, to the allergen groups they contain. Phrases that map to no
+// group, like "coconut milk" or "cream of tartar", stop their words from
+// matching shorter entries such as "milk" or "cream".
+var phrases = map[string][]string{
+	// Gluten
+	"wheat": {Gluten}, "flour": {Gluten}, "bread": {Gluten}, "breadcrumb": {Gluten},
+	"bread crumb": {Gluten}, "panko": {Gluten}, "crouton": {Gluten},
+	"pasta": {Gluten}, "spaghetti": {Gluten}, "linguine": {Gluten},
+	"fettuccine": {Gluten}, "penne": {Gluten}, "rigatoni": {Gluten},
+	"fusilli": {Gluten}, "macaroni": {Gluten}, "lasagna": {Gluten},
+	"lasagne": {Gluten}, "orzo": {Gluten}, "ravioli": {Gluten},
+	"tortellini": {Gluten}, "gnocchi": {Gluten}, "noodle": {Gluten},
+	"ramen": {Gluten}, "udon": {Gluten}, "soba": {Gluten}, "couscous": {Gluten},
+	"bulgur": {Gluten}, "bulgar": {Gluten}, "barley": {Gluten}, "rye": {Gluten},
+	"spelt": {Gluten}, "semolina": {Gluten}, "farro": {Gluten},
+	"durum": {Gluten}, "freekeh": {Gluten}, "kamut": {Gluten},
+	"seitan": {Gluten}, "malt": {Gluten}, "beer": {Gluten}, "lager": {Gluten},
+	"tortilla": {Gluten}, "pita": {Gluten}, "naan": {Gluten},
+	"bagel": {Gluten}, "croissant": {Gluten}, "baguette": {Gluten},
+	"cracker": {Gluten}, "biscuit": {Gluten}, "cookie": {Gluten},
+	"pastry": {Gluten}, "pie crust": {Gluten}, "phyllo": {Gluten},
+	"filo": {Gluten}, "wonton": {Gluten}, "dumpling": {Gluten},
+	"brioche": {Gluten, Dairy, Egg}, "challah": {Gluten, Egg},
+	"egg noodle": {Gluten, Egg},
+	// Flours, noodles and wraps made without gluten grains
+	"rice flour": {}, "coconut flour": {}, "corn flour": {}, "cornflour": {},
+	"chickpea flour": {}, "gram flour": {}, "buckwheat flour": {},
+	"tapioca flour": {}, "potato flour": {}, "cassava flour": {},
+	"almond flour": {TreeNut}, "almond meal": {TreeNut},
+	"rice noodle": {}, "glass noodle": {}, "rice paper": {},
+	"corn tortilla": {}, "ginger beer": {}, "root beer": {},
+
+	// Dairy
+	"milk": {Dairy}, "butter": {Dairy}, "buttermilk": {Dairy}, "cream": {Dairy},
+	"sour cream": {Dairy}, "creme fraiche": {Dairy}, "crème fraîche": {Dairy},
+	"cheese": {Dairy}, "parmesan": {Dairy}, "parmigiano": {Dairy},
+	"pecorino": {Dairy}, "mozzarella": {Dairy}, "cheddar": {Dairy},
+	"ricotta": {Dairy}, "feta": {Dairy}, "gouda": {Dairy}, "brie": {Dairy},
+	"camembert": {Dairy}, "gruyere": {Dairy}, "gruyère": {Dairy},
+	"emmental": {Dairy}, "mascarpone": {Dairy}, "halloumi": {Dairy},
+	"paneer": {Dairy}, "provolone": {Dairy}, "manchego": {Dairy},
+	"queso": {Dairy}, "yogurt": {Dairy}, "yoghurt": {Dairy}, "kefir": {Dairy},
+	"ghee": {Dairy}, "whey": {Dairy}, "casein": {Dairy}, "caseinate": {Dairy},
+	"lactose": {Dairy}, "half and half": {Dairy},
+	"custard": {Dairy, Egg}, "hollandaise": {Dairy, Egg},
+	// Milks, creams and butters that are not dairy
+	"coconut milk": {}, "coconut cream": {}, "coconut yogurt": {},
+	"oat milk": {}, "rice milk": {}, "soy milk": {Soy}, "soymilk": {Soy},
+	"almond milk": {TreeNut}, "cashew milk": {TreeNut},
+	"peanut butter": {Peanut}, "almond butter": {TreeNut},
+	"cashew butter": {TreeNut}, "nut butter": {TreeNut},
+	"cocoa butter": {}, "apple butter": {}, "shea butter": {},
+	"sunflower seed butter": {}, "butter lettuce": {}, "butter bean": {},
+	"cream of tartar": {},
+
+	// Egg
+	"egg": {Egg}, "egg yolk": {Egg}, "egg white": {Egg}, "mayonnaise": {Egg},
+	"mayo": {Egg}, "aioli": {Egg}, "meringue": {Egg},
+
+	// Peanut
+	"peanut": {Peanut}, "groundnut": {Peanut}, "satay": {Peanut},
+
+	// Tree nuts
+	"nut": {TreeNut}, "almond": {TreeNut}, "walnut": {TreeNut},
+	"pecan": {TreeNut}, "cashew": {TreeNut}, "pistachio": {TreeNut},
+	"hazelnut": {TreeNut}, "filbert": {TreeNut}, "macadamia": {TreeNut},
+	"brazil nut": {TreeNut}, "pine nut": {TreeNut}, "pignoli": {TreeNut},
+	"chestnut": {TreeNut}, "praline": {TreeNut}, "marzipan": {TreeNut},
+	"frangipane": {TreeNut}, "nutella": {TreeNut, Dairy},
+	"gianduja": {TreeNut}, "pesto": {TreeNut, Dairy},
+	"water chestnut": {},
+
+	// Soy
+	"soy": {Soy}, "soya": {Soy}, "soybean": {Soy},
+	"soy sauce": {Soy, Gluten}, "shoyu": {Soy, Gluten}, "tamari": {Soy},
+	"tofu": {Soy}, "tempeh": {Soy}, "edamame": {Soy}, "miso": {Soy},
+	"natto": {Soy}, "teriyaki": {Soy, Gluten}, "hoisin": {Soy, Gluten},
+
+	// Fish
+	"fish": {Fish}, "salmon": {Fish}, "tuna": {Fish}, "cod": {Fish},
+	"anchovy": {Fish}, "sardine": {Fish}, "halibut": {Fish},
+	"tilapia": {Fish}, "trout": {Fish}, "mackerel": {Fish},
+	"haddock": {Fish}, "pollock": {Fish}, "bass": {Fish}, "snapper": {Fish},
+	"swordfish": {Fish}, "catfish": {Fish}, "mahi": {Fish}, "herring": {Fish},
+	"bonito": {Fish}, "dashi": {Fish}, "caviar": {Fish}, "roe": {Fish},
+	"surimi": {Fish}, "fish sauce": {Fish}, "worcestershire": {Fish},
+	"caesar dressing": {Fish, Egg, Dairy},
+
+	// Shellfish, crustaceans and molluscs
+	"shellfish": {Shellfish}, "shrimp": {Shellfish}, "prawn": {Shellfish},
+	"crab": {Shellfish}, "lobster": {Shellfish}, "crayfish": {Shellfish},
+	"crawfish": {Shellfish}, "langoustine": {Shellfish},
+	"scallop": {Shellfish}, "clam": {Shellfish}, "mussel": {Shellfish},
+	"oyster": {Shellfish}, "squid": {Shellfish}, "calamari": {Shellfish},
+	"octopus": {Shellfish}, "cuttlefish": {Shellfish},
+	"oyster sauce": {Shellfish}, "shrimp paste": {Shellfish},
+	"oyster mushroom": {},
+
+	// Sesame
+	"sesame": {Sesame}, "tahini": {Sesame}, "tahina": {Sesame},
+	"hummus": {Sesame}, "halva": {Sesame}, "halvah": {Sesame},
+	"gomasio": {Sesame}, "za'atar": {Sesame}, "zaatar": {Sesame},
+
+	// Mustard, celery and sulfites
+	"mustard": {Mustard}, "dijon": {Mustard},
+	"celery": {Celery}, "celeriac": {Celery},
+	"wine": {Sulfite}, "sherry": {Sulfite}, "vermouth": {Sulfite},
+}
+
+// maxPhraseWords is the number of words in the longest phrase
+var maxPhraseWords = func() int {
+	longest := 0
+	for phrase := range phrases {
+		if n := len(strings.Fields(phrase)); n > longest {
+			longest = n
+		}
+	}
+	return longest
+}()
@@ -34,6 +34,28 @@ type Config struct {
 	// Rate limiting
 	RateLimitEnabled bool
 	RateLimitPerMin  int
+
+	// Frontend URL used to build links in outgoing emails
+	FrontendURL string
+
+	// Mail configuration (emails are logged when SMTPHost is empty)
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+
+	// Blob storage for uploaded images. The local driver writes under
+	// StorageDir and serves the files under StorageBaseURL.
+	StorageDriver  string
+	StorageDir     string
+	StorageBaseURL string
+
+	// Similar-recipe lists are refreshed in the background every
+	// SimilarityRefreshSeconds. Disable the job on all but one server when
+	// running several.
+	SimilarityJobEnabled     bool
+	SimilarityRefreshSeconds int
 }
 
 // Load loads configuration from environment variables
@@ -68,6 +90,25 @@ func Load() *Config {
 		// Rate limiting
 		RateLimitEnabled: getEnvAsBool("RATE_LIMIT_ENABLED", true),
 		RateLimitPerMin:  getEnvAsInt("RATE_LIMIT_PER_MIN", 100),
+
+		// Frontend
+		FrontendURL: getEnv("FRONTEND_URL", "http://localhost:3000"),
+
+		// Mail
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		MailFrom:     getEnv("MAIL_FROM", "Meal Planner <no-reply@mealplanner.local>"),
+
+		// Storage
+		StorageDriver:  getEnv("STORAGE_DRIVER", "local"),
+		StorageDir:     getEnv("STORAGE_DIR", "./uploads"),
+		StorageBaseURL: getEnv("STORAGE_BASE_URL", "/uploads"),
+
+		// Similar recipes
+		SimilarityJobEnabled:     getEnvAsBool("SIMILARITY_JOB_ENABLED", true),
+		SimilarityRefreshSeconds: getEnvAsInt("SIMILARITY_REFRESH_SECONDS", 60),
 	}
 }
 
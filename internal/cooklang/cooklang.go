@@ -0,0 +1,372 @@
+// Package cooklang reads and writes recipes in Cooklang, a plain-text recipe
+// markup (https://cooklang.org), and maps them onto the recipe model.
+//
+// A recipe is a sequence of steps separated by blank lines. Steps mark up
+// ingredients as @salt or @olive oil{2%tbsp}(extra virgin), cookware as
+// #pot or #frying pan{}, and timers as ~{10%minutes}. Metadata is given in
+// YAML front matter or as ">> key: value" lines, notes as lines starting
+// with ">" and sections as lines like "= Sauce". "--" starts a comment that
+// runs to the end of the line and "[- ... -]" is a block comment.
+package cooklang
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidUTF8 is returned for a source that is not UTF-8 text
+var ErrInvalidUTF8 = errors.New("cooklang source must be UTF-8 text")
+
+// ErrTooLarge is returned for a document over MaxSize
+var ErrTooLarge = errors.New("cooklang document must be at most 256 KB")
+
+// ErrUnterminatedFrontMatter is returned when the closing "---" of the
+// front matter is missing
+var ErrUnterminatedFrontMatter = errors.New("front matter is not closed by a --- line")
+
+// Recipe is a parsed Cooklang document
+type Recipe struct {
+	Metadata []Metadata
+	Sections []Section
+}
+
+// Metadata is one metadata entry. Keys of nested front matter maps are
+// joined with dots, as in "time.prep". Values holds one value for scalars
+// and one per item for lists.
+type Metadata struct {
+	Key    string
+	Values []string
+}
+
+// Section is a named group of steps. The steps before the first section
+// heading form a section without a name.
+type Section struct {
+	Name  string
+	Steps []Step
+}
+
+// Step is a paragraph of the recipe. Notes hold text only.
+type Step struct {
+	Note  bool
+	Items []Item
+}
+
+// ItemKind says what an item of a step is
+type ItemKind int
+
+const (
+	Text ItemKind = iota
+	Ingredient
+	Cookware
+	Timer
+)
+
+// Item is a run of text or a marked-up ingredient, cookware or timer
+type Item struct {
+	Kind ItemKind
+	// Text is the content of a text item
+	Text string
+
+	Name string
+	// Quantity is as written, e.g. "2", "1/2" or "a pinch"; Unit follows
+	// the % in the braces
+	Quantity string
+	Unit     string
+	// Note is an ingredient's preparation, written in parentheses after it
+	Note string
+	// Optional is set by @?name and Reference by @&name, which refers back
+	// to an ingredient used in an earlier step
+	Optional  bool
+	Reference bool
+}
+
+// Get returns the first values of the metadata entry with one of the keys.
+// Keys are matched without regard to case, spaces, dashes or underscores.
+func (r *Recipe) Get(keys ...string) ([]string, bool) {
+	for _, key := range keys {
+		for _, m := range r.Metadata {
+			if normalizeKey(m.Key) == normalizeKey(key) {
+				return m.Values, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// normalizeKey folds "Prep Time", "prep_time" and "prep-time" together
+func normalizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '_' || r == '-' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, key)
+}
+
+// Parse parses a Cooklang document. It is lenient like other Cooklang
+// tools: markup it cannot read is kept as text.
+func Parse(src []byte) (*Recipe, error) {
+	if !utf8.Valid(src) {
+		return nil, ErrInvalidUTF8
+	}
+	text := strings.ReplaceAll(string(src), "\r\n", "\n")
+	text = strings.TrimPrefix(text, "\ufeff")
+
+	recipe := &Recipe{}
+	body, err := readFrontMatter(text, recipe)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{recipe: recipe}
+	for _, line := range strings.Split(stripBlockComments(body), "\n") {
+		p.line(line)
+	}
+	p.endStep()
+	return recipe, nil
+}
+
+// parser collects the lines of the step being read
+type parser struct {
+	recipe *Recipe
+	lines  []string
+	note   bool
+}
+
+func (p *parser) line(raw string) {
+	if strings.TrimSpace(raw) == "" {
+		p.endStep()
+		return
+	}
+	line := strings.TrimSpace(stripLineComment(raw))
+	if line == "" {
+		// A line holding only a comment does not end the step
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(line, ">>"):
+		key, value, _ := strings.Cut(strings.TrimSpace(line[2:]), ":")
+		if key = strings.TrimSpace(key); key != "" {
+			p.recipe.Metadata = append(p.recipe.Metadata, Metadata{Key: key, Values: []string{strings.TrimSpace(value)}})
+		}
+	case strings.HasPrefix(line, ">"):
+		if !p.note {
+			p.endStep()
+			p.note = true
+		}
+		p.lines = append(p.lines, strings.TrimSpace(line[1:]))
+	case strings.HasPrefix(line, "="):
+		p.endStep()
+		p.recipe.Sections = append(p.recipe.Sections, Section{Name: strings.TrimSpace(strings.Trim(line, "="))})
+	default:
+		if p.note {
+			p.endStep()
+		}
+		p.lines = append(p.lines, line)
+	}
+}
+
+func (p *parser) endStep() {
+	if len(p.lines) > 0 {
+		text := strings.Join(p.lines, " ")
+		step := Step{Note: p.note}
+		if p.note {
+			step.Items = []Item{{Kind: Text, Text: unescape(text)}}
+		} else {
+			step.Items = parseStep(text)
+		}
+		if len(p.recipe.Sections) == 0 {
+			p.recipe.Sections = append(p.recipe.Sections, Section{})
+		}
+		section := &p.recipe.Sections[len(p.recipe.Sections)-1]
+		section.Steps = append(section.Steps, step)
+	}
+	p.lines = nil
+	p.note = false
+}
+
+// stripBlockComments removes [- ... -] comments, keeping the line breaks
+// inside them so steps stay apart
+func stripBlockComments(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			b.WriteString(s[i : i+2])
+			i++
+		case strings.HasPrefix(s[i:], "[-"):
+			end := strings.Index(s[i+2:], "-]")
+			comment := s[i+2:]
+			if end >= 0 {
+				comment = s[i+2 : i+2+end]
+				i += end + 3
+			} else {
+				i = len(s)
+			}
+			b.WriteString(strings.Repeat("\n", strings.Count(comment, "\n")))
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// stripLineComment removes a "--" comment from the end of a line
+func stripLineComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(line[i:], "--") {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseStep splits the text of a step into text and markup items
+func parseStep(s string) []Item {
+	var items []Item
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			items = append(items, Item{Kind: Text, Text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) {
+			r, size := utf8.DecodeRuneInString(s[i+1:])
+			text.WriteRune(r)
+			i += 1 + size
+			continue
+		}
+		if c == '@' || c == '#' || c == '~' {
+			if item, n, ok := parseMarkup(s[i:]); ok {
+				flush()
+				items = append(items, item)
+				i += n
+				continue
+			}
+		}
+		text.WriteByte(c)
+		i++
+	}
+	flush()
+	return items
+}
+
+// parseMarkup reads an ingredient, cookware or timer at the start of s and
+// returns it with the number of bytes read
+func parseMarkup(s string) (Item, int, bool) {
+	item := Item{Kind: map[byte]ItemKind{'@': Ingredient, '#': Cookware, '~': Timer}[s[0]]}
+	i := 1
+	if item.Kind == Ingredient {
+		for ; i < len(s) && strings.IndexByte("?&-+", s[i]) >= 0; i++ {
+			switch s[i] {
+			case '?':
+				item.Optional = true
+			case '&':
+				item.Reference = true
+			}
+		}
+	}
+
+	rest := s[i:]
+	braced := false
+	if end := multiWordName(rest); end >= 0 {
+		item.Name, i, braced = rest[:end], i+end, true
+	} else {
+		end := singleWordName(rest)
+		item.Name, i = rest[:end], i+end
+		braced = i < len(s) && s[i] == '{'
+	}
+	item.Name = strings.TrimSpace(item.Name)
+	// "Name|alias" shows the alias in the text; the name is what we keep
+	if name, _, ok := strings.Cut(item.Name, "|"); ok {
+		item.Name = strings.TrimSpace(name)
+	}
+
+	if braced {
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return Item{}, 0, false
+		}
+		amount := s[i+1 : i+end]
+		i += end + 1
+		qty, unit, _ := strings.Cut(amount, "%")
+		qty = strings.TrimSpace(qty)
+		// "=" fixes a quantity when scaling and "*" marks it as scaled in
+		// older documents; neither matters here
+		qty = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(qty, "="), "*"))
+		item.Quantity, item.Unit = qty, strings.TrimSpace(unit)
+	}
+	if item.Name == "" && (item.Kind != Timer || !braced) {
+		return Item{}, 0, false
+	}
+
+	if item.Kind == Ingredient && i < len(s) && s[i] == '(' {
+		if end := strings.IndexByte(s[i:], ')'); end >= 0 {
+			item.Note = strings.TrimSpace(s[i+1 : i+end])
+			i += end + 1
+		}
+	}
+	return item, i, true
+}
+
+// multiWordName returns the length of a name ended by braces, as in
+// "olive oil{...}", or -1 when the braces do not follow on directly
+func multiWordName(s string) int {
+	for i, r := range s {
+		switch {
+		case r == '{':
+			return i
+		case r == ' ' || r == '|' || r == '\'' || r == '-' || isWordRune(r):
+		default:
+			return -1
+		}
+	}
+	return -1
+}
+
+// singleWordName returns the length of the word at the start of s
+func singleWordName(s string) int {
+	for i, r := range s {
+		if isWordRune(r) {
+			continue
+		}
+		// Hyphens join words, as in "@all-purpose"
+		if r == '-' && i > 0 {
+			if next, _ := utf8.DecodeRuneInString(s[i+1:]); isWordRune(next) {
+				continue
+			}
+		}
+		return i
+	}
+	return len(s)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// unescape drops the backslash from escaped characters
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,268 @@
+package cooklang
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/meal-planner/backend/internal/models"
+)
+
+func TestParseStep(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Item
+	}{
+		{
+			in: "Add @salt and @ground black pepper{} to taste.",
+			want: []Item{
+				{Kind: Text, Text: "Add "},
+				{Kind: Ingredient, Name: "salt"},
+				{Kind: Text, Text: " and "},
+				{Kind: Ingredient, Name: "ground black pepper"},
+				{Kind: Text, Text: " to taste."},
+			},
+		},
+		{
+			in: "Whisk @eggs{3} with @milk{250%ml}(cold) in a #bowl.",
+			want: []Item{
+				{Kind: Text, Text: "Whisk "},
+				{Kind: Ingredient, Name: "eggs", Quantity: "3"},
+				{Kind: Text, Text: " with "},
+				{Kind: Ingredient, Name: "milk", Quantity: "250", Unit: "ml", Note: "cold"},
+				{Kind: Text, Text: " in a "},
+				{Kind: Cookware, Name: "bowl"},
+				{Kind: Text, Text: "."},
+			},
+		},
+		{
+			in: "Fry in a #large frying pan{} for ~{10%minutes}.",
+			want: []Item{
+				{Kind: Text, Text: "Fry in a "},
+				{Kind: Cookware, Name: "large frying pan"},
+				{Kind: Text, Text: " for "},
+				{Kind: Timer, Quantity: "10", Unit: "minutes"},
+				{Kind: Text, Text: "."},
+			},
+		},
+		{
+			in: "Top with @?parsley and @&all-purpose flour{=1/2*%cup}.",
+			want: []Item{
+				{Kind: Text, Text: "Top with "},
+				{Kind: Ingredient, Name: "parsley", Optional: true},
+				{Kind: Text, Text: " and "},
+				{Kind: Ingredient, Name: "all-purpose flour", Quantity: "1/2", Unit: "cup", Reference: true},
+				{Kind: Text, Text: "."},
+			},
+		},
+		{
+			in: "Use @tomatoes|tomato{2}, email me @ home, \\@not markup.",
+			want: []Item{
+				{Kind: Text, Text: "Use "},
+				{Kind: Ingredient, Name: "tomatoes", Quantity: "2"},
+				{Kind: Text, Text: ", email me @ home, @not markup."},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := parseStep(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseStep(%q) =\n%+v\nwant\n%+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	src := `---
+title: Pancakes
+tags: [breakfast, "quick, easy"]
+servings: 4
+time:
+  prep: 10 minutes
+description: |
+  Fluffy pancakes.
+  Serve warm.
+---
+-- a comment line
+Whisk @flour{200%g} and @eggs{2}. -- trailing comment
+Rest for ~{15%minutes}.
+
+[- a block
+comment -]
+> Use buttermilk if you have it.
+
+= Cooking
+Fry in a #pan.
+>> source: grandma
+`
+	recipe, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	wantMeta := []Metadata{
+		{Key: "title", Values: []string{"Pancakes"}},
+		{Key: "tags", Values: []string{"breakfast", "quick, easy"}},
+		{Key: "servings", Values: []string{"4"}},
+		{Key: "time.prep", Values: []string{"10 minutes"}},
+		{Key: "description", Values: []string{"Fluffy pancakes.\nServe warm."}},
+		{Key: "source", Values: []string{"grandma"}},
+	}
+	if !reflect.DeepEqual(recipe.Metadata, wantMeta) {
+		t.Errorf("Metadata =\n%+v\nwant\n%+v", recipe.Metadata, wantMeta)
+	}
+
+	if len(recipe.Sections) != 2 {
+		t.Fatalf("got %d sections, want 2", len(recipe.Sections))
+	}
+	first, second := recipe.Sections[0], recipe.Sections[1]
+	if len(first.Steps) != 2 || first.Steps[0].Note || !first.Steps[1].Note {
+		t.Fatalf("first section steps = %+v, want a step and a note", first.Steps)
+	}
+	if got, want := stepText(first.Steps[0].Items), "Whisk flour and eggs. Rest for 15 minutes."; got != want {
+		t.Errorf("first step = %q, want %q", got, want)
+	}
+	if got, want := stepText(first.Steps[1].Items), "Use buttermilk if you have it."; got != want {
+		t.Errorf("note = %q, want %q", got, want)
+	}
+	if second.Name != "Cooking" || len(second.Steps) != 1 {
+		t.Errorf("second section = %+v, want Cooking with one step", second)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse([]byte("---\ntitle: x\n\nMix @a.")); err != ErrUnterminatedFrontMatter {
+		t.Errorf("unclosed front matter: error = %v, want %v", err, ErrUnterminatedFrontMatter)
+	}
+	if _, err := Parse([]byte{'@', 0xff}); err != ErrInvalidUTF8 {
+		t.Errorf("invalid UTF-8: error = %v, want %v", err, ErrInvalidUTF8)
+	}
+}
+
+func TestImport(t *testing.T) {
+	src := `>> title: Tomato Soup
+>> course: Main course
+>> servings: 2|4
+>> time: 1 hour
+>> tags: soup, vegetarian
+>> difficulty: easy
+
+@tomatoes{800%grams}, @onion{1}(chopped), @salt
+
+Soften the @onion{1} in @olive oil{2%tbsp}. Add @&tomatoes{} and @salt{a pinch}.
+
+= To serve
+Blend until smooth.
+
+> Freezes well.
+`
+	res, err := Import(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	d := res.Draft
+
+	if d.Name != "Tomato Soup" || d.Category != "Dinner" || d.Servings != 2 || d.Difficulty != "Easy" {
+		t.Errorf("draft = %q, %q, %d servings, %q", d.Name, d.Category, d.Servings, d.Difficulty)
+	}
+	if d.PrepTime != 0 || d.CookTime != 60 {
+		t.Errorf("times = %d prep, %d cook, want 0, 60", d.PrepTime, d.CookTime)
+	}
+	if want := []string{"soup", "vegetarian"}; !reflect.DeepEqual(d.Tags, want) {
+		t.Errorf("tags = %v, want %v", d.Tags, want)
+	}
+	if d.Description != "Freezes well." {
+		t.Errorf("description = %q", d.Description)
+	}
+
+	wantIngredients := []models.RecipeIngredient{
+		{Name: "tomatoes", Quantity: 800, Unit: "g"},
+		{Name: "onion", Quantity: 2, Note: "chopped"},
+		{Name: "salt"},
+		{Name: "olive oil", Quantity: 2, Unit: "tbsp"},
+		{Name: "salt", Note: "a pinch"},
+	}
+	if !reflect.DeepEqual(d.Ingredients, wantIngredients) {
+		t.Errorf("ingredients =\n%+v\nwant\n%+v", d.Ingredients, wantIngredients)
+	}
+
+	wantInstructions := []string{
+		"Soften the onion in olive oil. Add tomatoes and salt.",
+		"To serve: Blend until smooth.",
+	}
+	if !reflect.DeepEqual(d.Instructions, wantInstructions) {
+		t.Errorf("instructions = %q, want %q", d.Instructions, wantInstructions)
+	}
+	if len(res.Warnings) != 1 || !strings.HasPrefix(res.Warnings[0], "prep time, cook time") {
+		t.Errorf("warnings = %q", res.Warnings)
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	recipe := &models.Recipe{
+		Name:        "Garlic Butter Pasta",
+		Description: "Weeknight pasta: quick & cheap.\nServes a crowd.",
+		Category:    "Dinner",
+		Cuisine:     "Italian",
+		Difficulty:  "Easy",
+		Servings:    4,
+		PrepTime:    5,
+		CookTime:    15,
+		Tags:        models.StringList{"pasta", "quick"},
+		Ingredients: models.RecipeIngredients{
+			{Name: "spaghetti", Quantity: 400, Unit: "g"},
+			{Name: "butter", Quantity: 2.5, Unit: "tbsp"},
+			{Name: "garlic", Quantity: 3, Unit: "clove", Note: "minced"},
+			{Name: "salt", Note: "to taste"},
+			{Name: "parmesan", Quantity: 0.5, QuantityMax: 0.75, Unit: "cup"},
+		},
+		Instructions: models.StringList{
+			"Cook the spaghetti in salted water -- about 10 minutes.",
+			"Melt the butter, add the garlic (don't let it brown) and toss with the pasta.",
+			"Season with #1 sea salt @ the table.",
+		},
+	}
+
+	out := Export(recipe)
+	parsed, err := Parse(out)
+	if err != nil {
+		t.Fatalf("Parse(Export()) error = %v\n%s", err, out)
+	}
+	d := Draft(parsed).Draft
+
+	if d.Name != recipe.Name || d.Description != recipe.Description || d.Category != recipe.Category ||
+		d.Cuisine != recipe.Cuisine || d.Difficulty != recipe.Difficulty || d.Servings != recipe.Servings ||
+		d.PrepTime != recipe.PrepTime || d.CookTime != recipe.CookTime {
+		t.Errorf("metadata did not round-trip:\n%s\n%+v", out, d)
+	}
+	if !reflect.DeepEqual(d.Tags, []string(recipe.Tags)) {
+		t.Errorf("tags = %v, want %v", d.Tags, recipe.Tags)
+	}
+	if !reflect.DeepEqual(d.Instructions, []string(recipe.Instructions)) {
+		t.Errorf("instructions =\n%q\nwant\n%q\n%s", d.Instructions, recipe.Instructions, out)
+	}
+
+	// Unmentioned ingredients come first, then in order of mention
+	want := models.RecipeIngredients{recipe.Ingredients[4], recipe.Ingredients[0], recipe.Ingredients[1], recipe.Ingredients[2], recipe.Ingredients[3]}
+	if !reflect.DeepEqual(models.RecipeIngredients(d.Ingredients), want) {
+		t.Errorf("ingredients =\n%+v\nwant\n%+v\n%s", d.Ingredients, want, out)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Tomato Soup", "Tomato Soup.cook"},
+		{"Mac & Cheese: The Best?", "Mac & Cheese The Best.cook"},
+		{"../etc/passwd", "etc passwd.cook"},
+		{"  ", "recipe.cook"},
+	}
+
+	for _, tt := range tests {
+		if got := FileName(tt.in); got != tt.want {
+			t.Errorf("FileName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
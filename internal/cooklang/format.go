@@ -0,0 +1,136 @@
+package cooklang
+
+import (
+	"regexp"
+	"strings"
+)
+
+var spacePattern = regexp.MustCompile(`\s+`)
+
+// Format writes a recipe as a Cooklang document, with its metadata as YAML
+// front matter. Parsing the output gives back the same recipe.
+func Format(recipe *Recipe) []byte {
+	var b strings.Builder
+	writeFrontMatter(&b, recipe.Metadata)
+
+	paragraph := func() {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+	}
+	for _, section := range recipe.Sections {
+		if section.Name != "" {
+			paragraph()
+			b.WriteString("= " + section.Name + "\n")
+		}
+		for _, step := range section.Steps {
+			paragraph()
+			if step.Note {
+				b.WriteString("> " + escapeText(stepText(step.Items), false) + "\n")
+				continue
+			}
+			b.WriteString(formatStep(step.Items) + "\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+func formatStep(items []Item) string {
+	var b strings.Builder
+	for i, item := range items {
+		if item.Kind != Text {
+			next := ""
+			if i+1 < len(items) && items[i+1].Kind == Text {
+				next = items[i+1].Text
+			}
+			b.WriteString(formatMarkup(item, next))
+			continue
+		}
+		text := escapeText(item.Text, i == 0)
+		if i == len(items)-1 {
+			text = strings.TrimRight(text, " ")
+		}
+		// A parenthesis right after an ingredient would be read as its note
+		if i > 0 && items[i-1].Kind == Ingredient && strings.HasPrefix(text, "(") {
+			text = `\` + text
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+// formatMarkup writes an ingredient, cookware or timer. next is the text
+// that follows it, which decides whether a one-word name needs braces.
+func formatMarkup(item Item, next string) string {
+	var b strings.Builder
+	b.WriteByte("@#~"[item.Kind-Ingredient])
+	if item.Optional {
+		b.WriteByte('?')
+	}
+	if item.Reference {
+		b.WriteByte('&')
+	}
+	b.WriteString(item.Name)
+
+	bare := item.Kind != Timer && item.Quantity == "" && item.Unit == "" &&
+		item.Name != "" && singleWordName(item.Name) == len(item.Name) &&
+		singleWordName(item.Name+next) == len(item.Name) && !strings.HasPrefix(next, "{")
+	if !bare {
+		b.WriteString("{" + item.Quantity)
+		if item.Unit != "" {
+			b.WriteString("%" + item.Unit)
+		}
+		b.WriteString("}")
+	}
+	if item.Kind == Ingredient && item.Note != "" {
+		b.WriteString("(" + item.Note + ")")
+	}
+	return b.String()
+}
+
+// escapeText escapes the characters of plain text that would be read as
+// markup or comments, and puts it on one line
+func escapeText(s string, stepStart bool) string {
+	s = spacePattern.ReplaceAllString(s, " ")
+	if stepStart {
+		s = strings.TrimLeft(s, " ")
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '@' || c == '#' || c == '~' || c == '\\':
+			b.WriteByte('\\')
+		case c == '-' && (strings.HasPrefix(s[i+1:], "-") || strings.HasPrefix(s[i+1:], "]")):
+			// "--" and "-]" are written as "-\-" and "-\]"
+			b.WriteByte(c)
+			b.WriteByte('\\')
+			continue
+		case c == '[' && strings.HasPrefix(s[i+1:], "-"):
+			b.WriteByte(c)
+			b.WriteByte('\\')
+			continue
+		case i == 0 && stepStart && (c == '>' || c == '='):
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// stepText renders a step as plain text: ingredients and cookware by name
+// and timers by their duration
+func stepText(items []Item) string {
+	var b strings.Builder
+	for _, item := range items {
+		switch {
+		case item.Kind == Text:
+			b.WriteString(item.Text)
+		case item.Kind == Timer && item.Quantity != "":
+			b.WriteString(strings.TrimSpace(item.Quantity + " " + item.Unit))
+		default:
+			b.WriteString(item.Name)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
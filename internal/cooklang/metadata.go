@@ -0,0 +1,253 @@
+package cooklang
+
+import (
+	"strconv"
+	"strings"
+)
+
+// readFrontMatter reads YAML front matter at the start of text into the
+// recipe's metadata and returns the rest of the document. Only the YAML
+// that recipe metadata needs is understood: scalars, flow and block lists,
+// block scalars and nested maps.
+func readFrontMatter(text string, recipe *Recipe) (string, error) {
+	lines := strings.Split(text, "\n")
+	start := 0
+	for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	if start == len(lines) || strings.TrimSpace(lines[start]) != "---" {
+		return text, nil
+	}
+
+	end := -1
+	for i := start + 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return "", ErrUnterminatedFrontMatter
+	}
+
+	recipe.Metadata = append(recipe.Metadata, parseYAML(lines[start+1:end])...)
+	return strings.Join(lines[end+1:], "\n"), nil
+}
+
+// yamlLine is a front matter line without its indentation
+type yamlLine struct {
+	indent int
+	text   string
+}
+
+func parseYAML(raw []string) []Metadata {
+	var lines []yamlLine
+	for _, line := range raw {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			// Blank lines still matter inside block scalars
+			lines = append(lines, yamlLine{indent: -1})
+			continue
+		}
+		lines = append(lines, yamlLine{indent: len(line) - len(strings.TrimLeft(line, " \t")), text: trimmed})
+	}
+	var meta []Metadata
+	parseYAMLMap(lines, 0, "", &meta)
+	return meta
+}
+
+// parseYAMLMap reads the map entries at the indentation of its first line
+// and returns the number of lines read
+func parseYAMLMap(lines []yamlLine, start int, prefix string, meta *[]Metadata) int {
+	i := start
+	for i < len(lines) && lines[i].indent < 0 {
+		i++
+	}
+	if i == len(lines) {
+		return i - start
+	}
+	indent := lines[start].indent
+	if indent < 0 {
+		indent = lines[i].indent
+	}
+
+	for i < len(lines) {
+		line := lines[i]
+		if line.indent < 0 {
+			i++
+			continue
+		}
+		if line.indent < indent {
+			break
+		}
+		key, value, ok := strings.Cut(line.text, ":")
+		key = unquote(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		i++
+		if !ok || key == "" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch {
+		case value == "|" || value == ">" || strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+			block, n := yamlBlockScalar(lines[i:], indent, value[0] == '>')
+			*meta = append(*meta, Metadata{Key: key, Values: []string{block}})
+			i += n
+		case strings.HasPrefix(value, "["):
+			*meta = append(*meta, Metadata{Key: key, Values: flowList(value)})
+		case value != "":
+			*meta = append(*meta, Metadata{Key: key, Values: []string{yamlScalar(value)}})
+		default:
+			next := i
+			for next < len(lines) && lines[next].indent < 0 {
+				next++
+			}
+			switch {
+			case next < len(lines) && strings.HasPrefix(lines[next].text, "- ") && lines[next].indent >= indent:
+				var items []string
+				for next < len(lines) && (lines[next].indent < 0 || strings.HasPrefix(lines[next].text, "-") && lines[next].indent >= indent) {
+					if lines[next].indent >= 0 {
+						items = append(items, yamlScalar(strings.TrimSpace(strings.TrimPrefix(lines[next].text, "-"))))
+					}
+					next++
+				}
+				*meta = append(*meta, Metadata{Key: key, Values: items})
+				i = next
+			case next < len(lines) && lines[next].indent > indent:
+				i += parseYAMLMap(lines, next, key, meta) + next - i
+			default:
+				*meta = append(*meta, Metadata{Key: key, Values: []string{""}})
+			}
+		}
+	}
+	return i - start
+}
+
+// yamlBlockScalar reads the lines of a "|" or ">" block scalar, which are
+// indented more than its key
+func yamlBlockScalar(lines []yamlLine, keyIndent int, folded bool) (string, int) {
+	var parts []string
+	n := 0
+	for ; n < len(lines); n++ {
+		if lines[n].indent >= 0 && lines[n].indent <= keyIndent {
+			break
+		}
+		parts = append(parts, lines[n].text)
+	}
+	for len(parts) > 0 && parts[len(parts)-1] == "" {
+		parts = parts[:len(parts)-1]
+	}
+	if !folded {
+		return strings.Join(parts, "\n"), n
+	}
+	// Folded scalars join lines with spaces and keep blank lines as breaks
+	var b strings.Builder
+	for i, part := range parts {
+		switch {
+		case part == "":
+			b.WriteString("\n")
+		case i > 0 && parts[i-1] != "":
+			b.WriteString(" " + part)
+		default:
+			b.WriteString(part)
+		}
+	}
+	return b.String(), n
+}
+
+// flowList reads a list like [a, "b, c"]
+func flowList(value string) []string {
+	value = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"))
+	var items []string
+	var quote byte
+	begin := 0
+	for i := 0; i <= len(value); i++ {
+		if i < len(value) {
+			c := value[i]
+			switch {
+			case quote != 0:
+				if c == '\\' && quote == '"' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+				continue
+			case c == '"' || c == '\'':
+				quote = c
+				continue
+			case c != ',':
+				continue
+			}
+		}
+		if item := strings.TrimSpace(value[begin:i]); item != "" {
+			items = append(items, unquote(item))
+		}
+		begin = i + 1
+	}
+	return items
+}
+
+// yamlScalar reads a plain or quoted scalar, dropping a trailing comment
+func yamlScalar(value string) string {
+	if value != "" && (value[0] == '"' || value[0] == '\'') {
+		return unquote(value)
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+func unquote(s string) string {
+	if len(s) < 2 {
+		return s
+	}
+	switch {
+	case s[0] == '"' && s[len(s)-1] == '"':
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+		return s[1 : len(s)-1]
+	case s[0] == '\'' && s[len(s)-1] == '\'':
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
+
+// writeFrontMatter writes metadata as YAML front matter
+func writeFrontMatter(b *strings.Builder, meta []Metadata) {
+	if len(meta) == 0 {
+		return
+	}
+	b.WriteString("---\n")
+	for _, m := range meta {
+		b.WriteString(yamlQuote(m.Key) + ":")
+		switch len(m.Values) {
+		case 0:
+			b.WriteString(" []")
+		case 1:
+			b.WriteString(" " + yamlQuote(m.Values[0]))
+		default:
+			quoted := make([]string, len(m.Values))
+			for i, v := range m.Values {
+				quoted[i] = yamlQuote(v)
+			}
+			b.WriteString(" [" + strings.Join(quoted, ", ") + "]")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("---\n")
+}
+
+// yamlQuote quotes a scalar that YAML would otherwise read differently
+func yamlQuote(s string) string {
+	if s == "" || s != strings.TrimSpace(s) ||
+		strings.ContainsAny(s, "\n\t\"\\,[]{}") || strings.Contains(s, ": ") || strings.Contains(s, " #") ||
+		strings.IndexByte("-?:#&*!|>'%@`", s[0]) >= 0 || strings.HasSuffix(s, ":") {
+		return strconv.Quote(s)
+	}
+	return s
+}
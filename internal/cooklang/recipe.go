@@ -0,0 +1,444 @@
+package cooklang
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/meal-planner/backend/internal/ingredients"
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/recipeimport"
+)
+
+// SourceCooklang is the import source of drafts read from Cooklang
+const SourceCooklang = "cooklang"
+
+// MaxSize limits the size of a Cooklang document
+const MaxSize = 256 << 10
+
+var (
+	servingsPattern = regexp.MustCompile(`\d+`)
+)
+
+// Import reads a Cooklang document into a recipe draft. Metadata fills the
+// recipe fields, marked-up ingredients become the ingredient list and the
+// steps become the instructions, rendered as plain text.
+func Import(r io.Reader) (*recipeimport.Result, error) {
+	src, err := io.ReadAll(io.LimitReader(r, MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(src) > MaxSize {
+		return nil, ErrTooLarge
+	}
+	recipe, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return Draft(recipe), nil
+}
+
+// Draft maps a parsed recipe onto a recipe draft, warning about metadata
+// that is missing or could not be read
+func Draft(recipe *Recipe) *recipeimport.Result {
+	res := &recipeimport.Result{Source: SourceCooklang, Warnings: []string{}}
+	d := &res.Draft
+	warn := func(format string, args ...interface{}) {
+		res.Warnings = append(res.Warnings, fmt.Sprintf(format, args...))
+	}
+	meta := func(keys ...string) string {
+		values, _ := recipe.Get(keys...)
+		return strings.TrimSpace(strings.Join(values, ", "))
+	}
+
+	if d.Name = meta("title", "name"); d.Name == "" {
+		warn("title: not found")
+	}
+	d.Description = meta("description", "introduction")
+	d.Cuisine = meta("cuisine")
+	d.SourceURL = meta("source.url", "source", "url")
+	if image := meta("image", "picture"); strings.HasPrefix(image, "https://") || strings.HasPrefix(image, "http://") {
+		d.ImageURL = image
+	}
+
+	if course := meta("course", "category"); course == "" {
+		warn("course: not found")
+	} else if category, ok := recipeimport.MatchCategory(course); ok {
+		d.Category = category
+	} else {
+		warn("course: %q does not match a known category", course)
+	}
+
+	if difficulty := meta("difficulty"); difficulty != "" {
+		for _, option := range models.RecipeDifficulties {
+			if strings.EqualFold(option, difficulty) {
+				d.Difficulty = option
+			}
+		}
+		if d.Difficulty == "" {
+			warn("difficulty: %q is not one of %s", difficulty, strings.Join(models.RecipeDifficulties, ", "))
+		}
+	}
+
+	if servings := meta("servings", "serves", "yield"); servings != "" {
+		// Scaled documents list several servings, as in "2|4"
+		if n, err := strconv.Atoi(servingsPattern.FindString(servings)); err == nil && n >= 1 && n <= 100 {
+			d.Servings = n
+		} else {
+			warn("servings: could not read a number of servings from %q", servings)
+		}
+	}
+
+	draftTimes(recipe, res, warn)
+
+	d.Tags = []string{}
+	if values, ok := recipe.Get("tags", "tag", "keywords"); ok {
+		for _, value := range values {
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					d.Tags = append(d.Tags, tag)
+				}
+			}
+		}
+	}
+
+	d.Ingredients = []models.RecipeIngredient{}
+	d.Instructions = []string{}
+	var notes []string
+	for _, section := range recipe.Sections {
+		heading := section.Name
+		for _, step := range section.Steps {
+			if step.Note {
+				notes = append(notes, stepText(step.Items))
+				continue
+			}
+			for _, item := range step.Items {
+				if item.Kind == Ingredient {
+					addIngredient(d, item, warn)
+				}
+			}
+			if ingredientList(step.Items) {
+				continue
+			}
+			text := stepText(step.Items)
+			if heading != "" {
+				text = heading + ": " + text
+				heading = ""
+			}
+			d.Instructions = append(d.Instructions, text)
+		}
+	}
+	if len(notes) > 0 {
+		d.Description = strings.TrimSpace(d.Description + "\n\n" + strings.Join(notes, "\n\n"))
+	}
+	if len(d.Ingredients) == 0 {
+		warn("ingredients: none marked up with @")
+	}
+	if len(d.Instructions) == 0 {
+		warn("steps: not found")
+	}
+	return res
+}
+
+// draftTimes fills prep and cook time, using the total time as the cook
+// time when neither is given
+func draftTimes(recipe *Recipe, res *recipeimport.Result, warn func(string, ...interface{})) {
+	minutes := func(name string, keys ...string) (int, bool) {
+		values, _ := recipe.Get(keys...)
+		value := strings.TrimSpace(strings.Join(values, " "))
+		if value == "" {
+			return 0, false
+		}
+		m, err := recipeimport.ParseTime(value)
+		if err != nil {
+			warn("%s: %v", name, err)
+			return 0, false
+		}
+		return m, true
+	}
+
+	prep, hasPrep := minutes("prep time", "prep time", "time.prep", "prep")
+	cook, hasCook := minutes("cook time", "cook time", "time.cook", "cook")
+	total, hasTotal := minutes("time", "time", "total time", "time.total", "duration")
+	switch {
+	case hasTotal && !hasPrep && !hasCook:
+		cook = total
+		warn("prep time, cook time: not found; time used as cook time")
+	case hasTotal && hasPrep && !hasCook && total >= prep:
+		cook = total - prep
+	case hasTotal && hasCook && !hasPrep && total >= cook:
+		prep = total - cook
+	}
+	res.Draft.PrepTime = prep
+	res.Draft.CookTime = cook
+}
+
+// addIngredient adds a marked-up ingredient to the draft. An ingredient used
+// again in the same unit is added to the earlier amount, and one used again
+// without an amount is not listed twice.
+func addIngredient(d *recipeimport.Draft, item Item, warn func(string, ...interface{})) {
+	ing := models.RecipeIngredient{Name: item.Name, Note: item.Note}
+	if item.Quantity != "" {
+		qty, qtyMax, ok := ingredients.ParseQuantity(item.Quantity)
+		if ok {
+			ing.Quantity, ing.QuantityMax = qty, qtyMax
+		} else {
+			// Amounts like "a pinch" are kept as written
+			ing.Note = joinNote(strings.TrimSpace(item.Quantity+" "+item.Unit), ing.Note)
+		}
+	}
+	if item.Unit != "" && ing.Quantity > 0 {
+		if unit, ok := ingredients.NormalizeUnit(item.Unit); ok {
+			ing.Unit = unit
+		} else {
+			ing.Unit, ing.UnknownUnit = item.Unit, true
+			warn("ingredient: unrecognized unit %q for %q", item.Unit, item.Name)
+		}
+	}
+	if item.Optional {
+		ing.Note = joinNote(ing.Note, "optional")
+	}
+
+	for i := range d.Ingredients {
+		existing := &d.Ingredients[i]
+		if !strings.EqualFold(existing.Name, ing.Name) {
+			continue
+		}
+		switch {
+		case ing.Quantity == 0 && (ing.Note == "" || item.Reference):
+			return
+		case existing.Unit == ing.Unit && existing.Quantity > 0 && ing.Quantity > 0 &&
+			existing.QuantityMax == 0 && ing.QuantityMax == 0:
+			existing.Quantity += ing.Quantity
+			return
+		}
+	}
+	d.Ingredients = append(d.Ingredients, ing)
+}
+
+func joinNote(a, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	return a + ", " + b
+}
+
+// ingredientList reports whether a step only lists ingredients, as Export
+// writes for ingredients no instruction mentions
+func ingredientList(items []Item) bool {
+	found := false
+	for _, item := range items {
+		switch item.Kind {
+		case Ingredient:
+			found = true
+		case Text:
+			if strings.Trim(item.Text, " ,;") != "" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return found
+}
+
+// Export writes a recipe as a Cooklang document. Each ingredient is marked
+// up where an instruction first mentions it by name; ingredients that no
+// instruction mentions are listed in a first step of their own.
+func Export(recipe *models.Recipe) []byte {
+	doc := &Recipe{Metadata: exportMetadata(recipe)}
+
+	placed := make([]bool, len(recipe.Ingredients))
+	var steps []Step
+	for _, instruction := range recipe.Instructions {
+		steps = append(steps, Step{Items: markUpStep(instruction, recipe.Ingredients, placed)})
+	}
+
+	var list []Item
+	for i, ing := range recipe.Ingredients {
+		if placed[i] {
+			continue
+		}
+		if len(list) > 0 {
+			list = append(list, Item{Kind: Text, Text: ", "})
+		}
+		list = append(list, exportIngredient(ing))
+	}
+	if len(list) > 0 {
+		steps = append([]Step{{Items: list}}, steps...)
+	}
+
+	doc.Sections = []Section{{Steps: steps}}
+	return Format(doc)
+}
+
+func exportMetadata(recipe *models.Recipe) []Metadata {
+	var meta []Metadata
+	add := func(key string, values ...string) {
+		meta = append(meta, Metadata{Key: key, Values: values})
+	}
+	add("title", recipe.Name)
+	if recipe.Description != "" {
+		add("description", recipe.Description)
+	}
+	if recipe.Category != "" {
+		add("course", recipe.Category)
+	}
+	if recipe.Cuisine != "" {
+		add("cuisine", recipe.Cuisine)
+	}
+	if recipe.Difficulty != "" {
+		add("difficulty", recipe.Difficulty)
+	}
+	if recipe.Servings > 0 {
+		add("servings", strconv.Itoa(recipe.Servings))
+	}
+	if recipe.PrepTime > 0 {
+		add("prep time", fmt.Sprintf("%d minutes", recipe.PrepTime))
+	}
+	if recipe.CookTime > 0 {
+		add("cook time", fmt.Sprintf("%d minutes", recipe.CookTime))
+	}
+	if len(recipe.Tags) > 0 {
+		add("tags", recipe.Tags...)
+	}
+	if strings.HasPrefix(recipe.ImageURL, "https://") || strings.HasPrefix(recipe.ImageURL, "http://") {
+		add("image", recipe.ImageURL)
+	}
+	return meta
+}
+
+// markUpStep splits an instruction into text and the ingredients it
+// mentions by name that are not yet placed in an earlier step
+func markUpStep(text string, list models.RecipeIngredients, placed []bool) []Item {
+	type mention struct {
+		start, end int
+		index      int
+	}
+	var mentions []mention
+	overlaps := func(start, end int) bool {
+		for _, m := range mentions {
+			if start < m.end && m.start < end {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i, ing := range list {
+		name := exportName(ing.Name)
+		if placed[i] || name == "" || name != ing.Name {
+			continue
+		}
+		for offset := 0; offset < len(text); {
+			at := strings.Index(text[offset:], name)
+			if at < 0 {
+				break
+			}
+			start := offset + at
+			end := start + len(name)
+			offset = start + 1
+			if wordAt(text, start-1, true) || wordAt(text, end, false) || overlaps(start, end) {
+				continue
+			}
+			mentions = append(mentions, mention{start, end, i})
+			placed[i] = true
+			break
+		}
+	}
+
+	var items []Item
+	pos := 0
+	for len(mentions) > 0 {
+		next := 0
+		for i, m := range mentions {
+			if m.start < mentions[next].start {
+				next = i
+			}
+		}
+		m := mentions[next]
+		mentions = append(mentions[:next], mentions[next+1:]...)
+		if m.start > pos {
+			items = append(items, Item{Kind: Text, Text: text[pos:m.start]})
+		}
+		items = append(items, exportIngredient(list[m.index]))
+		pos = m.end
+	}
+	if pos < len(text) {
+		items = append(items, Item{Kind: Text, Text: text[pos:]})
+	}
+	return items
+}
+
+// wordAt reports whether the rune ending (before) or starting (after) at
+// byte i of s continues a word
+func wordAt(s string, i int, before bool) bool {
+	if i < 0 || i >= len(s) {
+		return false
+	}
+	var r rune
+	if before {
+		r, _ = utf8.DecodeLastRuneInString(s[:i+1])
+	} else {
+		r, _ = utf8.DecodeRuneInString(s[i:])
+	}
+	return isWordRune(r) || r == '-' || r == '\''
+}
+
+func exportIngredient(ing models.RecipeIngredient) Item {
+	item := Item{Kind: Ingredient, Name: exportName(ing.Name), Note: exportName(ing.Note)}
+	if ing.Quantity > 0 {
+		item.Quantity = exportQuantity(ing.Quantity)
+		if ing.QuantityMax > ing.Quantity {
+			item.Quantity += "-" + exportQuantity(ing.QuantityMax)
+		}
+		item.Unit = exportName(ing.Unit)
+	}
+	// A package size like the "14 oz" of "1 (14 oz) can" has no markup of
+	// its own, so it goes in the note
+	if ing.Size != nil {
+		size := strings.TrimSpace(ingredients.FormatQuantity(ing.Size.Quantity) + " " + ing.Size.Unit)
+		item.Note = joinNote(size, item.Note)
+	}
+	return item
+}
+
+// exportName removes the characters that end a name, amount or note early
+func exportName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if strings.ContainsRune("{}()%@#~\\", r) {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// exportQuantity writes a quantity as a number or simple fraction, which
+// Cooklang tools read; mixed numbers like "1 1/2" become decimals
+func exportQuantity(q float64) string {
+	if s := ingredients.FormatQuantity(q); !strings.Contains(s, " ") {
+		return s
+	}
+	return strconv.FormatFloat(math.Round(q*100)/100, 'f', -1, 64)
+}
+
+// FileName returns a .cook file name for a recipe name, keeping the name
+// readable but without characters that file systems reject
+func FileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < ' ' || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return ' '
+		}
+		return r
+	}, name)
+	name = strings.Trim(strings.Join(strings.Fields(name), " "), ". ")
+	if name == "" {
+		name = "recipe"
+	}
+	return name + ".cook"
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/meal-planner/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// backfillRecipeAllergens detects the allergens of recipes saved before
+// allergens were tracked. Recipes saved since always have them set.
+func backfillRecipeAllergens(db *gorm.DB) error {
+	var batch []models.Recipe
+	err := db.Where("allergens IS NULL").FindInBatches(&batch, 200, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			recipe := &batch[i]
+			recipe.DetectAllergens()
+			err := db.Model(recipe).UpdateColumns(map[string]interface{}{
+				"allergens":   recipe.Allergens,
+				"ingredients": recipe.Ingredients,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill recipe allergens: %w", err)
+	}
+	return nil
+}
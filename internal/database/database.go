@@ -2,6 +2,8 @@ package database
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/meal-planner/backend/internal/config"
 	"github.com/meal-planner/backend/internal/models"
@@ -29,8 +31,16 @@ func NewConnection(cfg *config.Config) (*gorm.DB, error) {
 		)
 	}
 
+	// Run database sessions in UTC
+	dsn = withUTCTimeZone(dsn)
+
 	// Configure GORM logger based on environment
-	gormConfig := &gorm.Config{}
+	gormConfig := &gorm.Config{
+		// Timestamps written by GORM (created_at, updated_at, deleted_at) are UTC
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
 	if cfg.IsDevelopment() {
 		gormConfig.Logger = logger.Default.LogMode(logger.Info)
 	} else {
@@ -43,6 +53,10 @@ func NewConnection(cfg *config.Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := registerUTCCallbacks(db); err != nil {
+		return nil, fmt.Errorf("failed to register database callbacks: %w", err)
+	}
+
 	// Get underlying SQL DB to configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -56,10 +70,52 @@ func NewConnection(cfg *config.Config) (*gorm.DB, error) {
 	return db, nil
 }
 
+// withUTCTimeZone sets the session time zone to UTC unless the DSN already
+// sets one. Both URL and key/value DSNs are supported.
+func withUTCTimeZone(dsn string) string {
+	if strings.Contains(strings.ToLower(dsn), "timezone=") {
+		return dsn
+	}
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		if strings.Contains(dsn, "?") {
+			return dsn + "&timezone=UTC"
+		}
+		return dsn + "?timezone=UTC"
+	}
+	return dsn + " TimeZone=UTC"
+}
+
 // Migrate runs database migrations
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&models.User{},
+		&models.OnboardingProgress{},
+		&models.Household{},
+		&models.HouseholdMember{},
+		&models.HouseholdInvitation{},
+		&models.EaterProfile{},
+		&models.NutritionGoals{},
+		&models.Recipe{},
+		&models.Favorite{},
+		&models.Review{},
+		&models.ReviewVote{},
+		&models.Food{},
+		&models.RecipeRevision{},
+		&models.Collection{},
+		&models.CollectionRecipe{},
+		&models.RecipeImage{},
+		&models.RecipeNeighbor{},
 		// Add other models here as they are created
 	)
+	if err != nil {
+		return err
+	}
+
+	if err := migrateRecipeSearch(db); err != nil {
+		return err
+	}
+	if err := backfillRecipeAllergens(db); err != nil {
+		return err
+	}
+	return backfillRecipeRevisions(db)
 }
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/meal-planner/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// backfillRecipeRevisions records a first revision for recipes saved before
+// revisions were tracked, so every recipe's history starts with its state at
+// the time of the upgrade
+func backfillRecipeRevisions(db *gorm.DB) error {
+	var batch []models.Recipe
+	err := db.Where("revision = 0").FindInBatches(&batch, 200, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			recipe := &batch[i]
+			snapshot := recipe.Snapshot()
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.Create(&models.RecipeRevision{
+					RecipeID:  recipe.ID,
+					Number:    1,
+					AuthorID:  recipe.CreatedByID,
+					Summary:   "Initial revision",
+					Snapshot:  &snapshot,
+					CreatedAt: recipe.UpdatedAt,
+				}).Error
+				if err != nil {
+					return err
+				}
+				return tx.Model(recipe).UpdateColumn("revision", 1).Error
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill recipe revisions: %w", err)
+	}
+	return nil
+}
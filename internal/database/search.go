@@ -0,0 +1,32 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// recipeSearchVector is the weighted full-text document for a recipe:
+// name (A), cuisine and tags (B), ingredient names (C), description (D).
+// Postgres keeps the generated column in sync on every write.
+const recipeSearchVector = `
+	setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+	setweight(to_tsvector('english', coalesce(cuisine, '') || ' ' || coalesce(tags::text, '')), 'B') ||
+	setweight(to_tsvector('english', coalesce(jsonb_path_query_array(ingredients, '$[*].name')::text, '')), 'C') ||
+	setweight(to_tsvector('english', coalesce(description, '')), 'D')`
+
+// migrateRecipeSearch adds the search_vector column and its GIN index, which
+// GORM cannot express through AutoMigrate
+func migrateRecipeSearch(db *gorm.DB) error {
+	statements := []string{
+		`ALTER TABLE recipes ADD COLUMN IF NOT EXISTS search_vector tsvector
+			GENERATED ALWAYS AS (` + recipeSearchVector + `) STORED`,
+		`CREATE INDEX IF NOT EXISTS idx_recipes_search_vector ON recipes USING GIN (search_vector)`,
+	}
+	for _, stmt := range statements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to migrate recipe search: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// registerUTCCallbacks makes every time value read from the database UTC.
+// The driver returns timestamptz values in the server's local zone; callers
+// convert to a user's zone only when rendering.
+func registerUTCCallbacks(db *gorm.DB) error {
+	return db.Callback().Query().After("gorm:after_query").Register("app:utc_times", normalizeTimesToUTC)
+}
+
+func normalizeTimesToUTC(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			normalizeStructTimes(db, reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		normalizeStructTimes(db, rv)
+	}
+}
+
+func normalizeStructTimes(db *gorm.DB, rv reflect.Value) {
+	if rv.Kind() != reflect.Struct || rv.Type() != db.Statement.Schema.ModelType || !rv.CanAddr() {
+		return
+	}
+
+	ctx := db.Statement.Context
+	for _, field := range db.Statement.Schema.Fields {
+		value, isZero := field.ValueOf(ctx, rv)
+		if isZero {
+			continue
+		}
+
+		switch t := value.(type) {
+		case time.Time:
+			_ = field.Set(ctx, rv, t.UTC())
+		case *time.Time:
+			if t != nil {
+				utc := t.UTC()
+				_ = field.Set(ctx, rv, &utc)
+			}
+		case gorm.DeletedAt:
+			if t.Valid {
+				_ = field.Set(ctx, rv, gorm.DeletedAt{Time: t.Time.UTC(), Valid: true})
+			}
+		}
+	}
+}
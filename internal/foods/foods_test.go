@@ -0,0 +1,284 @@
+package foods
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/meal-planner/backend/internal/ingredients"
+	"github.com/meal-planner/backend/internal/models"
+)
+
+var testFoods = []models.Food{
+	{ID: "butter", Name: "Butter, salted", DataType: models.FoodDataSRLegacy},
+	{ID: "egg", Name: "Egg, whole, raw, fresh", DataType: models.FoodDataSRLegacy,
+		Nutrients: models.RecipeNutrition{Calories: 143, Protein: 12.6, Fat: 9.5},
+		Portions: models.FoodPortions{
+			{Amount: 1, Unit: "large", Grams: 50},
+			{Amount: 1, Unit: "medium", Grams: 44},
+			{Amount: 1, Unit: ingredients.Cup, Grams: 243},
+		}},
+	{ID: "egg-white", Name: "Egg, white, raw, fresh", DataType: models.FoodDataSRLegacy},
+	{ID: "eggplant", Name: "Eggplant, raw", DataType: models.FoodDataSRLegacy},
+	{ID: "flour", Name: "Wheat flour, white, all-purpose, enriched, bleached", DataType: models.FoodDataSRLegacy,
+		Nutrients: models.RecipeNutrition{Calories: 364, Protein: 10.3, Carbohydrates: 76.3, Fat: 1}},
+	{ID: "garlic", Name: "Garlic, raw", DataType: models.FoodDataSRLegacy,
+		Nutrients: models.RecipeNutrition{Calories: 149},
+		Portions:  models.FoodPortions{{Amount: 1, Unit: "clove", Grams: 3}}},
+	{ID: "tomato-canned", Name: "Tomatoes, red, ripe, canned, packed in tomato juice", DataType: models.FoodDataSRLegacy},
+	{ID: "tomato", Name: "Tomatoes, red, ripe, raw, year round average", DataType: models.FoodDataSRLegacy},
+	{ID: "milk", Name: "Milk, whole, 3.25% milkfat, with added vitamin D", DataType: models.FoodDataSRLegacy,
+		Nutrients: models.RecipeNutrition{Calories: 61, Protein: 3.2},
+		Portions:  models.FoodPortions{{Amount: 1, Unit: ingredients.Cup, Grams: 244}}},
+	{ID: "olive-oil", Name: "Oil, olive, salad or cooking", DataType: models.FoodDataSRLegacy},
+	{ID: "olive-oil-brand", Name: "Extra virgin olive oil", DataType: models.FoodDataBranded},
+}
+
+func testFood(id string) *models.Food {
+	for i := range testFoods {
+		if testFoods[i].ID == id {
+			return &testFoods[i]
+		}
+	}
+	return nil
+}
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"Eggs", []string{"egg"}},
+		{"finely chopped fresh tomatoes", []string{"tomato"}},
+		{"Wheat flour, white, all-purpose", []string{"wheat", "flour", "white", "all", "purpose"}},
+		{"fresh blueberries", []string{"blueberry"}},
+		{"Milk, whole, 3.25% milkfat", []string{"milk", "whole", "milkfat"}},
+		{"hummus", []string{"hummus"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokens(tt.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokens(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestMatch(t *testing.T) {
+	tests := []struct {
+		ingredient string
+		want       string
+	}{
+		{"butter", "butter"},
+		{"unsalted butter, softened", "butter"},
+		{"large eggs", "egg"},
+		{"egg whites", "egg-white"},
+		{"all-purpose flour", "flour"},
+		{"garlic", "garlic"},
+		{"tomatoes", "tomato"},
+		{"canned tomatoes", "tomato-canned"},
+		{"whole milk", "milk"},
+		{"extra virgin olive oil", "olive-oil"},
+		{"saffron", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ingredient, func(t *testing.T) {
+			got, _ := BestMatch(tt.ingredient, testFoods)
+			gotID := ""
+			if got != nil {
+				gotID = got.ID
+			}
+			if gotID != tt.want {
+				t.Errorf("BestMatch(%q) = %q, want %q", tt.ingredient, gotID, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	got := SearchTerms("fresh blueberries and cream")
+	want := []string{"blueberr", "cream"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchTerms() = %q, want %q", got, want)
+	}
+}
+
+func TestGrams(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		food   string
+		want   float64
+		wantOK bool
+	}{
+		{"grams", "200 g flour", "flour", 200, true},
+		{"pounds", "1 lb flour", "flour", 453.59, true},
+		{"cups by density", "2 cups all-purpose flour", "flour", 250.78, true},
+		{"cups by food portion", "1 cup whole milk", "milk", 244, true},
+		{"tablespoons by food portion", "2 tbsp milk", "milk", 30.5, true},
+		{"sized items", "3 large eggs", "egg", 150, true},
+		{"unsized items use medium", "2 eggs", "egg", 88, true},
+		{"count unit portion", "4 cloves garlic", "garlic", 12, true},
+		{"range midpoint", "3-4 cloves garlic", "garlic", 10.5, true},
+		{"package size", "2 (14 oz) cans tomatoes", "tomato-canned", 793.79, true},
+		{"no quantity", "salt to taste", "butter", 0, false},
+		{"count unit without portion", "1 head garlic", "garlic", 0, false},
+		{"volume without density or portion", "1 cup eggplant", "eggplant", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Grams(models.ParseRecipeIngredient(tt.line), testFood(tt.food))
+			if ok != tt.wantOK {
+				t.Fatalf("Grams(%q) ok = %v, want %v", tt.line, ok, tt.wantOK)
+			}
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("Grams(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	items := models.RecipeIngredients{
+		models.ParseRecipeIngredient("250 g all-purpose flour"),
+		models.ParseRecipeIngredient("2 large eggs"),
+		models.ParseRecipeIngredient("1 cup milk"),
+		models.ParseRecipeIngredient("1 tsp saffron"),
+		models.ParseRecipeIngredient("salt to taste"),
+	}
+	items[0].FoodID = "flour"
+	items[1].FoodID = "egg"
+	items[2].FoodID = "milk"
+	foods := map[string]*models.Food{
+		"flour": testFood("flour"),
+		"egg":   testFood("egg"),
+		"milk":  testFood("milk"),
+	}
+
+	got := Compute(items, foods, 4)
+	// (910 + 143 + 148.84) / 4
+	if got.Nutrition.Calories != 300 {
+		t.Errorf("Calories = %v, want 300", got.Nutrition.Calories)
+	}
+	// (25.75 + 12.6 + 7.808) / 4
+	if got.Nutrition.Protein != 11.5 {
+		t.Errorf("Protein = %v, want 11.5", got.Nutrition.Protein)
+	}
+	if got.Coverage != 0.75 || got.Confidence != ConfidenceMedium || got.Weighed != 3 {
+		t.Errorf("Coverage = %v, Confidence = %q, Weighed = %d; want 0.75, medium, 3", got.Coverage, got.Confidence, got.Weighed)
+	}
+	if items[1].Grams != 100 || items[3].Grams != 0 {
+		t.Errorf("Grams = %v, %v; want 100, 0", items[1].Grams, items[3].Grams)
+	}
+
+	if none := Compute(items[3:], foods, 4); none.Confidence != "" || none.Nutrition != (models.RecipeNutrition{}) {
+		t.Errorf("Compute() with nothing weighed = %+v", none)
+	}
+}
+
+const fdcJSON = `{"SRLegacyFoods": [
+  {
+    "fdcId": 171287, "description": "Egg, whole, raw, fresh", "dataType": "SR Legacy",
+    "foodCategory": {"description": "Dairy and Egg Products"},
+    "foodNutrients": [
+      {"nutrient": {"id": 1008, "name": "Energy"}, "amount": 143},
+      {"nutrient": {"id": 1003, "name": "Protein"}, "amount": 12.6},
+      {"nutrient": {"id": 1253, "name": "Cholesterol"}, "amount": 372}
+    ],
+    "foodPortions": [
+      {"amount": 1, "gramWeight": 50, "modifier": "large", "measureUnit": {"name": "undetermined"}},
+      {"amount": 1, "gramWeight": 243, "modifier": "cup (4.86 large eggs)", "measureUnit": {"name": "undetermined"}}
+    ]
+  },
+  {
+    "fdcId": 2000001, "description": "Granola bar", "dataType": "Branded",
+    "foodNutrients": []
+  }
+]}`
+
+func TestReadFDCJSON(t *testing.T) {
+	var got []models.Food
+	err := ReadFDCJSON(strings.NewReader(fdcJSON), ReadOptions{}, func(f models.Food) error {
+		got = append(got, f)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadFDCJSON() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("ReadFDCJSON() returned %d foods, want 1 (branded skipped)", len(got))
+	}
+
+	egg := got[0]
+	if *egg.FDCID != 171287 || egg.DataType != models.FoodDataSRLegacy || egg.Category != "Dairy and Egg Products" {
+		t.Errorf("food = %+v", egg)
+	}
+	wantNutrients := models.RecipeNutrition{Calories: 143, Protein: 12.6, Cholesterol: 372}
+	if egg.Nutrients != wantNutrients {
+		t.Errorf("Nutrients = %+v, want %+v", egg.Nutrients, wantNutrients)
+	}
+	wantPortions := models.FoodPortions{
+		{Amount: 1, Unit: "large", Description: "large", Grams: 50},
+		{Amount: 1, Unit: ingredients.Cup, Description: "cup (4.86 large eggs)", Grams: 243},
+	}
+	if !reflect.DeepEqual(egg.Portions, wantPortions) {
+		t.Errorf("Portions = %+v, want %+v", egg.Portions, wantPortions)
+	}
+
+	if err := ReadFDCJSON(strings.NewReader(`"nope"`), ReadOptions{}, nil); err == nil {
+		t.Error("ReadFDCJSON() of a string should fail")
+	}
+}
+
+func TestReadFDCCSV(t *testing.T) {
+	fsys := fstest.MapFS{
+		"food.csv": {Data: []byte(`"fdc_id","data_type","description","food_category_id","publication_date"
+"748967","foundation_food","Eggs, Grade A, Large, egg whole","1","2019-12-16"
+"2000001","branded_food","Granola bar","",""
+`)},
+		"food_category.csv": {Data: []byte(`"id","code","description"
+"1","0100","Dairy and Egg Products"
+`)},
+		"food_nutrient.csv": {Data: []byte(`"id","fdc_id","nutrient_id","amount"
+"1","748967","2048","148"
+"2","748967","1003","12.4"
+"3","748967","1050","0.96"
+"4","748967","1004","9.96"
+`)},
+		"measure_unit.csv": {Data: []byte(`"id","name"
+"1000","cup"
+`)},
+		"food_portion.csv": {Data: []byte(`"id","fdc_id","seq_num","amount","measure_unit_id","portion_description","modifier","gram_weight"
+"1","748967","1","1","1000","","","243"
+`)},
+	}
+
+	var got []models.Food
+	if err := ReadFDCCSV(fsys, ReadOptions{}, func(f models.Food) error {
+		got = append(got, f)
+		return nil
+	}); err != nil {
+		t.Fatalf("ReadFDCCSV() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("ReadFDCCSV() returned %d foods, want 1", len(got))
+	}
+
+	egg := got[0]
+	if *egg.FDCID != 748967 || egg.DataType != models.FoodDataFoundation || egg.Category != "Dairy and Egg Products" {
+		t.Errorf("food = %+v", egg)
+	}
+	wantNutrients := models.RecipeNutrition{Calories: 148, Protein: 12.4, Carbohydrates: 0.96, Fat: 9.96}
+	if egg.Nutrients != wantNutrients {
+		t.Errorf("Nutrients = %+v, want %+v", egg.Nutrients, wantNutrients)
+	}
+	wantPortions := models.FoodPortions{{Amount: 1, Unit: ingredients.Cup, Grams: 243}}
+	if !reflect.DeepEqual(egg.Portions, wantPortions) {
+		t.Errorf("Portions = %+v, want %+v", egg.Portions, wantPortions)
+	}
+}
@@ -0,0 +1,184 @@
+// Package foods matches recipe ingredients to entries of the food
+// composition database, weighs them and computes recipe nutrition. It also
+// reads USDA FoodData Central dumps for import.
+package foods
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/meal-planner/backend/internal/models"
+)
+
+// MinScore is the lowest Score accepted as a match
+const MinScore = 0.5
+
+// ignoredWords describe preparation, size or quality rather than the food,
+// and are dropped from both ingredient and food names
+var ignoredWords = map[string]bool{
+	"a": true, "an": true, "and": true, "or": true, "of": true, "the": true,
+	"with": true, "for": true, "in": true, "to": true, "at": true,
+	"fresh": true, "freshly": true, "chopped": true, "diced": true,
+	"minced": true, "sliced": true, "grated": true, "shredded": true,
+	"crushed": true, "finely": true, "roughly": true, "thinly": true,
+	"large": true, "small": true, "medium": true, "extra": true,
+	"virgin": true, "organic": true, "peeled": true, "softened": true,
+	"melted": true, "packed": true, "ripe": true, "good": true,
+	"quality": true, "boneless": true, "skinless": true, "trimmed": true,
+	"unsalted": true, "salted": true, "room": true, "temperature": true,
+}
+
+// neutralWords are qualifiers of the generic form of a food in FoodData
+// Central names ("Egg, whole, raw, fresh"); they are not held against a
+// food when they are missing from the ingredient name
+var neutralWords = map[string]bool{
+	"raw": true, "whole": true, "plain": true, "regular": true, "all": true,
+	"commercial": true, "commercially": true, "prepared": true,
+	"enriched": true, "unenriched": true, "bleached": true,
+	"unbleached": true, "grade": true, "nfs": true, "ns": true,
+	"usda": true, "commodity": true, "includes": true, "form": true,
+}
+
+// Tokens splits a food or ingredient name into singular, lower-case words,
+// dropping words that describe preparation rather than the food
+func Tokens(name string) []string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-'
+	})
+	var tokens []string
+	for _, word := range words {
+		for _, part := range strings.Split(word, "-") {
+			if part == "" || ignoredWords[part] {
+				continue
+			}
+			tokens = append(tokens, singular(part))
+		}
+	}
+	return tokens
+}
+
+// singular strips common English plural endings
+func singular(word string) string {
+	switch {
+	case len(word) <= 3:
+		return word
+	case strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "oes"), strings.HasSuffix(word, "ches"),
+		strings.HasSuffix(word, "shes"), strings.HasSuffix(word, "sses"),
+		strings.HasSuffix(word, "xes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// Score rates how well a food matches an ingredient name, from 0 to about
+// 1.3. It rewards ingredient words found in the food's name most, food
+// words found in the ingredient less, and the food's primary name (before
+// the first comma, as in "Flour, wheat, all-purpose") being mentioned.
+func Score(ingredientName string, food *models.Food) float64 {
+	ingredient := Tokens(ingredientName)
+	foodTokens := Tokens(food.Name)
+	if len(ingredient) == 0 || len(foodTokens) == 0 {
+		return 0
+	}
+
+	inFood := make(map[string]bool, len(foodTokens))
+	for _, t := range foodTokens {
+		inFood[t] = true
+	}
+	inIngredient := make(map[string]bool, len(ingredient))
+	matched := 0
+	for _, t := range ingredient {
+		if !inIngredient[t] && inFood[t] {
+			matched++
+		}
+		inIngredient[t] = true
+	}
+	recall := float64(matched) / float64(len(inIngredient))
+	if recall < 0.5 {
+		return 0
+	}
+
+	// Precision ignores neutral words the ingredient does not mention
+	counted, found := 0, 0
+	for t := range inFood {
+		if neutralWords[t] && !inIngredient[t] {
+			continue
+		}
+		counted++
+		if inIngredient[t] {
+			found++
+		}
+	}
+	precision := 1.0
+	if counted > 0 {
+		precision = float64(found) / float64(counted)
+	}
+
+	primaryName, _, _ := strings.Cut(food.Name, ",")
+	primary := Tokens(primaryName)
+	primaryFound := 0
+	for _, t := range primary {
+		if inIngredient[t] {
+			primaryFound++
+		}
+	}
+	var primaryScore float64
+	if len(primary) > 0 {
+		primaryScore = float64(primaryFound) / float64(len(primary))
+	}
+
+	score := 0.6*recall + 0.3*precision + 0.3*primaryScore
+	if inFood["raw"] && !inIngredient["cooked"] {
+		score += 0.05
+	}
+	// Branded products are matched only when nothing generic is close
+	if food.DataType == models.FoodDataBranded {
+		score -= 0.25
+	}
+	return score
+}
+
+// BestMatch returns the candidate food that best matches the ingredient name
+// and its score, or nil when none scores at least MinScore. Ties go to the
+// earlier candidate.
+func BestMatch(ingredientName string, candidates []models.Food) (*models.Food, float64) {
+	var best *models.Food
+	bestScore := 0.0
+	for i := range candidates {
+		if score := Score(ingredientName, &candidates[i]); score > bestScore {
+			best, bestScore = &candidates[i], score
+		}
+	}
+	if bestScore < MinScore {
+		return nil, 0
+	}
+	return best, bestScore
+}
+
+// SearchTerms returns substrings of an ingredient name's words worth
+// searching food names for, longest first. A final "y" is dropped so that
+// "berry" also finds "berries".
+func SearchTerms(ingredientName string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, t := range Tokens(ingredientName) {
+		if len(t) > 4 && strings.HasSuffix(t, "y") {
+			t = t[:len(t)-1]
+		}
+		if len(t) >= 3 && !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	sort.SliceStable(terms, func(i, j int) bool {
+		return len(terms[i]) > len(terms[j])
+	})
+	return terms
+}
@@ -0,0 +1,350 @@
+package foods
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/ingredients"
+	"github.com/meal-planner/backend/internal/models"
+)
+
+// ErrBadDump is returned for FoodData Central files that are not in a known
+// format
+var ErrBadDump = errors.New("not a FoodData Central dump")
+
+// nutrientIDs maps FoodData Central nutrient IDs to the nutrition field they
+// fill, best source first. Foundation foods often report energy only as the
+// Atwater factors (2047, 2048) and carbohydrate "by summation" (1050).
+var nutrientIDs = []struct {
+	ids []int
+	set func(n *models.RecipeNutrition, v float64)
+}{
+	{[]int{1008, 2048, 2047}, func(n *models.RecipeNutrition, v float64) { n.Calories = v }},
+	{[]int{1003}, func(n *models.RecipeNutrition, v float64) { n.Protein = v }},
+	{[]int{1005, 1050}, func(n *models.RecipeNutrition, v float64) { n.Carbohydrates = v }},
+	{[]int{1004, 1085}, func(n *models.RecipeNutrition, v float64) { n.Fat = v }},
+	{[]int{1079}, func(n *models.RecipeNutrition, v float64) { n.Fiber = v }},
+	{[]int{2000, 1063}, func(n *models.RecipeNutrition, v float64) { n.Sugar = v }},
+	{[]int{1093}, func(n *models.RecipeNutrition, v float64) { n.Sodium = v }},
+	{[]int{1258}, func(n *models.RecipeNutrition, v float64) { n.SaturatedFat = v }},
+	{[]int{1253}, func(n *models.RecipeNutrition, v float64) { n.Cholesterol = v }},
+	{[]int{1087}, func(n *models.RecipeNutrition, v float64) { n.Calcium = v }},
+	{[]int{1089}, func(n *models.RecipeNutrition, v float64) { n.Iron = v }},
+	{[]int{1092}, func(n *models.RecipeNutrition, v float64) { n.Potassium = v }},
+	{[]int{1162}, func(n *models.RecipeNutrition, v float64) { n.VitaminC = v }},
+	{[]int{1114}, func(n *models.RecipeNutrition, v float64) { n.VitaminD = v }},
+}
+
+// nutrition builds per-100 g nutrition from nutrient amounts by ID
+func nutrition(amounts map[int]float64) models.RecipeNutrition {
+	var n models.RecipeNutrition
+	for _, field := range nutrientIDs {
+		for _, id := range field.ids {
+			if v, ok := amounts[id]; ok {
+				field.set(&n, v)
+				break
+			}
+		}
+	}
+	return n
+}
+
+// dataType maps FoodData Central data types to the models.FoodData constants
+func dataType(s string) string {
+	switch strings.ToLower(strings.ReplaceAll(s, " ", "_")) {
+	case "foundation", "foundation_food":
+		return models.FoodDataFoundation
+	case "sr_legacy", "sr_legacy_food":
+		return models.FoodDataSRLegacy
+	case "survey_fndds_food", "survey_(fndds)", "survey":
+		return models.FoodDataSurvey
+	case "branded", "branded_food":
+		return models.FoodDataBranded
+	}
+	return ""
+}
+
+// portion builds a food portion from FoodData Central's measure unit name,
+// modifier and description. SR Legacy leaves the unit "undetermined" and
+// puts it in the modifier, as in "cup, chopped" or "large".
+func portion(amount, grams float64, unitName, modifier, description string) (models.FoodPortion, bool) {
+	if grams <= 0 {
+		return models.FoodPortion{}, false
+	}
+	if amount <= 0 {
+		amount = 1
+	}
+	p := models.FoodPortion{Amount: amount, Grams: grams, Description: description}
+	if unit, ok := ingredients.NormalizeUnit(unitName); ok {
+		p.Unit = unit
+	} else {
+		first := strings.ToLower(modifier)
+		if i := strings.IndexAny(first, ",("); i >= 0 {
+			first = first[:i]
+		}
+		first = strings.TrimSpace(first)
+		if unit, ok := ingredients.NormalizeUnit(first); ok {
+			p.Unit = unit
+		} else if isSizeWord(first) {
+			p.Unit = first
+		}
+	}
+	if p.Description == "" {
+		p.Description = strings.TrimSpace(modifier)
+	}
+	return p, true
+}
+
+// ReadOptions controls which foods ReadFDCJSON and ReadFDCCSV return
+type ReadOptions struct {
+	// Branded includes branded foods, which are skipped by default: there are
+	// hundreds of thousands of them and they crowd out generic matches
+	Branded bool
+}
+
+func (o ReadOptions) skip(f *models.Food) bool {
+	return f.Name == "" || (f.DataType == models.FoodDataBranded && !o.Branded)
+}
+
+type fdcJSONFood struct {
+	FDCID        int    `json:"fdcId"`
+	Description  string `json:"description"`
+	DataType     string `json:"dataType"`
+	FoodCategory *struct {
+		Description string `json:"description"`
+	} `json:"foodCategory"`
+	WWEIAFoodCategory *struct {
+		Description string `json:"wweiaFoodCategoryDescription"`
+	} `json:"wweiaFoodCategory"`
+	BrandedFoodCategory string `json:"brandedFoodCategory"`
+	FoodNutrients       []struct {
+		Nutrient struct {
+			ID int `json:"id"`
+		} `json:"nutrient"`
+		Amount float64 `json:"amount"`
+	} `json:"foodNutrients"`
+	FoodPortions []struct {
+		Amount      float64 `json:"amount"`
+		GramWeight  float64 `json:"gramWeight"`
+		Modifier    string  `json:"modifier"`
+		Description string  `json:"portionDescription"`
+		MeasureUnit struct {
+			Name string `json:"name"`
+		} `json:"measureUnit"`
+	} `json:"foodPortions"`
+}
+
+func (j *fdcJSONFood) food() models.Food {
+	fdcID := j.FDCID
+	f := models.Food{
+		FDCID:    &fdcID,
+		Name:     strings.TrimSpace(j.Description),
+		DataType: dataType(j.DataType),
+		Category: j.BrandedFoodCategory,
+		Portions: models.FoodPortions{},
+	}
+	if j.FoodCategory != nil {
+		f.Category = j.FoodCategory.Description
+	} else if j.WWEIAFoodCategory != nil {
+		f.Category = j.WWEIAFoodCategory.Description
+	}
+
+	amounts := make(map[int]float64, len(j.FoodNutrients))
+	for _, n := range j.FoodNutrients {
+		amounts[n.Nutrient.ID] = n.Amount
+	}
+	f.Nutrients = nutrition(amounts)
+
+	for _, p := range j.FoodPortions {
+		if portion, ok := portion(p.Amount, p.GramWeight, p.MeasureUnit.Name, p.Modifier, p.Description); ok {
+			f.Portions = append(f.Portions, portion)
+		}
+	}
+	return f
+}
+
+// ReadFDCJSON streams the foods of a FoodData Central JSON download, such as
+// the Foundation, SR Legacy or FNDDS files, calling fn for each. The file is
+// an object holding one array of foods ({"FoundationFoods": [...]}) or a bare
+// array; it is decoded one food at a time so large files need not fit in
+// memory.
+func ReadFDCJSON(r io.Reader, opts ReadOptions, fn func(models.Food) error) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadDump, err)
+	}
+	switch tok {
+	case json.Delim('['):
+		return readFDCArray(dec, opts, fn)
+	case json.Delim('{'):
+	default:
+		return ErrBadDump
+	}
+
+	for dec.More() {
+		if _, err := dec.Token(); err != nil { // key
+			return err
+		}
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok != json.Delim('[') {
+			return ErrBadDump
+		}
+		if err := readFDCArray(dec, opts, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readFDCArray decodes foods up to and including the closing bracket
+func readFDCArray(dec *json.Decoder, opts ReadOptions, fn func(models.Food) error) error {
+	for dec.More() {
+		var j fdcJSONFood
+		if err := dec.Decode(&j); err != nil {
+			return err
+		}
+		f := j.food()
+		if opts.skip(&f) {
+			continue
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	_, err := dec.Token()
+	return err
+}
+
+// ReadFDCCSV reads a FoodData Central CSV download: food.csv,
+// food_nutrient.csv, food_portion.csv and measure_unit.csv, and
+// food_category.csv when present. Columns are found by header name. The
+// nutrient and portion files are read into memory keyed by food; fn is then
+// called for each food in food.csv.
+func ReadFDCCSV(fsys fs.FS, opts ReadOptions, fn func(models.Food) error) error {
+	categories := make(map[string]string)
+	if err := readCSV(fsys, "food_category.csv", []string{"id", "description"}, func(row []string) {
+		categories[row[0]] = row[1]
+	}); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	measureUnits := make(map[string]string)
+	if err := readCSV(fsys, "measure_unit.csv", []string{"id", "name"}, func(row []string) {
+		measureUnits[row[0]] = row[1]
+	}); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	amounts := make(map[string]map[int]float64)
+	if err := readCSV(fsys, "food_nutrient.csv", []string{"fdc_id", "nutrient_id", "amount"}, func(row []string) {
+		id, err1 := strconv.Atoi(row[1])
+		amount, err2 := strconv.ParseFloat(row[2], 64)
+		if err1 != nil || err2 != nil {
+			return
+		}
+		if amounts[row[0]] == nil {
+			amounts[row[0]] = make(map[int]float64)
+		}
+		amounts[row[0]][id] = amount
+	}); err != nil {
+		return err
+	}
+
+	portions := make(map[string]models.FoodPortions)
+	if err := readCSV(fsys, "food_portion.csv", []string{"fdc_id", "amount", "measure_unit_id", "modifier", "portion_description", "gram_weight"}, func(row []string) {
+		amount, _ := strconv.ParseFloat(row[1], 64)
+		grams, _ := strconv.ParseFloat(row[5], 64)
+		if p, ok := portion(amount, grams, measureUnits[row[2]], row[3], row[4]); ok {
+			portions[row[0]] = append(portions[row[0]], p)
+		}
+	}); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	var fnErr error
+	err := readCSV(fsys, "food.csv", []string{"fdc_id", "data_type", "description", "food_category_id"}, func(row []string) {
+		if fnErr != nil {
+			return
+		}
+		fdcID, err := strconv.Atoi(row[0])
+		if err != nil {
+			return
+		}
+		f := models.Food{
+			FDCID:     &fdcID,
+			Name:      strings.TrimSpace(row[2]),
+			DataType:  dataType(row[1]),
+			Category:  categories[row[3]],
+			Nutrients: nutrition(amounts[row[0]]),
+			Portions:  portions[row[0]],
+		}
+		if f.Portions == nil {
+			f.Portions = models.FoodPortions{}
+		}
+		if !opts.skip(&f) {
+			fnErr = fn(f)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return fnErr
+}
+
+// readCSV calls fn with the named columns of each row of a CSV file. Missing
+// columns are passed as empty strings.
+func readCSV(fsys fs.FS, name string, columns []string, fn func(row []string)) error {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	r.ReuseRecord = true
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	index := make([]int, len(columns))
+	for i, col := range columns {
+		index[i] = -1
+		for j, h := range header {
+			if strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")) == col {
+				index[i] = j
+			}
+		}
+	}
+	if index[0] < 0 {
+		return fmt.Errorf("%s: %w: no %s column", name, ErrBadDump, columns[0])
+	}
+
+	row := make([]string, len(columns))
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		for i, j := range index {
+			row[i] = ""
+			if j >= 0 && j < len(record) {
+				row[i] = record[j]
+			}
+		}
+		fn(row)
+	}
+}
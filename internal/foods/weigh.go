@@ -0,0 +1,216 @@
+package foods
+
+import (
+	"math"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/ingredients"
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/units"
+)
+
+// Nutrition confidence levels, from the share of measured ingredients that
+// could be matched and weighed
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// sizeWords are portion units of counted foods in FoodData Central, as in
+// "1 large" egg
+var sizeWords = []string{"extra large", "jumbo", "large", "medium", "small"}
+
+// Grams returns the weight of an ingredient of the given food, and false when
+// it cannot be weighed: it has no quantity, or its unit is a volume or count
+// the food has no portion for. Ranges are weighed at their midpoint, and
+// "1 (14 oz) can" as 14 oz.
+func Grams(ing models.RecipeIngredient, food *models.Food) (float64, bool) {
+	qty := ing.Quantity
+	if ing.QuantityMax > qty {
+		qty = (qty + ing.QuantityMax) / 2
+	}
+	if qty <= 0 {
+		return 0, false
+	}
+
+	if ing.Size != nil {
+		size, ok := weigh(ing.Size.Quantity, ing.Size.Unit, ing.Name, food)
+		return size * qty, ok
+	}
+	if ing.UnknownUnit {
+		return 0, false
+	}
+	if ing.Unit != "" && !ingredients.IsCountUnit(ing.Unit) {
+		return weigh(qty, ing.Unit, ing.Name, food)
+	}
+
+	if portion := countPortion(ing, food); portion != nil {
+		return qty * portion.Grams / portion.Amount, true
+	}
+	return 0, false
+}
+
+// weigh converts an amount of a mass or volume unit to grams. Volumes use
+// the food's own volume portions when it has some, and the density table
+// otherwise.
+func weigh(amount float64, unit, name string, food *models.Food) (float64, bool) {
+	q := units.Quantity{Amount: amount, Unit: unit}
+	switch units.DimensionOf(unit) {
+	case units.Mass:
+		g, err := units.Convert(q, ingredients.Gram, name)
+		return g.Amount, err == nil
+	case units.Volume:
+		ml, err := units.Convert(q, ingredients.Milliliter, name)
+		if err != nil {
+			return 0, false
+		}
+		for _, p := range food.Portions {
+			if units.DimensionOf(p.Unit) != units.Volume || p.Amount <= 0 {
+				continue
+			}
+			portion, err := units.Convert(units.Quantity{Amount: p.Amount, Unit: p.Unit}, ingredients.Milliliter, name)
+			if err == nil && portion.Amount > 0 {
+				return ml.Amount * p.Grams / portion.Amount, true
+			}
+		}
+		for _, n := range []string{name, food.Name} {
+			if density, ok := units.Density(n); ok {
+				return ml.Amount * density, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// countPortion finds the portion for a counted ingredient. "2 cloves garlic"
+// uses the food's clove portion. Without a unit, as in "3 large eggs", the
+// size named in the ingredient is used, then a medium item, then a portion
+// for a whole item of unspecified size, then any size.
+func countPortion(ing models.RecipeIngredient, food *models.Food) *models.FoodPortion {
+	find := func(unit string) *models.FoodPortion {
+		for i := range food.Portions {
+			if p := &food.Portions[i]; p.Unit == unit && p.Amount > 0 && p.Grams > 0 {
+				return p
+			}
+		}
+		return nil
+	}
+	if ing.Unit != "" {
+		return find(ing.Unit)
+	}
+
+	name := " " + strings.ToLower(ing.Name) + " "
+	for _, size := range sizeWords {
+		if strings.Contains(name, " "+size+" ") {
+			if p := find(size); p != nil {
+				return p
+			}
+		}
+	}
+	for _, unit := range []string{"medium", ""} {
+		if p := find(unit); p != nil {
+			return p
+		}
+	}
+	for _, size := range sizeWords {
+		if p := find(size); p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+func isSizeWord(word string) bool {
+	for _, size := range sizeWords {
+		if word == size {
+			return true
+		}
+	}
+	return false
+}
+
+// Result is the nutrition computed for a recipe
+type Result struct {
+	// Nutrition is per serving, rounded for display
+	Nutrition  models.RecipeNutrition
+	Coverage   float64
+	Confidence string
+	// Weighed is the number of ingredients that were weighed
+	Weighed int
+}
+
+// Compute weighs the ingredients of a recipe against their matched foods,
+// keyed by food ID, and sets their Grams. Nutrition is the sum over the
+// weighed ingredients divided by servings. Coverage is the share of
+// ingredients with a quantity that were weighed; lines like "salt to taste"
+// do not count against it.
+func Compute(items models.RecipeIngredients, foods map[string]*models.Food, servings int) Result {
+	if servings < 1 {
+		servings = 1
+	}
+	var total models.RecipeNutrition
+	measured, weighed := 0, 0
+	for i := range items {
+		ing := &items[i]
+		ing.Grams = 0
+		if ing.Quantity <= 0 {
+			continue
+		}
+		measured++
+		food, ok := foods[ing.FoodID]
+		if !ok || ing.FoodID == "" {
+			continue
+		}
+		grams, ok := Grams(*ing, food)
+		if !ok {
+			continue
+		}
+		ing.Grams = math.Round(grams*10) / 10
+		total = total.Add(food.Nutrients.Times(grams / 100))
+		weighed++
+	}
+
+	result := Result{Weighed: weighed}
+	if weighed == 0 {
+		return result
+	}
+	result.Nutrition = roundNutrition(total.Times(1 / float64(servings)))
+	result.Coverage = math.Round(float64(weighed)/float64(measured)*1000) / 1000
+	result.Confidence = ConfidenceFor(result.Coverage)
+	return result
+}
+
+// ConfidenceFor returns the confidence level of a nutrition coverage
+func ConfidenceFor(coverage float64) string {
+	switch {
+	case coverage >= 0.9:
+		return ConfidenceHigh
+	case coverage >= 0.6:
+		return ConfidenceMedium
+	}
+	return ConfidenceLow
+}
+
+// roundNutrition rounds calories and milligram amounts to whole numbers and
+// grams and micrograms to one decimal
+func roundNutrition(n models.RecipeNutrition) models.RecipeNutrition {
+	whole := math.Round
+	tenth := func(v float64) float64 { return math.Round(v*10) / 10 }
+	return models.RecipeNutrition{
+		Calories:      whole(n.Calories),
+		Protein:       tenth(n.Protein),
+		Carbohydrates: tenth(n.Carbohydrates),
+		Fat:           tenth(n.Fat),
+		Fiber:         tenth(n.Fiber),
+		Sugar:         tenth(n.Sugar),
+		Sodium:        whole(n.Sodium),
+		SaturatedFat:  tenth(n.SaturatedFat),
+		Cholesterol:   whole(n.Cholesterol),
+		Calcium:       whole(n.Calcium),
+		Iron:          tenth(n.Iron),
+		Potassium:     whole(n.Potassium),
+		VitaminC:      tenth(n.VitaminC),
+		VitaminD:      tenth(n.VitaminD),
+	}
+}
@@ -0,0 +1,374 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/pagination"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+type CollectionHandler struct {
+	collectionService services.CollectionService
+}
+
+func NewCollectionHandler(collectionService services.CollectionService) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+	}
+}
+
+// CollectionRequest represents the create/update collection request body
+type CollectionRequest struct {
+	Name          string `json:"name" binding:"required"`
+	Description   string `json:"description"`
+	CoverImageURL string `json:"coverImageUrl"`
+	Visibility    string `json:"visibility"`
+	HouseholdID   string `json:"householdId"`
+}
+
+func (r *CollectionRequest) toInput() *services.CollectionInput {
+	return &services.CollectionInput{
+		Name:          r.Name,
+		Description:   r.Description,
+		CoverImageURL: r.CoverImageURL,
+		Visibility:    r.Visibility,
+		HouseholdID:   r.HouseholdID,
+	}
+}
+
+// AddCollectionRecipeRequest represents the add recipe request body
+type AddCollectionRecipeRequest struct {
+	RecipeID string `json:"recipeId" binding:"required"`
+}
+
+// ReorderCollectionRequest represents the reorder recipes request body
+type ReorderCollectionRequest struct {
+	RecipeIDs []string `json:"recipeIds" binding:"required"`
+}
+
+// ListCollections lists the user's collections and those shared with their
+// households
+// GET /api/collections
+func (h *CollectionHandler) ListCollections(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	page, err := pagination.Parse(c, services.CollectionPagination)
+	if err != nil {
+		respondWithError(c, err, "invalid pagination")
+		return
+	}
+
+	collections, result, err := h.collectionService.List(userID, page)
+	if err != nil {
+		respondWithError(c, err, "failed to list collections")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       collections,
+		"pagination": result,
+	})
+}
+
+// CreateCollection creates a collection
+// POST /api/collections
+func (h *CollectionHandler) CreateCollection(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	collection, err := h.collectionService.Create(userID, req.toInput())
+	if err != nil {
+		respondWithError(c, err, "failed to create collection")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"collection": collection,
+	})
+}
+
+// GetCollection returns a collection
+// GET /api/collections/:id
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	collection, err := h.collectionService.Get(userID, c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to get collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collection": collection,
+	})
+}
+
+// UpdateCollection replaces a collection's details
+// PUT /api/collections/:id
+func (h *CollectionHandler) UpdateCollection(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	collection, err := h.collectionService.Update(userID, c.Param("id"), req.toInput())
+	if err != nil {
+		respondWithError(c, err, "failed to update collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collection": collection,
+	})
+}
+
+// DeleteCollection deletes a collection. Its recipes are not affected.
+// DELETE /api/collections/:id
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	if err := h.collectionService.Delete(userID, c.Param("id")); err != nil {
+		respondWithError(c, err, "failed to delete collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "collection deleted",
+	})
+}
+
+// ListCollectionRecipes lists a collection's recipes in their manual order
+// GET /api/collections/:id/recipes
+func (h *CollectionHandler) ListCollectionRecipes(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	page, err := pagination.Parse(c, services.CollectionRecipePagination)
+	if err != nil {
+		respondWithError(c, err, "invalid pagination")
+		return
+	}
+
+	entries, result, err := h.collectionService.ListRecipes(userID, c.Param("id"), page)
+	if err != nil {
+		respondWithError(c, err, "failed to list collection recipes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       entries,
+		"pagination": result,
+	})
+}
+
+// AddCollectionRecipe appends a recipe to a collection; adding it again has
+// no effect
+// POST /api/collections/:id/recipes
+func (h *CollectionHandler) AddCollectionRecipe(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req AddCollectionRecipeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	collection, err := h.collectionService.AddRecipe(userID, c.Param("id"), req.RecipeID)
+	if err != nil {
+		respondWithError(c, err, "failed to add recipe to collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collection": collection,
+	})
+}
+
+// RemoveCollectionRecipe removes a recipe from a collection
+// DELETE /api/collections/:id/recipes/:recipeId
+func (h *CollectionHandler) RemoveCollectionRecipe(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	collection, err := h.collectionService.RemoveRecipe(userID, c.Param("id"), c.Param("recipeId"))
+	if err != nil {
+		respondWithError(c, err, "failed to remove recipe from collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collection": collection,
+	})
+}
+
+// ReorderCollectionRecipes sets the order of a collection's recipes
+// PUT /api/collections/:id/recipes/order
+func (h *CollectionHandler) ReorderCollectionRecipes(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req ReorderCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	if err := h.collectionService.ReorderRecipes(userID, c.Param("id"), req.RecipeIDs); err != nil {
+		respondWithError(c, err, "failed to reorder collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "collection reordered",
+	})
+}
+
+// ShareCollection creates the collection's share link, or returns the
+// existing one
+// POST /api/collections/:id/share
+func (h *CollectionHandler) ShareCollection(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	collection, err := h.collectionService.Share(userID, c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to share collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collection": collection,
+	})
+}
+
+// UnshareCollection revokes the collection's share link
+// DELETE /api/collections/:id/share
+func (h *CollectionHandler) UnshareCollection(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	collection, err := h.collectionService.Unshare(userID, c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to unshare collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collection": collection,
+	})
+}
+
+// GetSharedCollection returns a collection through its share link
+// GET /api/shared/collections/:token
+func (h *CollectionHandler) GetSharedCollection(c *gin.Context) {
+	collection, err := h.collectionService.GetShared(c.Param("token"))
+	if err != nil {
+		respondWithError(c, err, "failed to get collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collection": collection,
+	})
+}
+
+// ListSharedCollectionRecipes lists the public recipes of a collection
+// through its share link
+// GET /api/shared/collections/:token/recipes
+func (h *CollectionHandler) ListSharedCollectionRecipes(c *gin.Context) {
+	page, err := pagination.Parse(c, services.CollectionRecipePagination)
+	if err != nil {
+		respondWithError(c, err, "invalid pagination")
+		return
+	}
+
+	entries, result, err := h.collectionService.ListSharedRecipes(c.Param("token"), page)
+	if err != nil {
+		respondWithError(c, err, "failed to list collection recipes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       entries,
+		"pagination": result,
+	})
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/cooklang"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+// maxCooklangArchiveUploadSize bounds an archive upload: the archive at its
+// limit plus room for the multipart framing
+const maxCooklangArchiveUploadSize = services.MaxCooklangArchiveBytes + 1<<20
+
+type CooklangHandler struct {
+	cooklangService services.CooklangService
+}
+
+func NewCooklangHandler(cooklangService services.CooklangService) *CooklangHandler {
+	return &CooklangHandler{
+		cooklangService: cooklangService,
+	}
+}
+
+// ExportRecipe downloads a recipe as a Cooklang .cook file
+// GET /api/recipes/:id/cooklang
+func (h *CooklangHandler) ExportRecipe(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	doc, fileName, err := h.cooklangService.Export(userID, c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to export recipe")
+		return
+	}
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", doc)
+}
+
+// ImportRecipe reads a Cooklang document into a recipe draft. The document
+// is sent as the request body or as a multipart "file" upload. The draft is
+// not saved.
+// POST /api/recipes/import/cooklang
+func (h *CooklangHandler) ImportRecipe(c *gin.Context) {
+	if _, exists := middleware.GetUserID(c); !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	doc, err := readUpload(c, cooklang.MaxSize+1<<16, "")
+	if err != nil {
+		respondWithUploadError(c, err, "document must be at most 256 KB")
+		return
+	}
+
+	result, err := h.cooklangService.ImportDraft(bytes.NewReader(doc))
+	if err != nil {
+		respondWithError(c, err, "failed to import recipe")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ImportArchive creates a private recipe for each .cook file of a zip
+// archive, sent as the request body or as a multipart "file" upload. A
+// "category" query or form value is used for files without a course.
+// POST /api/recipes/import/cooklang/archive
+func (h *CooklangHandler) ImportArchive(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	archive, err := readUpload(c, maxCooklangArchiveUploadSize, "")
+	if err != nil {
+		respondWithUploadError(c, err, fmt.Sprintf("archive must be at most %d MB", services.MaxCooklangArchiveBytes>>20))
+		return
+	}
+
+	category := c.PostForm("category")
+	if category == "" {
+		category = c.Query("category")
+	}
+	result, err := h.cooklangService.ImportArchive(userID, bytes.NewReader(archive), int64(len(archive)), category)
+	if err != nil && result != nil && result.Imported > 0 {
+		// Report the recipes created before the import stopped
+		log.Printf("failed to import archive: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":    "failed to import archive; the listed recipes were imported",
+			"imported": result.Imported,
+			"failed":   result.Failed,
+			"files":    result.Files,
+		})
+		return
+	}
+	if err != nil {
+		respondWithError(c, err, "failed to import archive")
+		return
+	}
+
+	status := http.StatusCreated
+	if result.Imported == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, result)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+type EaterProfileHandler struct {
+	profileService services.EaterProfileService
+}
+
+func NewEaterProfileHandler(profileService services.EaterProfileService) *EaterProfileHandler {
+	return &EaterProfileHandler{
+		profileService: profileService,
+	}
+}
+
+// EaterProfileRequest represents the create/update eater profile request body
+type EaterProfileRequest struct {
+	Name              string   `json:"name" binding:"required"`
+	AgeGroup          string   `json:"ageGroup"`
+	UserID            *string  `json:"userId"`
+	IsGuest           bool     `json:"isGuest"`
+	Allergies         []string `json:"allergies"`
+	Diets             []string `json:"diets"`
+	Dislikes          []string `json:"dislikes"`
+	PortionMultiplier *float64 `json:"portionMultiplier"`
+}
+
+func (r *EaterProfileRequest) toInput() *services.EaterProfileInput {
+	return &services.EaterProfileInput{
+		Name:              r.Name,
+		AgeGroup:          r.AgeGroup,
+		UserID:            r.UserID,
+		IsGuest:           r.IsGuest,
+		Allergies:         r.Allergies,
+		Diets:             r.Diets,
+		Dislikes:          r.Dislikes,
+		PortionMultiplier: r.PortionMultiplier,
+	}
+}
+
+// ListProfiles lists the eater profiles of a household
+// GET /api/households/:id/profiles
+func (h *EaterProfileHandler) ListProfiles(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	profiles, err := h.profileService.List(userID, c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to list profiles")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"profiles": profiles,
+	})
+}
+
+// CreateProfile adds an eater profile to a household
+// POST /api/households/:id/profiles
+func (h *EaterProfileHandler) CreateProfile(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req EaterProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	profile, err := h.profileService.Create(userID, c.Param("id"), req.toInput())
+	if err != nil {
+		respondWithError(c, err, "failed to create profile")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"profile": profile,
+	})
+}
+
+// UpdateProfile replaces an eater profile. A request without
+// portionMultiplier keeps the current one.
+// PUT /api/households/:id/profiles/:profileId
+func (h *EaterProfileHandler) UpdateProfile(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req EaterProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	profile, err := h.profileService.Update(userID, c.Param("id"), c.Param("profileId"), req.toInput())
+	if err != nil {
+		respondWithError(c, err, "failed to update profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"profile": profile,
+	})
+}
+
+// DeleteProfile removes an eater profile
+// DELETE /api/households/:id/profiles/:profileId
+func (h *EaterProfileHandler) DeleteProfile(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	if err := h.profileService.Delete(userID, c.Param("id"), c.Param("profileId")); err != nil {
+		respondWithError(c, err, "failed to delete profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "profile deleted",
+	})
+}
+
+// GetDiners summarizes restrictions and portions for the diners at a meal.
+// Pass ?profiles=id1,id2 to select diners; all profiles are used otherwise.
+// GET /api/households/:id/diners
+func (h *EaterProfileHandler) GetDiners(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var profileIDs []string
+	for _, id := range strings.Split(c.Query("profiles"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			profileIDs = append(profileIDs, id)
+		}
+	}
+
+	summary, err := h.profileService.SummarizeDiners(userID, c.Param("id"), profileIDs)
+	if err != nil {
+		respondWithError(c, err, "failed to summarize diners")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"diners": summary,
+	})
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/pagination"
+	"github.com/meal-planner/backend/internal/recipeimport"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+// errorStatusCodes maps service errors to the HTTP status returned to clients
+var errorStatusCodes = map[error]int{
+	services.ErrUserNotFound:               http.StatusNotFound,
+	services.ErrOnboardingStepNotFound:     http.StatusNotFound,
+	services.ErrOnboardingStepNotSkippable: http.StatusBadRequest,
+	services.ErrHouseholdNotFound:          http.StatusNotFound,
+	services.ErrHouseholdAccessDenied:      http.StatusForbidden,
+	services.ErrHouseholdMemberNotFound:    http.StatusNotFound,
+	services.ErrAlreadyHouseholdMember:     http.StatusConflict,
+	services.ErrOwnerCannotLeave:           http.StatusConflict,
+	services.ErrInvitationNotFound:         http.StatusNotFound,
+	services.ErrInvitationNotPending:       http.StatusConflict,
+	services.ErrInvitationExpired:          http.StatusGone,
+	services.ErrInvitationAlreadyPending:   http.StatusConflict,
+	services.ErrEaterProfileNotFound:       http.StatusNotFound,
+	services.ErrRecipeNotFound:             http.StatusNotFound,
+	services.ErrRecipeForbidden:            http.StatusForbidden,
+	services.ErrRecipeRevisionConflict:     http.StatusConflict,
+	services.ErrRevisionNotFound:           http.StatusNotFound,
+	services.ErrUpstreamNotFound:           http.StatusNotFound,
+	services.ErrReviewNotFound:             http.StatusNotFound,
+	services.ErrReviewExists:               http.StatusConflict,
+	services.ErrReviewForbidden:            http.StatusForbidden,
+	services.ErrCannotReviewOwnRecipe:      http.StatusForbidden,
+	services.ErrCannotVoteOwnReview:        http.StatusBadRequest,
+	services.ErrFoodNotFound:               http.StatusNotFound,
+	services.ErrCollectionNotFound:         http.StatusNotFound,
+	services.ErrImageNotFound:              http.StatusNotFound,
+	services.ErrCollectionForbidden:        http.StatusForbidden,
+	recipeimport.ErrNoRecipe:               http.StatusUnprocessableEntity,
+}
+
+// respondWithError writes an error response, mapping known service errors
+// to their status code and falling back to a 500 with the given message
+func respondWithError(c *gin.Context, err error, fallbackMsg string) {
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": validationErr.Message,
+		})
+		return
+	}
+
+	var paginationErr *pagination.Error
+	if errors.As(err, &paginationErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": paginationErr.Message,
+		})
+		return
+	}
+
+	for target, statusCode := range errorStatusCodes {
+		if errors.Is(err, target) {
+			c.JSON(statusCode, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+
+	log.Printf("%s: %v", fallbackMsg, err)
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": fallbackMsg,
+	})
+}
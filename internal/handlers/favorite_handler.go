@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/pagination"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+type FavoriteHandler struct {
+	favoriteService services.FavoriteService
+}
+
+func NewFavoriteHandler(favoriteService services.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{
+		favoriteService: favoriteService,
+	}
+}
+
+// AddFavorite favorites a recipe; favoriting it again has no effect
+// POST /api/recipes/:id/favorite
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	count, err := h.favoriteService.Add(userID, c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to favorite recipe")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recipeId":      c.Param("id"),
+		"isFavorite":    true,
+		"favoriteCount": count,
+	})
+}
+
+// RemoveFavorite unfavorites a recipe; removing a missing favorite has no effect
+// DELETE /api/recipes/:id/favorite
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	count, err := h.favoriteService.Remove(userID, c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to unfavorite recipe")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recipeId":      c.Param("id"),
+		"isFavorite":    false,
+		"favoriteCount": count,
+	})
+}
+
+// ListFavorites lists the current user's favorite recipes, newest first
+// GET /api/recipes/favorites
+func (h *FavoriteHandler) ListFavorites(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	page, err := pagination.Parse(c, services.FavoritePagination)
+	if err != nil {
+		respondWithError(c, err, "invalid pagination")
+		return
+	}
+
+	favorites, result, err := h.favoriteService.List(userID, page)
+	if err != nil {
+		respondWithError(c, err, "failed to list favorites")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       favorites,
+		"pagination": result,
+	})
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+type FoodHandler struct {
+	foodService services.FoodService
+}
+
+func NewFoodHandler(foodService services.FoodService) *FoodHandler {
+	return &FoodHandler{
+		foodService: foodService,
+	}
+}
+
+// SearchFoods finds foods by name, for choosing an ingredient's food
+// GET /api/foods?q=&limit=
+func (h *FoodHandler) SearchFoods(c *gin.Context) {
+	if _, exists := middleware.GetUserID(c); !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "limit must be a positive integer",
+			})
+			return
+		}
+		limit = n
+	}
+
+	foods, err := h.foodService.Search(c.Query("q"), limit)
+	if err != nil {
+		respondWithError(c, err, "failed to search foods")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": foods,
+	})
+}
+
+// GetFood returns a food with its nutrients per 100 g and portions
+// GET /api/foods/:id
+func (h *FoodHandler) GetFood(c *gin.Context) {
+	if _, exists := middleware.GetUserID(c); !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	food, err := h.foodService.Get(c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to get food")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"food": food,
+	})
+}
@@ -0,0 +1,421 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+type HouseholdHandler struct {
+	householdService services.HouseholdService
+}
+
+func NewHouseholdHandler(householdService services.HouseholdService) *HouseholdHandler {
+	return &HouseholdHandler{
+		householdService: householdService,
+	}
+}
+
+// HouseholdRequest represents the create/rename household request body
+type HouseholdRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// InviteRequest represents the household invitation request body
+type InviteRequest struct {
+	Email string               `json:"email" binding:"required"`
+	Role  models.HouseholdRole `json:"role"`
+}
+
+// MemberRoleRequest represents the change member role request body
+type MemberRoleRequest struct {
+	Role models.HouseholdRole `json:"role" binding:"required"`
+}
+
+// TransferOwnershipRequest represents the transfer ownership request body
+type TransferOwnershipRequest struct {
+	UserID string `json:"userId" binding:"required"`
+}
+
+// CreateHousehold creates a household owned by the current user
+// POST /api/households
+func (h *HouseholdHandler) CreateHousehold(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req HouseholdRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	household, err := h.householdService.Create(userID, req.Name)
+	if err != nil {
+		respondWithError(c, err, "failed to create household")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"household": household,
+	})
+}
+
+// ListHouseholds lists the households the current user belongs to
+// GET /api/households
+func (h *HouseholdHandler) ListHouseholds(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	households, err := h.householdService.ListForUser(userID)
+	if err != nil {
+		respondWithError(c, err, "failed to list households")
+		return
+	}
+
+	activeID, _ := middleware.GetHouseholdID(c)
+	c.JSON(http.StatusOK, gin.H{
+		"households":        households,
+		"activeHouseholdId": activeID,
+	})
+}
+
+// GetHousehold returns a household with its members
+// GET /api/households/:id
+func (h *HouseholdHandler) GetHousehold(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	household, err := h.householdService.Get(userID, c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to get household")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"household": household,
+	})
+}
+
+// UpdateHousehold renames a household
+// PUT /api/households/:id
+func (h *HouseholdHandler) UpdateHousehold(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req HouseholdRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	household, err := h.householdService.Rename(userID, c.Param("id"), req.Name)
+	if err != nil {
+		respondWithError(c, err, "failed to update household")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"household": household,
+	})
+}
+
+// DeleteHousehold deletes a household
+// DELETE /api/households/:id
+func (h *HouseholdHandler) DeleteHousehold(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	if err := h.householdService.Delete(userID, c.Param("id")); err != nil {
+		respondWithError(c, err, "failed to delete household")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "household deleted",
+	})
+}
+
+// Invite sends an invitation to join the household
+// POST /api/households/:id/invitations
+func (h *HouseholdHandler) Invite(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req InviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	invitation, err := h.householdService.Invite(userID, c.Param("id"), req.Email, req.Role)
+	if err != nil {
+		respondWithError(c, err, "failed to send invitation")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"invitation": invitation,
+	})
+}
+
+// ListInvitations lists the invitations sent for a household
+// GET /api/households/:id/invitations
+func (h *HouseholdHandler) ListInvitations(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	invitations, err := h.householdService.ListInvitations(userID, c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to list invitations")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invitations": invitations,
+	})
+}
+
+// RevokeInvitation revokes a pending invitation
+// DELETE /api/households/:id/invitations/:invitationId
+func (h *HouseholdHandler) RevokeInvitation(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	if err := h.householdService.RevokeInvitation(userID, c.Param("id"), c.Param("invitationId")); err != nil {
+		respondWithError(c, err, "failed to revoke invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "invitation revoked",
+	})
+}
+
+// ListMyInvitations lists pending invitations addressed to the current user
+// GET /api/invitations
+func (h *HouseholdHandler) ListMyInvitations(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	invitations, err := h.householdService.ListMyInvitations(userID)
+	if err != nil {
+		respondWithError(c, err, "failed to list invitations")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invitations": invitations,
+	})
+}
+
+// AcceptInvitation accepts an invitation and joins the household
+// POST /api/invitations/:id/accept
+func (h *HouseholdHandler) AcceptInvitation(c *gin.Context) {
+	h.respondToInvitation(c, true)
+}
+
+// DeclineInvitation declines an invitation
+// POST /api/invitations/:id/decline
+func (h *HouseholdHandler) DeclineInvitation(c *gin.Context) {
+	h.respondToInvitation(c, false)
+}
+
+func (h *HouseholdHandler) respondToInvitation(c *gin.Context, accept bool) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	invitation, err := h.householdService.RespondToInvitation(userID, c.Param("id"), accept)
+	if err != nil {
+		respondWithError(c, err, "failed to respond to invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invitation": invitation,
+	})
+}
+
+// ChangeMemberRole changes a member's role
+// PUT /api/households/:id/members/:userId
+func (h *HouseholdHandler) ChangeMemberRole(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req MemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	member, err := h.householdService.ChangeMemberRole(userID, c.Param("id"), c.Param("userId"), req.Role)
+	if err != nil {
+		respondWithError(c, err, "failed to change member role")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"member": member,
+	})
+}
+
+// RemoveMember removes a member from the household
+// DELETE /api/households/:id/members/:userId
+func (h *HouseholdHandler) RemoveMember(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	if err := h.householdService.RemoveMember(userID, c.Param("id"), c.Param("userId")); err != nil {
+		respondWithError(c, err, "failed to remove member")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "member removed",
+	})
+}
+
+// Leave removes the current user from the household
+// POST /api/households/:id/leave
+func (h *HouseholdHandler) Leave(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	if err := h.householdService.Leave(userID, c.Param("id")); err != nil {
+		respondWithError(c, err, "failed to leave household")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "left household",
+	})
+}
+
+// TransferOwnership hands household ownership to another member
+// POST /api/households/:id/transfer
+func (h *HouseholdHandler) TransferOwnership(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	household, err := h.householdService.TransferOwnership(userID, c.Param("id"), req.UserID)
+	if err != nil {
+		respondWithError(c, err, "failed to transfer ownership")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"household": household,
+	})
+}
+
+// Activate returns a token whose claims select the household as active.
+// Clients may instead send the X-Household-ID header on each request.
+// POST /api/households/:id/activate
+func (h *HouseholdHandler) Activate(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	token, err := h.householdService.Activate(userID, c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to activate household")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":             token,
+		"activeHouseholdId": c.Param("id"),
+	})
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+// maxImageUploadSize bounds a whole upload request: every image at its
+// limit plus room for the multipart framing
+const maxImageUploadSize = services.MaxImagesPerUpload*services.MaxImageBytes + 1<<20
+
+type ImageHandler struct {
+	imageService services.ImageService
+}
+
+func NewImageHandler(imageService services.ImageService) *ImageHandler {
+	return &ImageHandler{
+		imageService: imageService,
+	}
+}
+
+// UploadImages adds images to a recipe, sent as multipart "images" files
+// (or a single "image"). Each image is stored in original, large, medium
+// and thumbnail sizes; the first image of a recipe becomes its primary
+// image.
+// POST /api/recipes/:id/images
+func (h *ImageHandler) UploadImages(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	uploads, err := readImageUploads(c)
+	if err != nil {
+		respondWithUploadError(c, err, fmt.Sprintf("upload must be at most %d MB", maxImageUploadSize>>20))
+		return
+	}
+
+	images, err := h.imageService.Upload(userID, c.Param("id"), uploads)
+	if err != nil {
+		respondWithError(c, err, "failed to upload images")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"images": images,
+	})
+}
+
+// readImageUploads reads the uploaded files of a multipart request
+func readImageUploads(c *gin.Context) ([]services.ImageUpload, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImageUploadSize)
+	if c.ContentType() != "multipart/form-data" {
+		return nil, errors.New("images must be sent as multipart/form-data")
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		if isTooLarge(err) {
+			return nil, err
+		}
+		return nil, errors.New("invalid multipart form")
+	}
+
+	files := append(form.File["images"], form.File["image"]...)
+	if len(files) == 0 {
+		return nil, errors.New("images are required")
+	}
+	if len(files) > services.MaxImagesPerUpload {
+		return nil, fmt.Errorf("at most %d images can be uploaded at once", services.MaxImagesPerUpload)
+	}
+
+	uploads := make([]services.ImageUpload, 0, len(files))
+	for _, fileHeader := range files {
+		if fileHeader.Size > services.MaxImageBytes {
+			return nil, fmt.Errorf("%s: images must be at most %d MB", fileHeader.Filename, services.MaxImageBytes>>20)
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(io.LimitReader(file, services.MaxImageBytes+1))
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, services.ImageUpload{Filename: fileHeader.Filename, Data: data})
+	}
+	return uploads, nil
+}
+
+// ListImages lists a recipe's uploaded images in order
+// GET /api/recipes/:id/images
+func (h *ImageHandler) ListImages(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	images, err := h.imageService.List(userID, c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to list images")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"images": images,
+	})
+}
+
+// SetPrimaryImage makes an image the one shown for the recipe
+// POST /api/recipes/:id/images/:imageId/primary
+func (h *ImageHandler) SetPrimaryImage(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	images, err := h.imageService.SetPrimary(userID, c.Param("id"), c.Param("imageId"))
+	if err != nil {
+		respondWithError(c, err, "failed to set primary image")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"images": images,
+	})
+}
+
+// DeleteImage deletes an image and its stored files
+// DELETE /api/recipes/:id/images/:imageId
+func (h *ImageHandler) DeleteImage(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	if err := h.imageService.Delete(userID, c.Param("id"), c.Param("imageId")); err != nil {
+		respondWithError(c, err, "failed to delete image")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "image deleted",
+	})
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/nutrition"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+type NutritionGoalsHandler struct {
+	goalsService services.NutritionGoalsService
+}
+
+func NewNutritionGoalsHandler(goalsService services.NutritionGoalsService) *NutritionGoalsHandler {
+	return &NutritionGoalsHandler{
+		goalsService: goalsService,
+	}
+}
+
+// UpdateGoalsRequest represents the update nutrition goals request body.
+// Macros are grams or percent of calories depending on macroUnit.
+type UpdateGoalsRequest struct {
+	Preset      string                 `json:"preset"`
+	Calories    *int                   `json:"calories"`
+	MacroUnit   string                 `json:"macroUnit"`
+	Protein     *float64               `json:"protein"`
+	Carbs       *float64               `json:"carbs"`
+	Fat         *float64               `json:"fat"`
+	Fiber       *float64               `json:"fiber"`
+	SodiumLimit *float64               `json:"sodiumLimit"`
+	SugarLimit  *float64               `json:"sugarLimit"`
+	Calculator  *nutrition.BodyMetrics `json:"calculator"`
+}
+
+// EstimateGoalsRequest represents the goals calculator request body
+type EstimateGoalsRequest struct {
+	nutrition.BodyMetrics
+	Preset string `json:"preset"`
+}
+
+// GetGoals returns the user's nutrition goals
+// GET /api/users/goals
+func (h *NutritionGoalsHandler) GetGoals(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	goals, isSet, err := h.goalsService.GetGoals(userID)
+	if err != nil {
+		respondWithError(c, err, "failed to get nutrition goals")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"goals":     goals,
+		"isDefault": !isSet,
+		"presets":   nutrition.Presets,
+	})
+}
+
+// UpdateGoals sets the user's nutrition goals
+// PUT /api/users/goals
+func (h *NutritionGoalsHandler) UpdateGoals(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req UpdateGoalsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	goals, err := h.goalsService.UpdateGoals(userID, &services.NutritionGoalsInput{
+		Preset:      req.Preset,
+		Calories:    req.Calories,
+		MacroUnit:   req.MacroUnit,
+		Protein:     req.Protein,
+		Carbs:       req.Carbs,
+		Fat:         req.Fat,
+		Fiber:       req.Fiber,
+		SodiumLimit: req.SodiumLimit,
+		SugarLimit:  req.SugarLimit,
+		Metrics:     req.Calculator,
+	})
+	if err != nil {
+		respondWithError(c, err, "failed to update nutrition goals")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"goals": goals,
+	})
+}
+
+// EstimateGoals estimates daily targets from body metrics without saving them
+// POST /api/users/goals/estimate
+func (h *NutritionGoalsHandler) EstimateGoals(c *gin.Context) {
+	var req EstimateGoalsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	targets, err := h.goalsService.Estimate(req.BodyMetrics, req.Preset)
+	if err != nil {
+		respondWithError(c, err, "failed to estimate nutrition goals")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"targets": targets,
+		"bmr":     nutrition.BMR(req.BodyMetrics),
+		"tdee":    nutrition.TDEE(req.BodyMetrics),
+	})
+}
+
+// CompareGoals compares a day's nutrient totals with the user's goals
+// POST /api/users/goals/compare
+func (h *NutritionGoalsHandler) CompareGoals(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var totals nutrition.Totals
+	if err := c.ShouldBindJSON(&totals); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	comparisons, err := h.goalsService.CompareWithGoals(userID, totals)
+	if err != nil {
+		respondWithError(c, err, "failed to compare with nutrition goals")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comparisons": comparisons,
+	})
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+type OnboardingHandler struct {
+	onboardingService services.OnboardingService
+}
+
+func NewOnboardingHandler(onboardingService services.OnboardingService) *OnboardingHandler {
+	return &OnboardingHandler{
+		onboardingService: onboardingService,
+	}
+}
+
+// GetProgress returns the onboarding steps and the user's progress through them
+// GET /api/onboarding
+func (h *OnboardingHandler) GetProgress(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	state, err := h.onboardingService.GetProgress(userID)
+	if err != nil {
+		respondWithError(c, err, "failed to load onboarding progress")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"onboarding": state,
+	})
+}
+
+// SubmitStep validates and stores the answers for one onboarding step
+// POST /api/onboarding/steps/:step
+func (h *OnboardingHandler) SubmitStep(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var data map[string]interface{}
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	state, err := h.onboardingService.SubmitStep(userID, c.Param("step"), data)
+	if err != nil {
+		respondWithError(c, err, "failed to submit onboarding step")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"onboarding": state,
+	})
+}
+
+// SkipStep marks an onboarding step as skipped
+// POST /api/onboarding/steps/:step/skip
+func (h *OnboardingHandler) SkipStep(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	state, err := h.onboardingService.SkipStep(userID, c.Param("step"))
+	if err != nil {
+		respondWithError(c, err, "failed to skip onboarding step")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"onboarding": state,
+	})
+}
+
+// CompleteOnboarding skips any remaining steps and marks onboarding as complete
+// POST /api/auth/onboarding/complete
+func (h *OnboardingHandler) CompleteOnboarding(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	user, err := h.onboardingService.CompleteAll(userID)
+	if err != nil {
+		respondWithError(c, err, "failed to complete onboarding")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": user.ToPublicUser(),
+	})
+}
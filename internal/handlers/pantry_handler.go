@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+type PantryHandler struct {
+	pantryService services.PantryService
+}
+
+func NewPantryHandler(pantryService services.PantryService) *PantryHandler {
+	return &PantryHandler{
+		pantryService: pantryService,
+	}
+}
+
+// UpdatePantryRequest represents the update pantry request body
+type UpdatePantryRequest struct {
+	Items []string `json:"items" binding:"required"`
+}
+
+// CookableRequest represents the cookable recipes request body. Every
+// field is optional; an empty body searches with the user's pantry.
+type CookableRequest struct {
+	Ingredients   []string `json:"ingredients"`
+	UsePantry     bool     `json:"usePantry"`
+	AssumeStaples *bool    `json:"assumeStaples"`
+	MaxMissing    *int     `json:"maxMissing"`
+	Limit         int      `json:"limit"`
+}
+
+// GetPantry returns the ingredients the user keeps on hand
+// GET /api/users/pantry
+func (h *PantryHandler) GetPantry(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	items, err := h.pantryService.Get(userID)
+	if err != nil {
+		respondWithError(c, err, "failed to get pantry")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+	})
+}
+
+// UpdatePantry replaces the ingredients the user keeps on hand
+// PUT /api/users/pantry
+func (h *PantryHandler) UpdatePantry(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req UpdatePantryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	items, err := h.pantryService.Update(userID, req.Items)
+	if err != nil {
+		respondWithError(c, err, "failed to update pantry")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+	})
+}
+
+// CookableRecipes finds recipes to cook with the ingredients on hand, sent
+// as "ingredients" or taken from the user's pantry. Results are ranked by
+// the share of each recipe's ingredients on hand and list what is missing.
+// Staples such as salt, oil and water count as on hand unless
+// "assumeStaples" is false. Accepts the list filters as query parameters.
+// POST /api/recipes/cookable
+func (h *PantryHandler) CookableRecipes(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req CookableRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	filters, err := parseRecipeFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	params := services.CookableParams{
+		Ingredients:   req.Ingredients,
+		UsePantry:     req.UsePantry,
+		AssumeStaples: req.AssumeStaples == nil || *req.AssumeStaples,
+		MaxMissing:    req.MaxMissing,
+		Limit:         req.Limit,
+		Filters:       filters,
+	}
+	if params.Limit == 0 {
+		params.Limit = services.DefaultCookableLimit
+	}
+
+	recipes, err := h.pantryService.Cookable(userID, params)
+	if err != nil {
+		respondWithError(c, err, "failed to find cookable recipes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": recipes,
+	})
+}
@@ -0,0 +1,412 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/pagination"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+// maxImportSize limits the size of an HTML page sent for import
+const maxImportSize = 5 << 20
+
+type RecipeHandler struct {
+	recipeService services.RecipeService
+}
+
+func NewRecipeHandler(recipeService services.RecipeService) *RecipeHandler {
+	return &RecipeHandler{
+		recipeService: recipeService,
+	}
+}
+
+// RecipeRequest represents the create/update recipe request body
+type RecipeRequest struct {
+	Name         string                    `json:"name" binding:"required"`
+	Description  string                    `json:"description"`
+	Category     string                    `json:"category" binding:"required"`
+	Cuisine      string                    `json:"cuisine"`
+	PrepTime     int                       `json:"prepTime"`
+	CookTime     int                       `json:"cookTime"`
+	Servings     int                       `json:"servings"`
+	Difficulty   string                    `json:"difficulty"`
+	Ingredients  []models.RecipeIngredient `json:"ingredients"`
+	Instructions []string                  `json:"instructions"`
+	Nutrition    models.RecipeNutrition    `json:"nutrition"`
+	Tags         []string                  `json:"tags"`
+	ImageURL     string                    `json:"imageUrl"`
+	IsPublic     *bool                     `json:"isPublic"`
+	IsFeatured   *bool                     `json:"isFeatured"`
+
+	AllergenOverrides models.AllergenOverrides `json:"allergenOverrides"`
+
+	// ChangeSummary and BaseRevision describe an edit for the recipe's
+	// revision history; see services.RecipeInput
+	ChangeSummary string `json:"changeSummary"`
+	BaseRevision  *int   `json:"baseRevision"`
+}
+
+func (r *RecipeRequest) toInput() *services.RecipeInput {
+	return &services.RecipeInput{
+		Name:         r.Name,
+		Description:  r.Description,
+		Category:     r.Category,
+		Cuisine:      r.Cuisine,
+		PrepTime:     r.PrepTime,
+		CookTime:     r.CookTime,
+		Servings:     r.Servings,
+		Difficulty:   r.Difficulty,
+		Ingredients:  r.Ingredients,
+		Instructions: r.Instructions,
+		Nutrition:    r.Nutrition,
+		Tags:         r.Tags,
+		ImageURL:     r.ImageURL,
+		IsPublic:     r.IsPublic,
+		IsFeatured:   r.IsFeatured,
+
+		AllergenOverrides: r.AllergenOverrides,
+		ChangeSummary:     r.ChangeSummary,
+		BaseRevision:      r.BaseRevision,
+	}
+}
+
+// parseRecipeFilters reads the shared recipe filter parameters. Facet
+// filters accept comma-separated values, e.g. ?cuisine=Italian,Greek.
+func parseRecipeFilters(c *gin.Context) (services.RecipeListParams, error) {
+	params := services.RecipeListParams{
+		Categories:         splitQueryList(c.Query("category")),
+		Cuisines:           splitQueryList(c.Query("cuisine")),
+		Difficulties:       splitQueryList(c.Query("difficulty")),
+		Tags:               splitQueryList(c.Query("tags")),
+		TagMatch:           c.Query("tagMatch"),
+		ExcludeMyAllergens: c.Query("excludeAllergens") == "true",
+		MatchMyDiet:        c.Query("matchDiet") == "true",
+		Mine:               c.Query("mine") == "true",
+	}
+
+	if v := c.Query("maxTotalTime"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return params, fmt.Errorf("maxTotalTime must be a whole number of minutes")
+		}
+		params.MaxTotalTime = n
+	}
+	for name, dest := range map[string]**float64{
+		"minCalories": &params.MinCalories,
+		"maxCalories": &params.MaxCalories,
+	} {
+		if v := c.Query(name); v != "" {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return params, fmt.Errorf("%s must be a number", name)
+			}
+			*dest = &n
+		}
+	}
+
+	return params, nil
+}
+
+// splitQueryList splits a comma-separated query value, dropping blanks
+func splitQueryList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// ListRecipes lists public recipes and the current user's own recipes,
+// together with facet counts for the filters.
+// Filters: ?category, ?cuisine, ?difficulty, ?tags (with ?tagMatch=all|any),
+// ?maxTotalTime, ?minCalories, ?maxCalories, ?excludeAllergens=true,
+// ?matchDiet=true and ?mine=true. Paginate with ?limit and ?cursor or ?page;
+// sort with e.g. ?sort=-rating,name.
+// GET /api/recipes
+func (h *RecipeHandler) ListRecipes(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	params, err := parseRecipeFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	page, err := pagination.Parse(c, services.RecipePagination)
+	if err != nil {
+		respondWithError(c, err, "invalid pagination")
+		return
+	}
+
+	recipes, result, err := h.recipeService.List(userID, params, page)
+	if err != nil {
+		respondWithError(c, err, "failed to list recipes")
+		return
+	}
+
+	facets, err := h.recipeService.Facets(userID, params)
+	if err != nil {
+		respondWithError(c, err, "failed to count recipe facets")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       recipes,
+		"facets":     facets,
+		"pagination": result,
+	})
+}
+
+// SearchRecipes runs a full-text search over recipe names, descriptions,
+// cuisines, tags and ingredients. ?q supports prefixes, "quoted phrases" and
+// -excluded terms, e.g. ?q=chicken -mushroom. Accepts the list filters too;
+// results are ranked by relevance and paginated with ?page.
+// GET /api/recipes/search
+func (h *RecipeHandler) SearchRecipes(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "q is required",
+		})
+		return
+	}
+
+	params, err := parseRecipeFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	page, err := pagination.Parse(c, services.RecipeSearchPagination)
+	if err != nil {
+		respondWithError(c, err, "invalid pagination")
+		return
+	}
+
+	results, result, err := h.recipeService.Search(userID, query, params, page)
+	if err != nil {
+		respondWithError(c, err, "failed to search recipes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       results,
+		"pagination": result,
+	})
+}
+
+// GetRecipe returns a single recipe. Ingredient quantities are as written,
+// which is what edits must send back; ?units=metric or ?units=us converts
+// them for display.
+// GET /api/recipes/:id
+func (h *RecipeHandler) GetRecipe(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	recipe, system, err := h.recipeService.GetInUnits(userID, c.Param("id"), c.Query("units"))
+	if err != nil {
+		respondWithError(c, err, "failed to get recipe")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recipe": recipe,
+		"units":  system,
+	})
+}
+
+// ScaleRecipe returns a recipe scaled to ?servings=N, with nutrition totals
+// for all servings. Quantities are shown in the user's unit system unless
+// ?units=metric, ?units=us or ?units=original is given.
+// GET /api/recipes/:id/scaled
+func (h *RecipeHandler) ScaleRecipe(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	servings, err := strconv.Atoi(c.Query("servings"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "servings must be a whole number",
+		})
+		return
+	}
+
+	recipe, system, err := h.recipeService.Scale(userID, c.Param("id"), servings, c.Query("units"))
+	if err != nil {
+		respondWithError(c, err, "failed to scale recipe")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recipe": recipe,
+		"units":  system,
+	})
+}
+
+// CreateRecipe creates a recipe owned by the current user
+// POST /api/recipes
+func (h *RecipeHandler) CreateRecipe(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req RecipeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	recipe, err := h.recipeService.Create(userID, req.toInput())
+	if err != nil {
+		respondWithError(c, err, "failed to create recipe")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"recipe": recipe,
+	})
+}
+
+// UpdateRecipe replaces a recipe
+// PUT /api/recipes/:id
+func (h *RecipeHandler) UpdateRecipe(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req RecipeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	recipe, err := h.recipeService.Update(userID, c.Param("id"), req.toInput())
+	if err != nil {
+		respondWithError(c, err, "failed to update recipe")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recipe": recipe,
+	})
+}
+
+// ForkRecipe copies a recipe into a new private recipe of the caller that
+// links back to the original
+// POST /api/recipes/:id/fork
+func (h *RecipeHandler) ForkRecipe(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	recipe, err := h.recipeService.Fork(userID, c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to fork recipe")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"recipe": recipe,
+	})
+}
+
+// DeleteRecipe deletes a recipe
+// DELETE /api/recipes/:id
+func (h *RecipeHandler) DeleteRecipe(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	if err := h.recipeService.Delete(userID, c.Param("id")); err != nil {
+		respondWithError(c, err, "failed to delete recipe")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "recipe deleted",
+	})
+}
+
+// ImportRecipe extracts a recipe draft from a saved HTML page. The page is
+// sent as the request body, as {"html": "..."} JSON, or as a multipart
+// "file" upload; URLs are never fetched. The draft is not saved.
+// POST /api/recipes/import
+func (h *RecipeHandler) ImportRecipe(c *gin.Context) {
+	if _, exists := middleware.GetUserID(c); !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	page, err := readUpload(c, maxImportSize, "html")
+	if err != nil {
+		respondWithUploadError(c, err, "page must be at most 5 MB")
+		return
+	}
+
+	result, err := h.recipeService.ImportDraft(bytes.NewReader(page))
+	if err != nil {
+		respondWithError(c, err, "failed to import recipe")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/pagination"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+type ReviewHandler struct {
+	reviewService services.ReviewService
+}
+
+func NewReviewHandler(reviewService services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// ReviewRequest represents the create/update review request body
+type ReviewRequest struct {
+	Rating   int    `json:"rating" binding:"required"`
+	Comment  string `json:"comment"`
+	PhotoURL string `json:"photoUrl"`
+}
+
+func (r *ReviewRequest) toInput() *services.ReviewInput {
+	return &services.ReviewInput{
+		Rating:   r.Rating,
+		Comment:  r.Comment,
+		PhotoURL: r.PhotoURL,
+	}
+}
+
+// ListReviews lists a recipe's reviews, newest first by default.
+// Sort with ?sort=-helpful or ?sort=-rating.
+// GET /api/recipes/:id/reviews
+func (h *ReviewHandler) ListReviews(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	page, err := pagination.Parse(c, services.ReviewPagination)
+	if err != nil {
+		respondWithError(c, err, "invalid pagination")
+		return
+	}
+
+	reviews, result, err := h.reviewService.List(userID, c.Param("id"), page)
+	if err != nil {
+		respondWithError(c, err, "failed to list reviews")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       reviews,
+		"pagination": result,
+	})
+}
+
+// CreateReview reviews a recipe
+// POST /api/recipes/:id/reviews
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	review, err := h.reviewService.Create(userID, c.Param("id"), req.toInput())
+	if err != nil {
+		respondWithError(c, err, "failed to create review")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"review": review,
+	})
+}
+
+// UpdateReview replaces the current user's review
+// PUT /api/recipes/:id/reviews/:reviewId
+func (h *ReviewHandler) UpdateReview(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	review, err := h.reviewService.Update(userID, c.Param("id"), c.Param("reviewId"), req.toInput())
+	if err != nil {
+		respondWithError(c, err, "failed to update review")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"review": review,
+	})
+}
+
+// DeleteReview deletes a review
+// DELETE /api/recipes/:id/reviews/:reviewId
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	if err := h.reviewService.Delete(userID, c.Param("id"), c.Param("reviewId")); err != nil {
+		respondWithError(c, err, "failed to delete review")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "review deleted",
+	})
+}
+
+// MarkHelpful marks a review as helpful
+// POST /api/recipes/:id/reviews/:reviewId/helpful
+func (h *ReviewHandler) MarkHelpful(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	count, err := h.reviewService.Vote(userID, c.Param("id"), c.Param("reviewId"))
+	if err != nil {
+		respondWithError(c, err, "failed to vote on review")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviewId":     c.Param("reviewId"),
+		"isHelpful":    true,
+		"helpfulCount": count,
+	})
+}
+
+// UnmarkHelpful removes the current user's helpful vote
+// DELETE /api/recipes/:id/reviews/:reviewId/helpful
+func (h *ReviewHandler) UnmarkHelpful(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	count, err := h.reviewService.Unvote(userID, c.Param("id"), c.Param("reviewId"))
+	if err != nil {
+		respondWithError(c, err, "failed to remove vote")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviewId":     c.Param("reviewId"),
+		"isHelpful":    false,
+		"helpfulCount": count,
+	})
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/pagination"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+type RevisionHandler struct {
+	revisionService services.RevisionService
+	recipeService   services.RecipeService
+}
+
+func NewRevisionHandler(revisionService services.RevisionService, recipeService services.RecipeService) *RevisionHandler {
+	return &RevisionHandler{
+		revisionService: revisionService,
+		recipeService:   recipeService,
+	}
+}
+
+// ListRevisions lists a recipe's revisions, newest first, without their
+// snapshots
+// GET /api/recipes/:id/revisions
+func (h *RevisionHandler) ListRevisions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	page, err := pagination.Parse(c, services.RevisionPagination)
+	if err != nil {
+		respondWithError(c, err, "invalid pagination")
+		return
+	}
+
+	revisions, result, err := h.revisionService.List(userID, c.Param("id"), page)
+	if err != nil {
+		respondWithError(c, err, "failed to list revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       revisions,
+		"pagination": result,
+	})
+}
+
+// GetRevision returns a revision with the recipe's fields as it saved them
+// GET /api/recipes/:id/revisions/:number
+func (h *RevisionHandler) GetRevision(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		respondWithError(c, services.ErrRevisionNotFound, "failed to get revision")
+		return
+	}
+
+	revision, err := h.revisionService.Get(userID, c.Param("id"), number)
+	if err != nil {
+		respondWithError(c, err, "failed to get revision")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revision": revision,
+	})
+}
+
+// DiffRevisions lists the fields changed between ?from and ?to. Without ?to
+// the latest revision is compared, and without ?from the one before ?to.
+// GET /api/recipes/:id/revisions/diff
+func (h *RevisionHandler) DiffRevisions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	var numbers [2]int
+	for i, key := range []string{"from", "to"} {
+		v := c.Query(key)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": key + " must be a revision number",
+			})
+			return
+		}
+		numbers[i] = n
+	}
+
+	diff, err := h.revisionService.Diff(userID, c.Param("id"), numbers[0], numbers[1])
+	if err != nil {
+		respondWithError(c, err, "failed to diff revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"diff": diff,
+	})
+}
+
+// RestoreRevision sets a recipe back to an earlier revision, recording the
+// restore as a new revision
+// POST /api/recipes/:id/revisions/:number/restore
+func (h *RevisionHandler) RestoreRevision(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		respondWithError(c, services.ErrRevisionNotFound, "failed to restore revision")
+		return
+	}
+
+	recipe, err := h.recipeService.Restore(userID, c.Param("id"), number)
+	if err != nil {
+		respondWithError(c, err, "failed to restore revision")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recipe": recipe,
+	})
+}
+
+// GetUpstreamChanges lists the changes made to a fork's original since it
+// was forked
+// GET /api/recipes/:id/upstream
+func (h *RevisionHandler) GetUpstreamChanges(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	upstream, err := h.revisionService.Upstream(userID, c.Param("id"))
+	if err != nil {
+		respondWithError(c, err, "failed to get upstream changes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"upstream": upstream,
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/meal-planner/backend/internal/middleware"
+	"github.com/meal-planner/backend/internal/services"
+)
+
+type SimilarityHandler struct {
+	similarityService services.SimilarityService
+}
+
+func NewSimilarityHandler(similarityService services.SimilarityService) *SimilarityHandler {
+	return &SimilarityHandler{
+		similarityService: similarityService,
+	}
+}
+
+// SimilarRecipes recommends recipes like the given one, most similar first.
+// Accepts the list filters; unlike listings, recipes with the caller's
+// allergens and outside their diet are left out unless
+// ?excludeAllergens=false or ?matchDiet=false. Return up to ?limit recipes.
+// GET /api/recipes/:id/similar
+func (h *SimilarityHandler) SimilarRecipes(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	params, err := parseRecipeFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	params.ExcludeMyAllergens = c.Query("excludeAllergens") != "false"
+	params.MatchMyDiet = c.Query("matchDiet") != "false"
+
+	limit := services.DefaultSimilarLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "limit must be a positive integer",
+			})
+			return
+		}
+		limit = n
+	}
+
+	recipes, err := h.similarityService.Similar(userID, c.Param("id"), params, limit)
+	if err != nil {
+		respondWithError(c, err, "failed to find similar recipes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": recipes,
+	})
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readUpload reads a file sent as the request body or as a multipart "file"
+// upload, reading at most maxSize bytes of the request. When jsonField is
+// set, a JSON body holding the file as that string field is accepted too.
+func readUpload(c *gin.Context, maxSize int64, jsonField string) ([]byte, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize)
+
+	var data []byte
+	switch {
+	case c.ContentType() == "multipart/form-data":
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			if isTooLarge(err) {
+				return nil, err
+			}
+			return nil, errors.New("file is required")
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		if data, err = io.ReadAll(io.LimitReader(file, maxSize)); err != nil {
+			return nil, err
+		}
+
+	case jsonField != "" && c.ContentType() == "application/json":
+		var req map[string]any
+		if err := c.ShouldBindJSON(&req); err != nil && isTooLarge(err) {
+			return nil, err
+		}
+		value, _ := req[jsonField].(string)
+		if value == "" {
+			return nil, errors.New(jsonField + " is required")
+		}
+		data = []byte(value)
+
+	default:
+		var err error
+		if data, err = io.ReadAll(c.Request.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, errors.New("file is empty")
+	}
+	return data, nil
+}
+
+// respondWithUploadError writes the response for a failed readUpload:
+// tooLargeMsg with 413 when the request was over its limit, otherwise the
+// error with 400
+func respondWithUploadError(c *gin.Context, err error, tooLargeMsg string) {
+	if isTooLarge(err) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": tooLargeMsg,
+		})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": err.Error(),
+	})
+}
+
+// isTooLarge reports whether err comes from reading past a request's limit
+func isTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
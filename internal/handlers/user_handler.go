@@ -35,6 +35,10 @@ type ChangePasswordRequest struct {
 type UpdatePreferencesRequest struct {
 	Theme         string `json:"theme"`
 	Notifications *bool  `json:"notifications"`
+	TimeZone      string `json:"timeZone"`
+	Locale        string `json:"locale"`
+	UnitSystem    string `json:"unitSystem"`
+	WeekStart     string `json:"weekStart"`
 }
 
 // UpdateProfile updates the user profile
@@ -136,30 +140,6 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	})
 }
 
-// CompleteOnboarding marks onboarding as complete
-// POST /api/auth/onboarding/complete
-func (h *UserHandler) CompleteOnboarding(c *gin.Context) {
-	userID, exists := middleware.GetUserID(c)
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "unauthorized",
-		})
-		return
-	}
-
-	user, err := h.userService.CompleteOnboarding(userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to complete onboarding",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"user": user.ToPublicUser(),
-	})
-}
-
 // UpdatePreferences updates user preferences
 // PUT /api/auth/preferences
 func (h *UserHandler) UpdatePreferences(c *gin.Context) {
@@ -179,19 +159,40 @@ func (h *UserHandler) UpdatePreferences(c *gin.Context) {
 		return
 	}
 
+	user, err := h.userService.GetUserByID(userID)
+	if err != nil {
+		respondWithError(c, err, "failed to update preferences")
+		return
+	}
+
+	// Start from the stored preferences so fields collected elsewhere
+	// (e.g. during onboarding) are not wiped
 	preferences := &models.UserPreferences{}
+	if user.Preferences != nil {
+		*preferences = *user.Preferences
+	}
 	if req.Theme != "" {
 		preferences.Theme = req.Theme
 	}
 	if req.Notifications != nil {
 		preferences.Notifications = *req.Notifications
 	}
+	if req.TimeZone != "" {
+		preferences.TimeZone = req.TimeZone
+	}
+	if req.Locale != "" {
+		preferences.Locale = req.Locale
+	}
+	if req.UnitSystem != "" {
+		preferences.UnitSystem = req.UnitSystem
+	}
+	if req.WeekStart != "" {
+		preferences.WeekStart = req.WeekStart
+	}
 
-	user, err := h.userService.UpdatePreferences(userID, preferences)
+	user, err = h.userService.UpdatePreferences(userID, preferences)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to update preferences",
-		})
+		respondWithError(c, err, "failed to update preferences")
 		return
 	}
 
@@ -0,0 +1,48 @@
+// Package imaging validates and processes uploaded images in pure Go: it
+// detects the format from magic bytes, applies the EXIF orientation,
+// strips metadata by re-encoding, resizes to responsive variants and
+// computes a BlurHash placeholder.
+package imaging
+
+import "bytes"
+
+// Image formats recognized by Detect
+const (
+	JPEG = "jpeg"
+	PNG  = "png"
+	GIF  = "gif"
+	WebP = "webp"
+	HEIC = "heic"
+)
+
+// Detect returns the format of an image from its magic bytes, or "" when
+// the data is not a recognized image. The name or content type a client
+// sent is never trusted.
+func Detect(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return JPEG
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return PNG
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return GIF
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return WebP
+	case len(data) >= 12 && string(data[4:8]) == "ftyp" && isHEICBrand(string(data[8:12])):
+		return HEIC
+	}
+	return ""
+}
+
+func isHEICBrand(brand string) bool {
+	switch brand {
+	case "heic", "heix", "hevc", "hevx", "mif1", "msf1":
+		return true
+	}
+	return false
+}
+
+// Decodable reports whether Process can decode images of the format
+func Decodable(format string) bool {
+	return format == JPEG || format == PNG || format == GIF
+}
@@ -0,0 +1,298 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"jpeg", []byte{0xFF, 0xD8, 0xFF, 0xE0, 0, 0x10}, JPEG},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00"), PNG},
+		{"gif", []byte("GIF89a\x01\x00"), GIF},
+		{"webp", []byte("RIFF\x24\x00\x00\x00WEBPVP8 "), WebP},
+		{"heic", []byte("\x00\x00\x00\x18ftypheic\x00\x00"), HEIC},
+		{"html", []byte("<html><body>"), ""},
+		{"empty", nil, ""},
+		{"truncated riff", []byte("RIFF"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.data); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// exifJPEG returns a minimal JPEG header with an EXIF orientation
+func exifJPEG(order string, orientation uint16) []byte {
+	var tiff []byte
+	u16 := func(v uint16) []byte {
+		if order == "II" {
+			return []byte{byte(v), byte(v >> 8)}
+		}
+		return []byte{byte(v >> 8), byte(v)}
+	}
+	u32 := func(v uint32) []byte {
+		if order == "II" {
+			return []byte{byte(v), byte(v >> 8), byte(v >> 16), byte(v >> 24)}
+		}
+		return []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+	}
+	tiff = append(tiff, order...)
+	tiff = append(tiff, u16(42)...)
+	tiff = append(tiff, u32(8)...)
+	tiff = append(tiff, u16(2)...)
+	// An unrelated tag before the orientation
+	tiff = append(tiff, u16(0x010F)...)
+	tiff = append(tiff, u16(2)...)
+	tiff = append(tiff, u32(4)...)
+	tiff = append(tiff, u32(0)...)
+	tiff = append(tiff, u16(orientationTag)...)
+	tiff = append(tiff, u16(3)...)
+	tiff = append(tiff, u32(1)...)
+	tiff = append(tiff, u16(orientation)...)
+	tiff = append(tiff, 0, 0)
+	tiff = append(tiff, u32(0)...)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(segment) + 2
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1, byte(length >> 8), byte(length)}
+	return append(data, segment...)
+}
+
+func TestOrientation(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"big endian", exifJPEG("MM", 6), 6},
+		{"little endian", exifJPEG("II", 8), 8},
+		{"out of range", exifJPEG("MM", 12), 1},
+		{"no exif", []byte{0xFF, 0xD8, 0xFF, 0xDA, 0, 2}, 1},
+		{"truncated", exifJPEG("MM", 6)[:20], 1},
+		{"not jpeg", []byte("\x89PNG\r\n\x1a\n"), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Orientation(tt.data); got != tt.want {
+				t.Errorf("Orientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 2×1 image: red on the left, blue on the right
+	red := color.NRGBA{255, 0, 0, 255}
+	blue := color.NRGBA{0, 0, 255, 255}
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.SetNRGBA(0, 0, red)
+	src.SetNRGBA(1, 0, blue)
+
+	tests := []struct {
+		orientation int
+		w, h        int
+		first       color.NRGBA // pixel at 0,0
+	}{
+		{1, 2, 1, red},
+		{2, 2, 1, blue},
+		{3, 2, 1, blue},
+		{4, 2, 1, red},
+		{5, 1, 2, red},
+		{6, 1, 2, red},
+		{7, 1, 2, blue},
+		{8, 1, 2, blue},
+	}
+	for _, tt := range tests {
+		got := Orient(src, tt.orientation)
+		if got.Bounds().Dx() != tt.w || got.Bounds().Dy() != tt.h {
+			t.Errorf("Orient(%d) size = %v, want %dx%d", tt.orientation, got.Bounds().Size(), tt.w, tt.h)
+			continue
+		}
+		if c := got.NRGBAAt(0, 0); c != tt.first {
+			t.Errorf("Orient(%d) pixel 0,0 = %v, want %v", tt.orientation, c, tt.first)
+		}
+	}
+}
+
+func TestFitSize(t *testing.T) {
+	tests := []struct {
+		w, h, maxW, maxH int
+		wantW, wantH     int
+	}{
+		{4000, 3000, 1600, 1600, 1600, 1200},
+		{3000, 4000, 800, 800, 600, 800},
+		{640, 480, 1600, 1600, 640, 480},
+		{10000, 1, 100, 100, 100, 1},
+	}
+	for _, tt := range tests {
+		w, h := FitSize(tt.w, tt.h, tt.maxW, tt.maxH)
+		if w != tt.wantW || h != tt.wantH {
+			t.Errorf("FitSize(%d, %d, %d, %d) = %d, %d, want %d, %d", tt.w, tt.h, tt.maxW, tt.maxH, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func solid(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestResize(t *testing.T) {
+	c := color.NRGBA{200, 100, 50, 255}
+	got := Resize(solid(90, 60, c), 30, 20)
+	if got.Bounds().Dx() != 30 || got.Bounds().Dy() != 20 {
+		t.Fatalf("Resize() size = %v", got.Bounds().Size())
+	}
+	for _, p := range []image.Point{{0, 0}, {29, 19}, {15, 10}} {
+		if px := got.NRGBAAt(p.X, p.Y); px != c {
+			t.Errorf("Resize() pixel %v = %v, want %v", p, px, c)
+		}
+	}
+
+	// Averaging a black and white stripe pattern gives grey
+	stripes := image.NewNRGBA(image.Rect(0, 0, 4, 1))
+	for x := 0; x < 4; x++ {
+		v := uint8(0)
+		if x%2 == 1 {
+			v = 255
+		}
+		stripes.SetNRGBA(x, 0, color.NRGBA{v, v, v, 255})
+	}
+	if px := Resize(stripes, 1, 1).NRGBAAt(0, 0); px.R != 128 {
+		t.Errorf("Resize() of stripes = %v, want grey", px)
+	}
+}
+
+func TestFill(t *testing.T) {
+	got := Fill(solid(1000, 500, color.NRGBA{1, 2, 3, 255}), 320, 320)
+	if got.Bounds().Dx() != 320 || got.Bounds().Dy() != 320 {
+		t.Errorf("Fill() size = %v, want 320x320", got.Bounds().Size())
+	}
+	small := Fill(solid(200, 100, color.NRGBA{1, 2, 3, 255}), 320, 320)
+	if small.Bounds().Dx() != 100 || small.Bounds().Dy() != 100 {
+		t.Errorf("Fill() of a small image size = %v, want 100x100", small.Bounds().Size())
+	}
+}
+
+func decodeBase83(s string) int {
+	v := 0
+	for _, r := range s {
+		v = v*83 + strings.IndexRune(base83Chars, r)
+	}
+	return v
+}
+
+func TestBlurHash(t *testing.T) {
+	hash := BlurHash(solid(32, 24, color.NRGBA{255, 128, 0, 255}), 4, 3)
+	if len(hash) != 4+2*4*3 {
+		t.Fatalf("BlurHash() length = %d, want %d", len(hash), 4+2*4*3)
+	}
+	if got := decodeBase83(hash[:1]); got != 3+2*9 {
+		t.Errorf("size flag = %d, want %d", got, 3+2*9)
+	}
+	dc := decodeBase83(hash[2:6])
+	if r, g, b := dc>>16, dc>>8&0xFF, dc&0xFF; r != 255 || g != 128 || b != 0 {
+		t.Errorf("average color = %d,%d,%d, want 255,128,0", r, g, b)
+	}
+
+	// Detail changes the AC components but not the length
+	gradient := image.NewNRGBA(image.Rect(0, 0, 32, 24))
+	for y := 0; y < 24; y++ {
+		for x := 0; x < 32; x++ {
+			gradient.SetNRGBA(x, y, color.NRGBA{uint8(x * 8), 0, 0, 255})
+		}
+	}
+	if other := BlurHash(gradient, 4, 3); len(other) != len(hash) || other[6:] == hash[6:] {
+		t.Errorf("BlurHash() of a gradient = %q, solid = %q", other, hash)
+	}
+}
+
+func TestProcess(t *testing.T) {
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, solid(2000, 1000, color.NRGBA{0, 128, 0, 255})); err != nil {
+		t.Fatal(err)
+	}
+	result, err := Process(pngData.Bytes(), Variants)
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if result.Format != PNG || result.Width != 2000 || result.Height != 1000 || result.BlurHash == "" {
+		t.Errorf("Process() = %s %dx%d %q", result.Format, result.Width, result.Height, result.BlurHash)
+	}
+	want := map[string][2]int{
+		"original":  {2000, 1000},
+		"large":     {1600, 800},
+		"medium":    {800, 400},
+		"thumbnail": {320, 320},
+	}
+	for _, v := range result.Variants {
+		if size := want[v.Name]; v.Width != size[0] || v.Height != size[1] {
+			t.Errorf("variant %s = %dx%d, want %dx%d", v.Name, v.Width, v.Height, size[0], size[1])
+		}
+		if Detect(v.Data) != JPEG {
+			t.Errorf("variant %s is not a JPEG", v.Name)
+		}
+	}
+}
+
+func TestProcessOrientsAndStripsEXIF(t *testing.T) {
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, solid(40, 20, color.NRGBA{90, 90, 90, 255}), nil); err != nil {
+		t.Fatal(err)
+	}
+	// Insert an EXIF segment rotated 90° after the start-of-image marker
+	exif := exifJPEG("MM", 6)[2:]
+	data := append([]byte{0xFF, 0xD8}, exif...)
+	data = append(data, encoded.Bytes()[2:]...)
+
+	result, err := Process(data, Variants[:1])
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if result.Width != 20 || result.Height != 40 {
+		t.Errorf("Process() size = %dx%d, want 20x40", result.Width, result.Height)
+	}
+	if out := result.Variants[0].Data; bytes.Contains(out, []byte("Exif")) {
+		t.Error("variant still contains EXIF metadata")
+	}
+}
+
+func TestProcessRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"text", []byte("not an image"), ErrUnsupported},
+		{"webp", []byte("RIFF\x24\x00\x00\x00WEBPVP8 "), ErrUnsupported},
+		{"corrupt png", []byte("\x89PNG\r\n\x1a\ngarbage"), ErrUnsupported},
+		// A GIF header declaring 65535×65535 pixels
+		{"too large", []byte("GIF89a\xff\xff\xff\xff\x00\x00\x00;"), ErrTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Process(tt.data, Variants); !errors.Is(err, tt.want) {
+				t.Errorf("Process() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// MaxPixels caps the size of decoded images, so a small file declaring
+// huge dimensions cannot exhaust memory
+const MaxPixels = 40_000_000
+
+// jpegQuality is used for every variant
+const jpegQuality = 85
+
+var (
+	ErrUnsupported = errors.New("unsupported image format")
+	ErrTooLarge    = errors.New("image dimensions are too large")
+)
+
+// VariantSpec describes one output size. Crop fills the box exactly by
+// cropping around the center; otherwise the image is fit within it.
+type VariantSpec struct {
+	Name      string
+	MaxWidth  int
+	MaxHeight int
+	Crop      bool
+}
+
+// Variants are the responsive sizes generated for recipe images. The
+// original is the upright, metadata-free full image, capped in size.
+var Variants = []VariantSpec{
+	{Name: "original", MaxWidth: 4096, MaxHeight: 4096},
+	{Name: "large", MaxWidth: 1600, MaxHeight: 1600},
+	{Name: "medium", MaxWidth: 800, MaxHeight: 800},
+	{Name: "thumbnail", MaxWidth: 320, MaxHeight: 320, Crop: true},
+}
+
+// Variant is one encoded output size
+type Variant struct {
+	Name   string
+	Width  int
+	Height int
+	Data   []byte
+}
+
+// Result is a processed image. Width and Height are of the upright image.
+type Result struct {
+	Format   string
+	Width    int
+	Height   int
+	BlurHash string
+	Variants []Variant
+}
+
+// Process validates an uploaded image by its magic bytes, turns it upright
+// and encodes each variant as a JPEG. Re-encoding drops all metadata, such
+// as EXIF location tags. Transparent areas are flattened onto white.
+func Process(data []byte, specs []VariantSpec) (*Result, error) {
+	format := Detect(data)
+	if !Decodable(format) {
+		if format == "" {
+			return nil, ErrUnsupported
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, format)
+	}
+
+	cfg, err := decodeConfig(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	decoded, err := decode(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	img := Flatten(decoded)
+	if format == JPEG {
+		img = Orient(img, Orientation(data))
+	}
+
+	result := &Result{
+		Format: format,
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+	}
+	for _, spec := range specs {
+		var out *image.NRGBA
+		if spec.Crop {
+			out = Fill(img, spec.MaxWidth, spec.MaxHeight)
+		} else {
+			out = Fit(img, spec.MaxWidth, spec.MaxHeight)
+		}
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, out, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		result.Variants = append(result.Variants, Variant{
+			Name:   spec.Name,
+			Width:  out.Bounds().Dx(),
+			Height: out.Bounds().Dy(),
+			Data:   buf.Bytes(),
+		})
+	}
+	result.BlurHash = BlurHash(Fit(img, 32, 32), BlurHashX, BlurHashY)
+	return result, nil
+}
+
+// Flatten draws an image onto an opaque white background
+func Flatten(src image.Image) *image.NRGBA {
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Over)
+	return dst
+}
+
+func decodeConfig(format string, data []byte) (image.Config, error) {
+	r := bytes.NewReader(data)
+	switch format {
+	case JPEG:
+		return jpeg.DecodeConfig(r)
+	case PNG:
+		return png.DecodeConfig(r)
+	default:
+		return gif.DecodeConfig(r)
+	}
+}
+
+// decode decodes with the decoder of the detected format only. Animated
+// GIFs decode to their first frame.
+func decode(format string, data []byte) (image.Image, error) {
+	r := bytes.NewReader(data)
+	switch format {
+	case JPEG:
+		return jpeg.Decode(r)
+	case PNG:
+		return png.Decode(r)
+	default:
+		return gif.Decode(r)
+	}
+}
@@ -0,0 +1,122 @@
+package imaging
+
+import (
+	"image"
+	"math"
+)
+
+// FitSize returns the size of a w×h image scaled down, keeping its aspect
+// ratio, to fit within maxW×maxH. Images that already fit keep their size.
+func FitSize(w, h, maxW, maxH int) (int, int) {
+	scale := math.Min(float64(maxW)/float64(w), float64(maxH)/float64(h))
+	if scale >= 1 {
+		return w, h
+	}
+	fw := int(math.Round(float64(w) * scale))
+	fh := int(math.Round(float64(h) * scale))
+	return max(fw, 1), max(fh, 1)
+}
+
+// Fit scales an image down to fit within maxW×maxH
+func Fit(src *image.NRGBA, maxW, maxH int) *image.NRGBA {
+	b := src.Bounds()
+	w, h := FitSize(b.Dx(), b.Dy(), maxW, maxH)
+	if w == b.Dx() && h == b.Dy() {
+		return src
+	}
+	return Resize(src, w, h)
+}
+
+// Fill crops an image around its center to the aspect ratio of w×h and
+// scales it down to that size. Images smaller than w×h are cropped to the
+// aspect ratio but not enlarged.
+func Fill(src *image.NRGBA, w, h int) *image.NRGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	cw, ch := sw, sh
+	if sw*h > sh*w {
+		cw = max(sh*w/h, 1)
+	} else {
+		ch = max(sw*h/w, 1)
+	}
+	x0 := b.Min.X + (sw-cw)/2
+	y0 := b.Min.Y + (sh-ch)/2
+	cropped := src.SubImage(image.Rect(x0, y0, x0+cw, y0+ch)).(*image.NRGBA)
+	return Fit(cropped, w, h)
+}
+
+// Resize scales an image to w×h with an area-averaging filter, which keeps
+// downscaled photos smooth without ringing. Pixels are averaged as stored,
+// so images should be opaque; see Flatten.
+func Resize(src *image.NRGBA, w, h int) *image.NRGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+
+	// Resample rows first, then columns
+	xWeights := boxWeights(sw, w)
+	tmp := make([]float64, w*sh*4)
+	for y := 0; y < sh; y++ {
+		row := src.Pix[src.PixOffset(b.Min.X, b.Min.Y+y):]
+		for x, weights := range xWeights {
+			out := tmp[(y*w+x)*4:]
+			for _, wt := range weights {
+				p := row[wt.index*4:]
+				for c := 0; c < 4; c++ {
+					out[c] += float64(p[c]) * wt.weight
+				}
+			}
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	yWeights := boxWeights(sh, h)
+	for y, weights := range yWeights {
+		for x := 0; x < w; x++ {
+			var sum [4]float64
+			for _, wt := range weights {
+				p := tmp[(wt.index*w+x)*4:]
+				for c := 0; c < 4; c++ {
+					sum[c] += p[c] * wt.weight
+				}
+			}
+			d := dst.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				dst.Pix[d+c] = clampByte(sum[c])
+			}
+		}
+	}
+	return dst
+}
+
+type sampleWeight struct {
+	index  int
+	weight float64
+}
+
+// boxWeights returns, for each of n output pixels, the source pixels it
+// covers out of size and how much of each, summing to 1
+func boxWeights(size, n int) [][]sampleWeight {
+	scale := float64(size) / float64(n)
+	weights := make([][]sampleWeight, n)
+	for i := range weights {
+		start := float64(i) * scale
+		end := start + scale
+		for s := int(start); s < size && float64(s) < end; s++ {
+			overlap := math.Min(end, float64(s+1)) - math.Max(start, float64(s))
+			if overlap > 0 {
+				weights[i] = append(weights[i], sampleWeight{index: s, weight: overlap / scale})
+			}
+		}
+	}
+	return weights
+}
+
+func clampByte(v float64) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	}
+	return uint8(v + 0.5)
+}
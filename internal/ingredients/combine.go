@@ -0,0 +1,73 @@
+package ingredients
+
+import "strings"
+
+// String formats the ingredient back into a line such as
+// "2 1/2 cup all-purpose flour, sifted"
+func (i Ingredient) String() string {
+	var parts []string
+	if i.Quantity > 0 {
+		qty := FormatQuantity(i.Quantity)
+		if i.QuantityMax > i.Quantity {
+			qty += "-" + FormatQuantity(i.QuantityMax)
+		}
+		parts = append(parts, qty)
+	}
+	if i.Size != nil {
+		parts = append(parts, "("+FormatQuantity(i.Size.Quantity)+" "+i.Size.Unit+")")
+	}
+	if i.Unit != "" {
+		parts = append(parts, i.Unit)
+	}
+	if i.Name != "" {
+		parts = append(parts, i.Name)
+	}
+	line := strings.Join(parts, " ")
+	if i.Note != "" {
+		line += ", " + i.Note
+	}
+	return line
+}
+
+// Key identifies ingredients that can be added together: the same name,
+// unit and package size
+func (i Ingredient) Key() string {
+	key := strings.ToLower(i.Name) + "|" + i.Unit
+	if i.Size != nil {
+		key += "|" + FormatQuantity(i.Size.Quantity) + " " + i.Size.Unit
+	}
+	return key
+}
+
+// Combine adds up ingredients with the same Key, as a shopping list does,
+// keeping the order in which each first appears. Ranges add up both bounds,
+// so "1-2 onions" and "1 onion" make "2-3 onions". Notes are dropped since
+// they describe preparation rather than what to buy.
+func Combine(items []Ingredient) []Ingredient {
+	var combined []Ingredient
+	index := make(map[string]int)
+	for _, item := range items {
+		item.Note = ""
+		key := item.Key()
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(combined)
+			combined = append(combined, item)
+			continue
+		}
+
+		total := &combined[i]
+		if total.QuantityMax > 0 || item.QuantityMax > 0 {
+			total.QuantityMax = upperBound(*total) + upperBound(item)
+		}
+		total.Quantity += item.Quantity
+	}
+	return combined
+}
+
+func upperBound(i Ingredient) float64 {
+	if i.QuantityMax > 0 {
+		return i.QuantityMax
+	}
+	return i.Quantity
+}
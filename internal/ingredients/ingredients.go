@@ -0,0 +1,287 @@
+// Package ingredients parses free-text ingredient lines such as
+// "2 1/2 cups all-purpose flour, sifted" into a quantity, unit, name and
+// preparation note.
+package ingredients
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Amount is a quantity of a unit, e.g. the "14 oz" in "1 (14 oz) can"
+type Amount struct {
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit,omitempty"`
+}
+
+// Ingredient is a parsed ingredient line
+type Ingredient struct {
+	// Quantity is zero when the line has none, as in "salt to taste"
+	Quantity float64 `json:"quantity,omitempty"`
+	// QuantityMax is the upper bound of a range such as "3-4"; zero otherwise
+	QuantityMax float64 `json:"quantityMax,omitempty"`
+	// Unit is canonical (see NormalizeUnit) unless UnknownUnit is set
+	Unit        string `json:"unit,omitempty"`
+	UnknownUnit bool   `json:"unknownUnit,omitempty"`
+	// Size qualifies a count unit, as in "1 (14 oz) can" or "4 6-ounce fillets"
+	Size *Amount `json:"size,omitempty"`
+	Name string  `json:"name"`
+	Note string  `json:"note,omitempty"`
+}
+
+// quantityModifiers qualify a measure ("1 heaping tablespoon") and are kept
+// in the note
+var quantityModifiers = map[string]bool{
+	"heaping": true, "heaped": true, "level": true, "scant": true,
+	"rounded": true, "generous": true, "good": true,
+}
+
+// notePhrases start a trailing note when they follow the name, as in
+// "salt to taste" or "parsley for garnish"
+var notePhrases = []string{
+	" to taste", " or to taste", " for ", " as needed", " as required",
+	" if needed", " optional", " plus more", " or more", " divided",
+}
+
+var (
+	spacePattern  = regexp.MustCompile(`\s+`)
+	bulletPattern = regexp.MustCompile(`^[-*•·]+\s+`)
+	parenPattern  = regexp.MustCompile(`\(([^()]*)\)`)
+	// abbreviationPattern matches an unrecognized abbreviated unit like "hndfl."
+	abbreviationPattern = regexp.MustCompile(`^[A-Za-z]{1,5}\.$`)
+	// sizeHyphenPattern matches the hyphen in sizes like "14-ounce"
+	sizeHyphenPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)-([A-Za-z])`)
+)
+
+// Parse parses an ingredient line. It never fails: text it cannot read as a
+// quantity or unit is kept in the name.
+func Parse(line string) Ingredient {
+	s := normalize(line)
+	var ing Ingredient
+	var notes []string
+
+	qty, qtyMax, rest, hasQty := readQuantity(s)
+	if !hasQty {
+		qty, rest, hasQty = readArticle(s)
+	}
+	if hasQty {
+		ing.Quantity, ing.QuantityMax = qty, qtyMax
+		s = rest
+
+		if word, after := nextWord(s); strings.EqualFold(word, "dozen") {
+			ing.Quantity *= 12
+			ing.QuantityMax *= 12
+			s = after
+		}
+
+		// "1 (14 oz) can", "2 (about 1 lb) chicken breasts"
+		if strings.HasPrefix(s, "(") {
+			if inner, after, ok := strings.Cut(s[1:], ")"); ok {
+				if size, ok := readSize(inner); ok {
+					ing.Size = size
+				} else if inner = strings.TrimSpace(inner); inner != "" {
+					notes = append(notes, inner)
+				}
+				s = strings.TrimSpace(after)
+			}
+		}
+		// "1 14-ounce can"
+		if ing.Size == nil {
+			if size, after, ok := readContainerSize(s); ok {
+				ing.Size, s = size, after
+			}
+		}
+
+		for {
+			word, after := nextWord(s)
+			if !quantityModifiers[strings.ToLower(word)] {
+				break
+			}
+			notes = append(notes, strings.ToLower(word))
+			s = after
+		}
+	}
+
+	if unit, after, ok := readUnit(s, hasQty); ok {
+		ing.Unit = unit
+		s = after
+		if !hasQty {
+			// "pinch of salt" means one pinch
+			ing.Quantity = 1
+		}
+	} else if hasQty {
+		if word, after := nextWord(s); abbreviationPattern.MatchString(word) && after != "" {
+			ing.Unit = strings.ToLower(strings.TrimSuffix(word, "."))
+			ing.UnknownUnit = true
+			s = after
+		}
+	}
+	if word, after := nextWord(s); ing.Unit != "" && strings.EqualFold(word, "of") {
+		s = after
+	}
+
+	ing.Name, notes = splitNote(s, notes)
+	ing.Note = strings.Join(notes, ", ")
+	return ing
+}
+
+// normalize rewrites unicode fractions and dashes and collapses whitespace
+func normalize(line string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range line {
+		switch {
+		case unicodeFractions[r] != "":
+			if prev >= '0' && prev <= '9' {
+				b.WriteByte(' ')
+			}
+			b.WriteString(unicodeFractions[r])
+		case r == '⁄':
+			b.WriteByte('/')
+		case r == '–' || r == '—':
+			b.WriteByte('-')
+		default:
+			b.WriteRune(r)
+		}
+		prev = r
+	}
+	s := strings.TrimSpace(spacePattern.ReplaceAllString(b.String(), " "))
+	return bulletPattern.ReplaceAllString(s, "")
+}
+
+// nextWord splits off the first space-separated word of s
+func nextWord(s string) (string, string) {
+	word, rest, _ := strings.Cut(s, " ")
+	return word, strings.TrimSpace(rest)
+}
+
+// readArticle reads "a" or "an" as a quantity of one when a unit follows,
+// as in "a pinch of salt" or "a dozen eggs"
+func readArticle(s string) (float64, string, bool) {
+	word, rest := nextWord(s)
+	if !strings.EqualFold(word, "a") && !strings.EqualFold(word, "an") {
+		return 0, s, false
+	}
+	next, _ := nextWord(rest)
+	if _, ok := NormalizeUnit(next); ok || strings.EqualFold(next, "dozen") {
+		return 1, rest, true
+	}
+	if _, ok := numberWords[strings.ToLower(next)]; ok {
+		// "a half cup"
+		qty, _, after, _ := readNumberWord(rest)
+		return qty, after, true
+	}
+	return 0, s, false
+}
+
+// readUnit reads a one- or two-word unit from the start of s. Without a
+// quantity a unit is only read when "of" follows, as in "pinch of salt", and
+// the case-sensitive "T" and "t" are not read at all.
+func readUnit(s string, hasQty bool) (string, string, bool) {
+	words := strings.SplitN(s, " ", 3)
+	for n := min(2, len(words)); n >= 1; n-- {
+		candidate := strings.TrimRight(strings.Join(words[:n], " "), ",")
+		unit, ok := NormalizeUnit(candidate)
+		if !ok || (!hasQty && len(candidate) == 1) {
+			continue
+		}
+		after := strings.TrimSpace(strings.TrimPrefix(s[len(strings.Join(words[:n], " ")):], ","))
+		if after == "" && !hasQty {
+			continue
+		}
+		if !hasQty {
+			if word, _ := nextWord(after); !strings.EqualFold(word, "of") {
+				continue
+			}
+		}
+		return unit, after, true
+	}
+	return "", s, false
+}
+
+// readSize reads a parenthesized size such as "14 oz", "14-ounce" or
+// "about 1 lb"
+func readSize(s string) (*Amount, bool) {
+	s = strings.TrimSpace(s)
+	for _, prefix := range []string{"about ", "approx. ", "approximately ", "roughly "} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	qty, qtyMax, rest, ok := readQuantity(sizeHyphenPattern.ReplaceAllString(s, "$1 $2"))
+	if !ok || qtyMax != 0 {
+		return nil, false
+	}
+	unit, ok := NormalizeUnit(rest)
+	if !ok || IsCountUnit(unit) {
+		return nil, false
+	}
+	return &Amount{Quantity: qty, Unit: unit}, true
+}
+
+// readContainerSize reads a size written before a count unit, as in
+// "14-ounce can" or "6 oz fillets", and returns the rest starting at the unit
+func readContainerSize(s string) (*Amount, string, bool) {
+	qty, qtyMax, rest, ok := readQuantity(sizeHyphenPattern.ReplaceAllString(s, "$1 $2"))
+	if !ok || qtyMax != 0 {
+		return nil, s, false
+	}
+	words := strings.Fields(rest)
+	for _, n := range []int{2, 1} {
+		if len(words) <= n {
+			continue
+		}
+		unit, ok := NormalizeUnit(strings.Join(words[:n], " "))
+		if !ok || IsCountUnit(unit) {
+			continue
+		}
+		if container, _ := NormalizeUnit(words[n]); !IsCountUnit(container) {
+			return nil, s, false
+		}
+		return &Amount{Quantity: qty, Unit: unit}, strings.Join(words[n:], " "), true
+	}
+	return nil, s, false
+}
+
+// splitNote separates the name from parenthesized text, text after the first
+// comma and trailing phrases like "to taste", which are appended to notes
+func splitNote(s string, notes []string) (string, []string) {
+	for _, m := range parenPattern.FindAllStringSubmatch(s, -1) {
+		if inner := strings.TrimSpace(m[1]); inner != "" {
+			notes = append(notes, inner)
+		}
+	}
+	s = parenPattern.ReplaceAllString(s, "")
+
+	name, after, hasComma := strings.Cut(s, ",")
+	lower := strings.ToLower(" " + name)
+	cut := -1
+	for _, phrase := range notePhrases {
+		if i := strings.Index(lower, phrase); i > 0 && (cut < 0 || i < cut) {
+			cut = i
+		}
+	}
+	if cut > 0 {
+		// lower has a leading space, so cut is the index of the phrase's space
+		notes = append(notes, cleanNote(name[cut:]))
+		name = name[:cut-1]
+	}
+	if hasComma {
+		notes = append(notes, cleanNote(after))
+	}
+
+	name = strings.Trim(spacePattern.ReplaceAllString(name, " "), " ,;:.-")
+	if word, rest := nextWord(name); strings.EqualFold(word, "of") && rest != "" {
+		name = rest
+	}
+
+	kept := notes[:0]
+	for _, note := range notes {
+		if note != "" {
+			kept = append(kept, note)
+		}
+	}
+	return name, kept
+}
+
+func cleanNote(s string) string {
+	return strings.Trim(spacePattern.ReplaceAllString(s, " "), " ,;:.")
+}
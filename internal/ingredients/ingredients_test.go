@@ -0,0 +1,459 @@
+package ingredients
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want Ingredient
+	}{
+		{
+			name: "mixed fraction with note",
+			line: "2 1/2 cups all-purpose flour, sifted",
+			want: Ingredient{Quantity: 2.5, Unit: Cup, Name: "all-purpose flour", Note: "sifted"},
+		},
+		{
+			name: "can with parenthesized size",
+			line: "1 (14 oz) can diced tomatoes",
+			want: Ingredient{Quantity: 1, Unit: "can", Size: &Amount{Quantity: 14, Unit: Ounce}, Name: "diced tomatoes"},
+		},
+		{
+			name: "no quantity with to taste",
+			line: "salt to taste",
+			want: Ingredient{Name: "salt", Note: "to taste"},
+		},
+		{
+			name: "to taste after comma",
+			line: "Salt and freshly ground black pepper, to taste",
+			want: Ingredient{Name: "Salt and freshly ground black pepper", Note: "to taste"},
+		},
+		{
+			name: "plain count",
+			line: "3 eggs",
+			want: Ingredient{Quantity: 3, Name: "eggs"},
+		},
+		{
+			name: "count with size adjective",
+			line: "2 large eggs, beaten",
+			want: Ingredient{Quantity: 2, Name: "large eggs", Note: "beaten"},
+		},
+		{
+			name: "simple fraction",
+			line: "1/2 tsp baking soda",
+			want: Ingredient{Quantity: 0.5, Unit: Teaspoon, Name: "baking soda"},
+		},
+		{
+			name: "decimal",
+			line: "1.5 kg potatoes",
+			want: Ingredient{Quantity: 1.5, Unit: Kilogram, Name: "potatoes"},
+		},
+		{
+			name: "leading decimal point",
+			line: ".5 cup sugar",
+			want: Ingredient{Quantity: 0.5, Unit: Cup, Name: "sugar"},
+		},
+		{
+			name: "unit attached to number",
+			line: "500g chicken breast",
+			want: Ingredient{Quantity: 500, Unit: Gram, Name: "chicken breast"},
+		},
+		{
+			name: "unicode fraction",
+			line: "½ cup milk",
+			want: Ingredient{Quantity: 0.5, Unit: Cup, Name: "milk"},
+		},
+		{
+			name: "unicode fraction after whole number",
+			line: "1½ tsp salt",
+			want: Ingredient{Quantity: 1.5, Unit: Teaspoon, Name: "salt"},
+		},
+		{
+			name: "unicode fraction after space",
+			line: "2 ¾ cups water",
+			want: Ingredient{Quantity: 2.75, Unit: Cup, Name: "water"},
+		},
+		{
+			name: "fraction slash",
+			line: "1⁄4 teaspoon cayenne",
+			want: Ingredient{Quantity: 0.25, Unit: Teaspoon, Name: "cayenne"},
+		},
+		{
+			name: "hyphen range",
+			line: "3-4 cloves garlic, minced",
+			want: Ingredient{Quantity: 3, QuantityMax: 4, Unit: "clove", Name: "garlic", Note: "minced"},
+		},
+		{
+			name: "spaced en dash range",
+			line: "2 – 3 tbsp olive oil",
+			want: Ingredient{Quantity: 2, QuantityMax: 3, Unit: Tablespoon, Name: "olive oil"},
+		},
+		{
+			name: "to range",
+			line: "1 to 2 tablespoons honey",
+			want: Ingredient{Quantity: 1, QuantityMax: 2, Unit: Tablespoon, Name: "honey"},
+		},
+		{
+			name: "or range",
+			line: "1 or 2 jalapeños",
+			want: Ingredient{Quantity: 1, QuantityMax: 2, Name: "jalapeños"},
+		},
+		{
+			name: "fraction range",
+			line: "1/2-3/4 cup broth",
+			want: Ingredient{Quantity: 0.5, QuantityMax: 0.75, Unit: Cup, Name: "broth"},
+		},
+		{
+			name: "descending range keeps lower bound only",
+			line: "4-2 apples",
+			want: Ingredient{Quantity: 4, Name: "apples"},
+		},
+		{
+			name: "abbreviation with period",
+			line: "1 tbsp. butter",
+			want: Ingredient{Quantity: 1, Unit: Tablespoon, Name: "butter"},
+		},
+		{
+			name: "capital T is tablespoon",
+			line: "2 T soy sauce",
+			want: Ingredient{Quantity: 2, Unit: Tablespoon, Name: "soy sauce"},
+		},
+		{
+			name: "lower t is teaspoon",
+			line: "1 t vanilla",
+			want: Ingredient{Quantity: 1, Unit: Teaspoon, Name: "vanilla"},
+		},
+		{
+			name: "c is cup",
+			line: "1 c. sugar",
+			want: Ingredient{Quantity: 1, Unit: Cup, Name: "sugar"},
+		},
+		{
+			name: "upper case unit",
+			line: "3 TBSP Butter",
+			want: Ingredient{Quantity: 3, Unit: Tablespoon, Name: "Butter"},
+		},
+		{
+			name: "two word unit",
+			line: "8 fl oz cream",
+			want: Ingredient{Quantity: 8, Unit: FluidOunce, Name: "cream"},
+		},
+		{
+			name: "spelled two word unit",
+			line: "4 fluid ounces orange juice",
+			want: Ingredient{Quantity: 4, Unit: FluidOunce, Name: "orange juice"},
+		},
+		{
+			name: "plural with es",
+			line: "2 pinches red pepper flakes",
+			want: Ingredient{Quantity: 2, Unit: "pinch", Name: "red pepper flakes"},
+		},
+		{
+			name: "pounds abbreviation",
+			line: "2 lbs ground beef",
+			want: Ingredient{Quantity: 2, Unit: Pound, Name: "ground beef"},
+		},
+		{
+			name: "metric volume",
+			line: "250 ml stock",
+			want: Ingredient{Quantity: 250, Unit: Milliliter, Name: "stock"},
+		},
+		{
+			name: "litre spelling",
+			line: "1 litre water",
+			want: Ingredient{Quantity: 1, Unit: Liter, Name: "water"},
+		},
+		{
+			name: "of after unit",
+			line: "2 cups of flour",
+			want: Ingredient{Quantity: 2, Unit: Cup, Name: "flour"},
+		},
+		{
+			name: "article with unit",
+			line: "a pinch of salt",
+			want: Ingredient{Quantity: 1, Unit: "pinch", Name: "salt"},
+		},
+		{
+			name: "unit without quantity",
+			line: "Handful of basil leaves",
+			want: Ingredient{Quantity: 1, Unit: "handful", Name: "basil leaves"},
+		},
+		{
+			name: "article without unit stays in name",
+			line: "a few sprigs of thyme",
+			want: Ingredient{Name: "a few sprigs of thyme"},
+		},
+		{
+			name: "number word",
+			line: "one onion, diced",
+			want: Ingredient{Quantity: 1, Name: "onion", Note: "diced"},
+		},
+		{
+			name: "half a unit",
+			line: "half a cup cream",
+			want: Ingredient{Quantity: 0.5, Unit: Cup, Name: "cream"},
+		},
+		{
+			name: "a dozen",
+			line: "a dozen eggs",
+			want: Ingredient{Quantity: 12, Name: "eggs"},
+		},
+		{
+			name: "numeric dozen",
+			line: "2 dozen clams",
+			want: Ingredient{Quantity: 24, Name: "clams"},
+		},
+		{
+			name: "hyphenated container size",
+			line: "1 14-ounce can coconut milk",
+			want: Ingredient{Quantity: 1, Unit: "can", Size: &Amount{Quantity: 14, Unit: Ounce}, Name: "coconut milk"},
+		},
+		{
+			name: "spaced container size",
+			line: "2 8 oz packages cream cheese, softened",
+			want: Ingredient{Quantity: 2, Unit: "package", Size: &Amount{Quantity: 8, Unit: Ounce}, Name: "cream cheese", Note: "softened"},
+		},
+		{
+			name: "parenthesized hyphenated size",
+			line: "4 (6-ounce) salmon fillets",
+			want: Ingredient{Quantity: 4, Size: &Amount{Quantity: 6, Unit: Ounce}, Name: "salmon fillets"},
+		},
+		{
+			name: "approximate size",
+			line: "2 (about 1 lb) chicken breasts",
+			want: Ingredient{Quantity: 2, Size: &Amount{Quantity: 1, Unit: Pound}, Name: "chicken breasts"},
+		},
+		{
+			name: "parenthesized note after quantity",
+			line: "3 (ripe) bananas",
+			want: Ingredient{Quantity: 3, Name: "bananas", Note: "ripe"},
+		},
+		{
+			name: "parenthesized note in name",
+			line: "1 cup parsley (chopped)",
+			want: Ingredient{Quantity: 1, Unit: Cup, Name: "parsley", Note: "chopped"},
+		},
+		{
+			name: "optional in parentheses",
+			line: "1 tsp chili flakes (optional)",
+			want: Ingredient{Quantity: 1, Unit: Teaspoon, Name: "chili flakes", Note: "optional"},
+		},
+		{
+			name: "for garnish",
+			line: "fresh cilantro for garnish",
+			want: Ingredient{Name: "fresh cilantro", Note: "for garnish"},
+		},
+		{
+			name: "plus more",
+			line: "2 tbsp olive oil plus more for drizzling",
+			want: Ingredient{Quantity: 2, Unit: Tablespoon, Name: "olive oil", Note: "plus more for drizzling"},
+		},
+		{
+			name: "as needed",
+			line: "Vegetable oil as needed",
+			want: Ingredient{Name: "Vegetable oil", Note: "as needed"},
+		},
+		{
+			name: "divided",
+			line: "1 cup sugar, divided",
+			want: Ingredient{Quantity: 1, Unit: Cup, Name: "sugar", Note: "divided"},
+		},
+		{
+			name: "multiple notes",
+			line: "1 (2 lb) bag carrots (organic), peeled and sliced",
+			want: Ingredient{Quantity: 1, Unit: "bag", Size: &Amount{Quantity: 2, Unit: Pound}, Name: "carrots", Note: "organic, peeled and sliced"},
+		},
+		{
+			name: "modifier before unit",
+			line: "1 heaping tablespoon cocoa powder",
+			want: Ingredient{Quantity: 1, Unit: Tablespoon, Name: "cocoa powder", Note: "heaping"},
+		},
+		{
+			name: "count unit",
+			line: "2 sprigs fresh rosemary",
+			want: Ingredient{Quantity: 2, Unit: "sprig", Name: "fresh rosemary"},
+		},
+		{
+			name: "tin is can",
+			line: "1 tin chickpeas, drained",
+			want: Ingredient{Quantity: 1, Unit: "can", Name: "chickpeas", Note: "drained"},
+		},
+		{
+			name: "unrecognized abbreviation is flagged",
+			line: "2 hndfl. spinach",
+			want: Ingredient{Quantity: 2, Unit: "hndfl", UnknownUnit: true, Name: "spinach"},
+		},
+		{
+			name: "bullet and extra whitespace",
+			line: "  -  2   cups   rice  ",
+			want: Ingredient{Quantity: 2, Unit: Cup, Name: "rice"},
+		},
+		{
+			name: "name starting with number word boundary",
+			line: "7-up soda",
+			want: Ingredient{Name: "7-up soda"},
+		},
+		{
+			name: "zero is not a quantity",
+			line: "00 flour",
+			want: Ingredient{Name: "00 flour"},
+		},
+		{
+			name: "quantity inside name",
+			line: "Juice of 1 lemon",
+			want: Ingredient{Name: "Juice of 1 lemon"},
+		},
+		{
+			name: "single letter without quantity is not a unit",
+			line: "T-bone steak",
+			want: Ingredient{Name: "T-bone steak"},
+		},
+		{
+			name: "unit word without of stays in name",
+			line: "can opener",
+			want: Ingredient{Name: "can opener"},
+		},
+		{
+			name: "quantity and unit only",
+			line: "2 cups",
+			want: Ingredient{Quantity: 2, Unit: Cup},
+		},
+		{
+			name: "empty line",
+			line: "   ",
+			want: Ingredient{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.line)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v (size %+v), want %+v (size %+v)", tt.line, got, got.Size, tt.want, tt.want.Size)
+			}
+		})
+	}
+}
+
+func TestNormalizeUnit(t *testing.T) {
+	tests := []struct {
+		unit   string
+		want   string
+		wantOK bool
+	}{
+		{"Tablespoons", Tablespoon, true},
+		{"tbsp.", Tablespoon, true},
+		{"T", Tablespoon, true},
+		{"t", Teaspoon, true},
+		{"TSP", Teaspoon, true},
+		{"grams", Gram, true},
+		{"Fl. Oz.", FluidOunce, true},
+		{"boxes", "box", true},
+		{"loaves", "loaf", true},
+		{"  cups ", Cup, true},
+		{"smidgen", "", false},
+		{"", "", false},
+		{"s", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.unit, func(t *testing.T) {
+			got, ok := NormalizeUnit(tt.unit)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("NormalizeUnit(%q) = %q, %v, want %q, %v", tt.unit, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFormatQuantity(t *testing.T) {
+	tests := []struct {
+		qty  float64
+		want string
+	}{
+		{2, "2"},
+		{2.5, "2 1/2"},
+		{0.25, "1/4"},
+		{1.0 / 3, "1/3"},
+		{0.667, "2/3"},
+		{1.125, "1 1/8"},
+		{0.999, "1"},
+		{2.17, "2.17"},
+		{0.1, "0.1"},
+		{0, "0"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatQuantity(tt.qty); got != tt.want {
+			t.Errorf("FormatQuantity(%v) = %q, want %q", tt.qty, got, tt.want)
+		}
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		in      string
+		qty     float64
+		qtyMax  float64
+		wantErr bool
+	}{
+		{in: "2", qty: 2},
+		{in: "1 1/2", qty: 1.5},
+		{in: "½", qty: 0.5},
+		{in: "0.25", qty: 0.25},
+		{in: "3-4", qty: 3, qtyMax: 4},
+		{in: "two", qty: 2},
+		{in: "2 cups", wantErr: true},
+		{in: "some", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		qty, qtyMax, ok := ParseQuantity(tt.in)
+		if ok == tt.wantErr {
+			t.Errorf("ParseQuantity(%q) ok = %v, want %v", tt.in, ok, !tt.wantErr)
+			continue
+		}
+		if qty != tt.qty || qtyMax != tt.qtyMax {
+			t.Errorf("ParseQuantity(%q) = %v, %v, want %v, %v", tt.in, qty, qtyMax, tt.qty, tt.qtyMax)
+		}
+	}
+}
+
+func TestIngredientString(t *testing.T) {
+	for _, line := range []string{
+		"2 1/2 cup all-purpose flour, sifted",
+		"1 (14 oz) can diced tomatoes",
+		"3-4 clove garlic, minced",
+		"salt, to taste",
+	} {
+		if got := Parse(line).String(); got != line {
+			t.Errorf("Parse(%q).String() = %q", line, got)
+		}
+	}
+}
+
+func TestCombine(t *testing.T) {
+	items := []Ingredient{
+		Parse("1 cup flour, sifted"),
+		Parse("2 onions"),
+		Parse("1/2 cup Flour"),
+		Parse("1-2 onions, diced"),
+		Parse("100 g flour"),
+		Parse("salt to taste"),
+		Parse("salt"),
+	}
+	want := []Ingredient{
+		{Quantity: 1.5, Unit: Cup, Name: "flour"},
+		{Quantity: 3, QuantityMax: 4, Name: "onions"},
+		{Quantity: 100, Unit: Gram, Name: "flour"},
+		{Name: "salt"},
+	}
+
+	if got := Combine(items); !reflect.DeepEqual(got, want) {
+		t.Errorf("Combine() = %+v, want %+v", got, want)
+	}
+}
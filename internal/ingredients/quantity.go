@@ -0,0 +1,148 @@
+package ingredients
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// unicodeFractions maps vulgar fraction characters to ASCII fractions
+var unicodeFractions = map[rune]string{
+	'½': "1/2", '⅓': "1/3", '⅔': "2/3", '¼': "1/4", '¾': "3/4",
+	'⅕': "1/5", '⅖': "2/5", '⅗': "3/5", '⅘': "4/5", '⅙': "1/6",
+	'⅚': "5/6", '⅛': "1/8", '⅜': "3/8", '⅝': "5/8", '⅞': "7/8",
+}
+
+// numberWords are quantities that may be spelled out
+var numberWords = map[string]float64{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+	"half": 0.5, "quarter": 0.25,
+}
+
+const numberPattern = `\d+\s+\d+/\d+|\d+/\d+|\d*\.\d+|\d+`
+
+// quantityPattern matches a number or range at the start of a line, e.g.
+// "2", "1 1/2", "0.5", "3-4" or "1 to 2"
+var quantityPattern = regexp.MustCompile(`^(` + numberPattern + `)(?:\s*(?:-|to|or)\s*(` + numberPattern + `))?`)
+
+// parseNumber parses "2", "1/2", "1 1/2" or "0.5"
+func parseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	if whole, frac, ok := strings.Cut(s, " "); ok {
+		w, okWhole := parseNumber(whole)
+		f, okFrac := parseNumber(strings.TrimSpace(frac))
+		return w + f, okWhole && okFrac && f < 1
+	}
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		n, errNum := strconv.ParseFloat(num, 64)
+		d, errDen := strconv.ParseFloat(den, 64)
+		if errNum != nil || errDen != nil || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	return n, err == nil
+}
+
+// ParseQuantity parses a quantity or range on its own, such as "2", "1 1/2",
+// "½" or "3-4". qtyMax is zero unless s is a range.
+func ParseQuantity(s string) (qty, qtyMax float64, ok bool) {
+	qty, qtyMax, rest, ok := readQuantity(normalize(s))
+	if !ok || rest != "" {
+		return 0, 0, false
+	}
+	return qty, qtyMax, true
+}
+
+// readQuantity reads a leading quantity or range from s and returns the rest
+func readQuantity(s string) (qty, qtyMax float64, rest string, ok bool) {
+	m := quantityPattern.FindStringSubmatchIndex(s)
+	if m == nil {
+		return readNumberWord(s)
+	}
+
+	// The number must end at a word boundary: "7-up" is a name, not 7 of "-up"
+	end := m[1]
+	if end < len(s) && !isQuantityBoundary(s[end]) {
+		if m[4] >= 0 {
+			end = m[3]
+		}
+		if end < len(s) && !isQuantityBoundary(s[end]) {
+			return 0, 0, s, false
+		}
+		m[4] = -1
+	}
+
+	qty, ok = parseNumber(s[m[2]:m[3]])
+	if !ok || qty <= 0 {
+		return 0, 0, s, false
+	}
+	if m[4] >= 0 {
+		if max, ok := parseNumber(s[m[4]:m[5]]); ok && max > qty {
+			qtyMax = max
+		}
+	}
+	return qty, qtyMax, strings.TrimSpace(s[end:]), true
+}
+
+func isQuantityBoundary(c byte) bool {
+	return c == ' ' || c == '(' || c == ',' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// readNumberWord reads a spelled-out quantity such as "two" or "half a"
+func readNumberWord(s string) (float64, float64, string, bool) {
+	word, rest, _ := strings.Cut(s, " ")
+	qty, ok := numberWords[strings.ToLower(word)]
+	if !ok {
+		return 0, 0, s, false
+	}
+	rest = strings.TrimSpace(rest)
+	if qty < 1 {
+		// "half a cup", "a quarter cup"
+		for _, article := range []string{"a ", "an "} {
+			if strings.HasPrefix(strings.ToLower(rest), article) {
+				rest = strings.TrimSpace(rest[len(article):])
+			}
+		}
+	}
+	return qty, 0, rest, true
+}
+
+// FormatQuantity formats a quantity the way recipes write it, using common
+// fractions where they are close enough: 2.5 becomes "2 1/2" and 0.333
+// becomes "1/3". Other values keep up to two decimals.
+func FormatQuantity(q float64) string {
+	if q <= 0 || math.IsNaN(q) || math.IsInf(q, 0) {
+		return "0"
+	}
+
+	whole := math.Floor(q)
+	frac := q - whole
+	for _, f := range []struct {
+		value float64
+		text  string
+	}{
+		{0, ""}, {1.0 / 8, "1/8"}, {1.0 / 4, "1/4"}, {1.0 / 3, "1/3"},
+		{3.0 / 8, "3/8"}, {1.0 / 2, "1/2"}, {5.0 / 8, "5/8"}, {2.0 / 3, "2/3"},
+		{3.0 / 4, "3/4"}, {7.0 / 8, "7/8"}, {1, ""},
+	} {
+		if math.Abs(frac-f.value) > 0.01 {
+			continue
+		}
+		if f.value == 1 {
+			whole++
+		}
+		switch {
+		case f.text == "":
+			return strconv.FormatFloat(whole, 'f', -1, 64)
+		case whole == 0:
+			return f.text
+		default:
+			return strconv.FormatFloat(whole, 'f', -1, 64) + " " + f.text
+		}
+	}
+	return strconv.FormatFloat(math.Round(q*100)/100, 'f', -1, 64)
+}
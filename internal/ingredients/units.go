@@ -0,0 +1,101 @@
+package ingredients
+
+import "strings"
+
+// Canonical units. Parse and NormalizeUnit report units in these forms.
+const (
+	Teaspoon   = "tsp"
+	Tablespoon = "tbsp"
+	Cup        = "cup"
+	FluidOunce = "fl oz"
+	Milliliter = "ml"
+	Centiliter = "cl"
+	Deciliter  = "dl"
+	Liter      = "l"
+	Pint       = "pint"
+	Quart      = "quart"
+	Gallon     = "gallon"
+	Milligram  = "mg"
+	Gram       = "g"
+	Kilogram   = "kg"
+	Ounce      = "oz"
+	Pound      = "lb"
+)
+
+// unitAliases maps lower-case spellings to canonical units. Plurals ending in
+// "s" or "es" are accepted for every alias, so they are not listed.
+var unitAliases = map[string]string{
+	"tsp": Teaspoon, "tsps": Teaspoon, "teaspoon": Teaspoon, "tspn": Teaspoon,
+	"tbsp": Tablespoon, "tbs": Tablespoon, "tbl": Tablespoon, "tbsps": Tablespoon, "tablespoon": Tablespoon, "tblsp": Tablespoon,
+	"cup": Cup, "c": Cup,
+	"fl oz": FluidOunce, "fl. oz": FluidOunce, "fluid ounce": FluidOunce, "floz": FluidOunce,
+	"ml": Milliliter, "milliliter": Milliliter, "millilitre": Milliliter, "mls": Milliliter,
+	"cl": Centiliter, "centiliter": Centiliter, "centilitre": Centiliter,
+	"dl": Deciliter, "deciliter": Deciliter, "decilitre": Deciliter,
+	"l": Liter, "liter": Liter, "litre": Liter, "ltr": Liter,
+	"pint": Pint, "pt": Pint,
+	"quart": Quart, "qt": Quart,
+	"gallon": Gallon, "gal": Gallon,
+	"mg": Milligram, "milligram": Milligram, "milligramme": Milligram,
+	"g": Gram, "gr": Gram, "gram": Gram, "gramme": Gram, "grm": Gram,
+	"kg": Kilogram, "kilo": Kilogram, "kilogram": Kilogram, "kilogramme": Kilogram,
+	"oz": Ounce, "ounce": Ounce,
+	"lb": Pound, "lbs": Pound, "pound": Pound,
+
+	// Count units have no fixed size; they are kept as written
+	"bag": "bag", "bottle": "bottle", "box": "box", "bunch": "bunch",
+	"can": "can", "tin": "can", "carton": "carton", "clove": "clove",
+	"container": "container", "cube": "cube", "dash": "dash", "drop": "drop",
+	"ear": "ear", "envelope": "envelope", "fillet": "fillet", "filet": "fillet",
+	"handful": "handful", "head": "head", "jar": "jar", "knob": "knob",
+	"loaf": "loaf", "loaves": "loaf", "package": "package", "pkg": "package",
+	"pack": "package", "packet": "packet", "pkt": "packet", "piece": "piece",
+	"pc": "piece", "pinch": "pinch", "sheet": "sheet", "slice": "slice",
+	"sprig": "sprig", "stalk": "stalk", "stick": "stick", "strip": "strip",
+	"tub": "tub",
+}
+
+// caseSensitiveUnits are abbreviations whose meaning depends on case
+var caseSensitiveUnits = map[string]string{
+	"T":   Tablespoon,
+	"Tb":  Tablespoon,
+	"Tbl": Tablespoon,
+	"t":   Teaspoon,
+}
+
+// NormalizeUnit returns the canonical form of a unit such as "Tbsp.",
+// "tablespoons" or "T", and false when the unit is not recognized.
+func NormalizeUnit(unit string) (string, bool) {
+	unit = strings.TrimSpace(unit)
+	if canonical, ok := caseSensitiveUnits[strings.TrimSuffix(unit, ".")]; ok {
+		return canonical, true
+	}
+
+	unit = strings.ToLower(strings.Join(strings.Fields(unit), " "))
+	unit = strings.TrimSuffix(unit, ".")
+	if unit == "" {
+		return "", false
+	}
+	if canonical, ok := unitAliases[unit]; ok {
+		return canonical, true
+	}
+	for _, suffix := range []string{"es", "s"} {
+		if singular := strings.TrimSuffix(unit, suffix); singular != unit && len(singular) > 1 {
+			if canonical, ok := unitAliases[singular]; ok {
+				return canonical, true
+			}
+		}
+	}
+	return "", false
+}
+
+// IsCountUnit reports whether unit is a count unit such as "clove" or "can"
+// rather than a measure of volume or weight.
+func IsCountUnit(unit string) bool {
+	switch unit {
+	case Teaspoon, Tablespoon, Cup, FluidOunce, Milliliter, Centiliter, Deciliter,
+		Liter, Pint, Quart, Gallon, Milligram, Gram, Kilogram, Ounce, Pound:
+		return false
+	}
+	return unit != ""
+}
@@ -0,0 +1,161 @@
+package locale
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Defaults applied when a user has not set a preference
+const (
+	DefaultTimeZone   = "UTC"
+	DefaultLocale     = "en-US"
+	DefaultUnitSystem = UnitSystemUS
+)
+
+// Measurement systems for rendering ingredient quantities
+const (
+	UnitSystemMetric = "metric"
+	UnitSystemUS     = "us"
+)
+
+var (
+	ErrInvalidTimeZone   = errors.New("invalid time zone")
+	ErrInvalidLocale     = errors.New("invalid locale")
+	ErrInvalidUnitSystem = errors.New("unit system must be one of: metric, us")
+	ErrInvalidWeekStart  = errors.New("week start must be one of: sunday, monday")
+)
+
+var localeRegex = regexp.MustCompile(`^([a-zA-Z]{2,3})(?:[-_]([a-zA-Z]{2}))?$`)
+
+// NormalizeTimeZone validates an IANA time zone name
+func NormalizeTimeZone(tz string) (string, error) {
+	tz = strings.TrimSpace(tz)
+	if tz == "" {
+		return DefaultTimeZone, nil
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return "", ErrInvalidTimeZone
+	}
+	return tz, nil
+}
+
+// NormalizeLocale validates a language tag such as "en", "en-GB" or "pt_BR"
+// and returns it in canonical "ll-CC" form
+func NormalizeLocale(tag string) (string, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return DefaultLocale, nil
+	}
+	m := localeRegex.FindStringSubmatch(tag)
+	if m == nil {
+		return "", ErrInvalidLocale
+	}
+	if m[2] == "" {
+		return strings.ToLower(m[1]), nil
+	}
+	return strings.ToLower(m[1]) + "-" + strings.ToUpper(m[2]), nil
+}
+
+// NormalizeUnitSystem validates a measurement system
+func NormalizeUnitSystem(system string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(system)) {
+	case "":
+		return DefaultUnitSystem, nil
+	case UnitSystemMetric:
+		return UnitSystemMetric, nil
+	case UnitSystemUS, "imperial", "us_customary":
+		return UnitSystemUS, nil
+	}
+	return "", ErrInvalidUnitSystem
+}
+
+// NormalizeWeekStart validates the first day of the week
+func NormalizeWeekStart(day string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(day)) {
+	case "", "sunday":
+		return "sunday", nil
+	case "monday":
+		return "monday", nil
+	}
+	return "", ErrInvalidWeekStart
+}
+
+// LoadLocation returns the location for a time zone name, falling back to UTC
+func LoadLocation(tz string) *time.Location {
+	if tz == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// WeekBounds returns the start (inclusive) and end (exclusive) of the week
+// containing t, as seen in the given location. The returned instants are in
+// UTC, ready to be stored or compared with persisted timestamps.
+func WeekBounds(t time.Time, loc *time.Location, weekStart string) (time.Time, time.Time) {
+	firstDay := time.Sunday
+	if weekStart == "monday" {
+		firstDay = time.Monday
+	}
+
+	local := t.In(loc)
+	offset := (int(local.Weekday()) - int(firstDay) + 7) % 7
+	start := time.Date(local.Year(), local.Month(), local.Day()-offset, 0, 0, 0, 0, loc)
+	end := time.Date(start.Year(), start.Month(), start.Day()+7, 0, 0, 0, 0, loc)
+	return start.UTC(), end.UTC()
+}
+
+// dateLayouts are date formats per language or locale. Month names are only
+// used for English; other locales use their numeric convention.
+var dateLayouts = map[string]string{
+	"en":    "January 2, 2006",
+	"en-US": "January 2, 2006",
+	"en-GB": "2 January 2006",
+	"en-AU": "2 January 2006",
+	"en-IN": "2 January 2006",
+	"de":    "02.01.2006",
+	"fr":    "02/01/2006",
+	"es":    "02/01/2006",
+	"it":    "02/01/2006",
+	"pt":    "02/01/2006",
+	"nl":    "02-01-2006",
+	"ja":    "2006/01/02",
+	"zh":    "2006-01-02",
+	"ko":    "2006. 01. 02.",
+}
+
+// FormatDate formats t as a date in the user's locale and time zone
+func FormatDate(t time.Time, localeTag string, loc *time.Location) string {
+	return t.In(loc).Format(dateLayout(localeTag))
+}
+
+// FormatDateTime formats t as a date and time in the user's locale and time zone
+func FormatDateTime(t time.Time, localeTag string, loc *time.Location) string {
+	timeLayout := "15:04 MST"
+	if lang := language(localeTag); lang == "en" && localeTag != "en-GB" {
+		timeLayout = "3:04 PM MST"
+	}
+	return t.In(loc).Format(dateLayout(localeTag) + " " + timeLayout)
+}
+
+func dateLayout(localeTag string) string {
+	if layout, ok := dateLayouts[localeTag]; ok {
+		return layout
+	}
+	if layout, ok := dateLayouts[language(localeTag)]; ok {
+		return layout
+	}
+	return dateLayouts[DefaultLocale]
+}
+
+func language(localeTag string) string {
+	if i := strings.IndexAny(localeTag, "-_"); i >= 0 {
+		return strings.ToLower(localeTag[:i])
+	}
+	return strings.ToLower(localeTag)
+}
@@ -0,0 +1,156 @@
+package locale
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    string
+		wantErr error
+	}{
+		{name: "empty uses default", tag: "", want: DefaultLocale},
+		{name: "language only", tag: "FR", want: "fr"},
+		{name: "language and region", tag: "en-gb", want: "en-GB"},
+		{name: "underscore separator", tag: "pt_BR", want: "pt-BR"},
+		{name: "invalid tag", tag: "english", wantErr: ErrInvalidLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeLocale(tt.tag)
+			if err != tt.wantErr {
+				t.Fatalf("NormalizeLocale() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeLocale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTimeZone(t *testing.T) {
+	tests := []struct {
+		name    string
+		tz      string
+		want    string
+		wantErr error
+	}{
+		{name: "empty uses default", tz: "", want: DefaultTimeZone},
+		{name: "valid zone", tz: "America/New_York", want: "America/New_York"},
+		{name: "invalid zone", tz: "Mars/Olympus", wantErr: ErrInvalidTimeZone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeTimeZone(tt.tz)
+			if err != tt.wantErr {
+				t.Fatalf("NormalizeTimeZone() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeTimeZone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeUnitSystem(t *testing.T) {
+	tests := []struct {
+		system  string
+		want    string
+		wantErr error
+	}{
+		{system: "", want: UnitSystemUS},
+		{system: "Metric", want: UnitSystemMetric},
+		{system: "imperial", want: UnitSystemUS},
+		{system: "cubits", wantErr: ErrInvalidUnitSystem},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.system, func(t *testing.T) {
+			got, err := NormalizeUnitSystem(tt.system)
+			if err != tt.wantErr {
+				t.Fatalf("NormalizeUnitSystem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeUnitSystem() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeekBounds(t *testing.T) {
+	tokyo := LoadLocation("Asia/Tokyo")
+	newYork := LoadLocation("America/New_York")
+
+	// Sunday 2025-10-12 20:00 UTC is already Monday morning in Tokyo
+	instant := time.Date(2025, 10, 12, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		loc       *time.Location
+		weekStart string
+		wantStart time.Time
+	}{
+		{
+			name:      "sunday start in UTC",
+			loc:       time.UTC,
+			weekStart: "sunday",
+			wantStart: time.Date(2025, 10, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "monday start in Tokyo",
+			loc:       tokyo,
+			weekStart: "monday",
+			wantStart: time.Date(2025, 10, 13, 0, 0, 0, 0, tokyo),
+		},
+		{
+			name:      "monday start in New York",
+			loc:       newYork,
+			weekStart: "monday",
+			wantStart: time.Date(2025, 10, 6, 0, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := WeekBounds(instant, tt.loc, tt.weekStart)
+			if !start.Equal(tt.wantStart) {
+				t.Errorf("WeekBounds() start = %v, want %v", start, tt.wantStart)
+			}
+			if start.Location() != time.UTC {
+				t.Errorf("WeekBounds() start location = %v, want UTC", start.Location())
+			}
+			if got := end.Sub(start); got < 167*time.Hour || got > 169*time.Hour {
+				t.Errorf("WeekBounds() week length = %v, want about 168h", got)
+			}
+		})
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	instant := time.Date(2025, 3, 9, 23, 30, 0, 0, time.UTC)
+	berlin := LoadLocation("Europe/Berlin")
+
+	tests := []struct {
+		locale string
+		loc    *time.Location
+		want   string
+	}{
+		{locale: "en-US", loc: time.UTC, want: "March 9, 2025"},
+		{locale: "en-GB", loc: time.UTC, want: "9 March 2025"},
+		{locale: "de-DE", loc: berlin, want: "10.03.2025"},
+		{locale: "xx", loc: time.UTC, want: "March 9, 2025"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			if got := FormatDate(instant, tt.locale, tt.loc); got != tt.want {
+				t.Errorf("FormatDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/config"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// ErrHeaderInjection reports a recipient or subject containing a line
+// break, which would add headers to the message
+var ErrHeaderInjection = errors.New("email recipient and subject must not contain line breaks")
+
+// Mailer sends emails
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New returns an SMTP mailer when SMTP is configured, otherwise a mailer
+// that only logs messages
+func New(cfg *config.Config) Mailer {
+	if cfg.SMTPHost == "" {
+		return &logMailer{}
+	}
+	return &smtpMailer{config: cfg}
+}
+
+type logMailer struct{}
+
+func (m *logMailer) Send(msg Message) error {
+	log.Printf("Email to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+type smtpMailer struct {
+	config *config.Config
+}
+
+func (m *smtpMailer) Send(msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return ErrHeaderInjection
+	}
+	from, err := mail.ParseAddress(m.config.MailFrom)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	var auth smtp.Auth
+	if m.config.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", m.config.SMTPUsername, m.config.SMTPPassword, m.config.SMTPHost)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", from.String())
+	fmt.Fprintf(&body, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&body, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	body.WriteString(msg.Body)
+
+	addr := m.config.SMTPHost + ":" + m.config.SMTPPort
+	return smtp.SendMail(addr, auth, from.Address, []string{msg.To}, []byte(body.String()))
+}
@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/meal-planner/backend/internal/config"
+)
+
+func TestSendRejectsHeaderInjection(t *testing.T) {
+	// The host is never dialed: messages are checked before sending
+	m := New(&config.Config{SMTPHost: "smtp.invalid", SMTPPort: "25", MailFrom: "no-reply@example.com"})
+
+	tests := []struct {
+		name string
+		msg  Message
+	}{
+		{
+			name: "line feed in recipient",
+			msg:  Message{To: "user@example.com\nBcc: victim@example.com", Subject: "Hello"},
+		},
+		{
+			name: "carriage return in recipient",
+			msg:  Message{To: "user@example.com\rBcc: victim@example.com", Subject: "Hello"},
+		},
+		{
+			name: "line break in subject",
+			msg:  Message{To: "user@example.com", Subject: "Join Smith family\r\nBcc: victim@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := m.Send(tt.msg); !errors.Is(err, ErrHeaderInjection) {
+				t.Errorf("Send() error = %v, want %v", err, ErrHeaderInjection)
+			}
+		})
+	}
+}
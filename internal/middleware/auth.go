@@ -1,14 +1,20 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/meal-planner/backend/internal/config"
+	"github.com/meal-planner/backend/internal/models"
 	"github.com/meal-planner/backend/internal/utils"
 )
 
+// HouseholdHeader selects the active household for a single request,
+// overriding the household carried in the token
+const HouseholdHeader = "X-Household-ID"
+
 // AuthMiddleware validates JWT tokens
 func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -48,6 +54,16 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		c.Set("userID", claims.UserID)
 		c.Set("email", claims.Email)
 
+		// Requested household: request header first, then token claim.
+		// GetHouseholdID checks membership before returning it.
+		householdID := strings.TrimSpace(c.GetHeader(HouseholdHeader))
+		if householdID == "" {
+			householdID = claims.HouseholdID
+		}
+		if householdID != "" {
+			c.Set("requestedHouseholdID", householdID)
+		}
+
 		c.Next()
 	}
 }
@@ -60,3 +76,57 @@ func GetUserID(c *gin.Context) (string, bool) {
 	}
 	return userID.(string), true
 }
+
+// HouseholdLister lists the households a user belongs to
+type HouseholdLister interface {
+	ListForUser(userID string) ([]models.Household, error)
+}
+
+// HouseholdMembershipMiddleware provides the household lister GetHouseholdID
+// checks membership with
+func HouseholdMembershipMiddleware(households HouseholdLister) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("householdLister", households)
+		c.Next()
+	}
+}
+
+// GetHouseholdID retrieves the active household ID: the household requested
+// by header or token, if the user belongs to it. Membership is looked up on
+// the first call of a request.
+func GetHouseholdID(c *gin.Context) (string, bool) {
+	if householdID, checked := c.Get("householdID"); checked {
+		return householdID.(string), householdID != ""
+	}
+
+	householdID := activeHouseholdID(c)
+	c.Set("householdID", householdID)
+	return householdID, householdID != ""
+}
+
+func activeHouseholdID(c *gin.Context) string {
+	requested, exists := c.Get("requestedHouseholdID")
+	if !exists {
+		return ""
+	}
+	userID, exists := GetUserID(c)
+	if !exists {
+		return ""
+	}
+	households, exists := c.Get("householdLister")
+	if !exists {
+		return ""
+	}
+
+	memberOf, err := households.(HouseholdLister).ListForUser(userID)
+	if err != nil {
+		log.Printf("Failed to check household membership: %v", err)
+		return ""
+	}
+	for _, household := range memberOf {
+		if household.ID == requested.(string) {
+			return household.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Collection visibilities
+const (
+	CollectionPrivate   = "private"
+	CollectionHousehold = "household"
+	CollectionPublic    = "public"
+)
+
+// CollectionVisibilities lists the accepted collection visibilities
+var CollectionVisibilities = []string{CollectionPrivate, CollectionHousehold, CollectionPublic}
+
+// Collection is a user's named, ordered list of recipes, such as
+// "Weeknight" or "Holiday". Household collections are visible to the
+// members of HouseholdID. Anyone holding the share link of a collection can
+// view it whatever its visibility.
+type Collection struct {
+	ID            string  `gorm:"type:varchar(255);primaryKey" json:"id"`
+	OwnerID       string  `gorm:"type:varchar(255);not null;index" json:"ownerId"`
+	HouseholdID   *string `gorm:"type:varchar(255);index" json:"householdId,omitempty"`
+	Name          string  `gorm:"type:varchar(255);not null" json:"name"`
+	Description   string  `gorm:"type:text" json:"description,omitempty"`
+	CoverImageURL string  `gorm:"type:text" json:"coverImageUrl,omitempty"`
+	Visibility    string  `gorm:"type:varchar(20);not null;default:private;index" json:"visibility"`
+
+	// ShareToken is the secret of the collection's share link. It and the
+	// link are only shown to the owner.
+	ShareToken *string `gorm:"type:varchar(64);uniqueIndex" json:"shareToken,omitempty"`
+	ShareURL   string  `gorm:"-" json:"shareUrl,omitempty"`
+
+	// RecipeCount is denormalized from collection_recipes and kept in sync
+	// when recipes are added or removed
+	RecipeCount int `gorm:"not null;default:0" json:"recipeCount"`
+
+	CreatedAt time.Time      `gorm:"index" json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// BeforeCreate hook to generate ID if not set
+func (c *Collection) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = generateID("collection")
+	}
+	return nil
+}
+
+// IsOwnedBy reports whether the user owns the collection
+func (c *Collection) IsOwnedBy(userID string) bool {
+	return c.OwnerID == userID
+}
+
+// CollectionRecipe places a recipe in a collection. Recipes are listed by
+// Position, lowest first.
+type CollectionRecipe struct {
+	CollectionID string    `gorm:"type:varchar(255);primaryKey" json:"-"`
+	RecipeID     string    `gorm:"type:varchar(255);primaryKey;index" json:"recipeId"`
+	Position     int       `gorm:"not null" json:"position"`
+	CreatedAt    time.Time `json:"addedAt"`
+	Recipe       *Recipe   `gorm:"foreignKey:RecipeID" json:"recipe,omitempty"`
+}
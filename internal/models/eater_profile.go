@@ -0,0 +1,136 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Age groups for eater profiles
+const (
+	AgeGroupAdult   = "adult"
+	AgeGroupTeen    = "teen"
+	AgeGroupChild   = "child"
+	AgeGroupToddler = "toddler"
+)
+
+// defaultPortionMultipliers are the portion sizes used when a profile doesn't
+// set its own, relative to one adult serving
+var defaultPortionMultipliers = map[string]float64{
+	AgeGroupAdult:   1.0,
+	AgeGroupTeen:    1.0,
+	AgeGroupChild:   0.75,
+	AgeGroupToddler: 0.5,
+}
+
+// IsValidAgeGroup checks if the age group is known
+func IsValidAgeGroup(ageGroup string) bool {
+	_, ok := defaultPortionMultipliers[ageGroup]
+	return ok
+}
+
+// DefaultPortionMultiplier returns the default portion multiplier for an age group
+func DefaultPortionMultiplier(ageGroup string) float64 {
+	if m, ok := defaultPortionMultipliers[ageGroup]; ok {
+		return m
+	}
+	return 1.0
+}
+
+// EaterProfile describes one person who eats the household's meals, with
+// their own restrictions and portion size
+type EaterProfile struct {
+	ID                string         `gorm:"type:varchar(255);primaryKey" json:"id"`
+	HouseholdID       string         `gorm:"type:varchar(255);not null;index" json:"householdId"`
+	UserID            *string        `gorm:"type:varchar(255);index" json:"userId,omitempty"`
+	Name              string         `gorm:"type:varchar(255);not null" json:"name"`
+	AgeGroup          string         `gorm:"type:varchar(20);not null;default:'adult'" json:"ageGroup"`
+	IsGuest           bool           `gorm:"default:false" json:"isGuest"`
+	Allergies         StringList     `gorm:"type:jsonb" json:"allergies"`
+	Diets             StringList     `gorm:"type:jsonb" json:"diets"`
+	Dislikes          StringList     `gorm:"type:jsonb" json:"dislikes"`
+	PortionMultiplier float64        `gorm:"not null;default:1" json:"portionMultiplier"`
+	CreatedAt         time.Time      `json:"createdAt"`
+	UpdatedAt         time.Time      `json:"updatedAt"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// BeforeCreate hook to generate ID if not set
+func (p *EaterProfile) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = generateID("eater")
+	}
+	return nil
+}
+
+// DinerSummary aggregates the restrictions and portions of the people eating
+// a given meal
+type DinerSummary struct {
+	ProfileIDs    []string   `json:"profileIds"`
+	DinerCount    int        `json:"dinerCount"`
+	PortionFactor float64    `json:"portionFactor"`
+	Allergies     StringList `json:"allergies"`
+	Diets         StringList `json:"diets"`
+	Dislikes      StringList `json:"dislikes"`
+}
+
+// SummarizeDiners returns the union of the profiles' restrictions and the
+// total portion factor, e.g. two adults and a toddler give a factor of 2.5
+func SummarizeDiners(profiles []EaterProfile) *DinerSummary {
+	summary := &DinerSummary{
+		ProfileIDs: make([]string, 0, len(profiles)),
+		DinerCount: len(profiles),
+	}
+
+	allergies := newCaseInsensitiveSet()
+	diets := newCaseInsensitiveSet()
+	dislikes := newCaseInsensitiveSet()
+
+	for _, profile := range profiles {
+		summary.ProfileIDs = append(summary.ProfileIDs, profile.ID)
+		summary.PortionFactor += profile.PortionMultiplier
+		allergies.addAll(profile.Allergies)
+		diets.addAll(profile.Diets)
+		dislikes.addAll(profile.Dislikes)
+	}
+
+	summary.Allergies = allergies.list()
+	summary.Diets = diets.list()
+	summary.Dislikes = dislikes.list()
+	return summary
+}
+
+// caseInsensitiveSet collects strings, keeping the first spelling seen
+type caseInsensitiveSet struct {
+	values map[string]string
+}
+
+func newCaseInsensitiveSet() *caseInsensitiveSet {
+	return &caseInsensitiveSet{values: make(map[string]string)}
+}
+
+func (s *caseInsensitiveSet) addAll(values []string) {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		key := strings.ToLower(v)
+		if _, ok := s.values[key]; !ok {
+			s.values[key] = v
+		}
+	}
+}
+
+func (s *caseInsensitiveSet) list() StringList {
+	list := make(StringList, 0, len(s.values))
+	for _, v := range s.values {
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return strings.ToLower(list[i]) < strings.ToLower(list[j])
+	})
+	return list
+}
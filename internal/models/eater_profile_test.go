@@ -0,0 +1,64 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarizeDiners(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []EaterProfile
+		want     *DinerSummary
+	}{
+		{
+			name:     "no profiles",
+			profiles: nil,
+			want: &DinerSummary{
+				ProfileIDs: []string{},
+				Allergies:  StringList{},
+				Diets:      StringList{},
+				Dislikes:   StringList{},
+			},
+		},
+		{
+			name: "two adults and a toddler",
+			profiles: []EaterProfile{
+				{ID: "a", PortionMultiplier: 1},
+				{ID: "b", PortionMultiplier: 1},
+				{ID: "c", PortionMultiplier: 0.5},
+			},
+			want: &DinerSummary{
+				ProfileIDs:    []string{"a", "b", "c"},
+				DinerCount:    3,
+				PortionFactor: 2.5,
+				Allergies:     StringList{},
+				Diets:         StringList{},
+				Dislikes:      StringList{},
+			},
+		},
+		{
+			name: "restrictions merged ignoring case and blanks",
+			profiles: []EaterProfile{
+				{ID: "a", PortionMultiplier: 1, Allergies: StringList{"Peanuts", " "}, Diets: StringList{"Vegetarian"}, Dislikes: StringList{"olives"}},
+				{ID: "b", PortionMultiplier: 0.75, Allergies: StringList{"peanuts", "Sesame"}, Dislikes: StringList{" Cilantro ", "Olives"}},
+			},
+			want: &DinerSummary{
+				ProfileIDs:    []string{"a", "b"},
+				DinerCount:    2,
+				PortionFactor: 1.75,
+				Allergies:     StringList{"Peanuts", "Sesame"},
+				Diets:         StringList{"Vegetarian"},
+				Dislikes:      StringList{"Cilantro", "olives"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SummarizeDiners(tt.profiles); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SummarizeDiners() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
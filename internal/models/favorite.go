@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Favorite marks a recipe as a favorite of a user
+type Favorite struct {
+	UserID    string    `gorm:"type:varchar(255);primaryKey" json:"-"`
+	RecipeID  string    `gorm:"type:varchar(255);primaryKey;index" json:"recipeId"`
+	CreatedAt time.Time `gorm:"index" json:"favoritedAt"`
+	Recipe    *Recipe   `gorm:"foreignKey:RecipeID" json:"recipe,omitempty"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Food is an entry in the food composition database, imported from USDA
+// FoodData Central. Nutrients are per 100 g.
+type Food struct {
+	ID        string          `gorm:"type:varchar(255);primaryKey" json:"id"`
+	FDCID     *int            `gorm:"column:fdc_id;uniqueIndex" json:"fdcId,omitempty"`
+	Name      string          `gorm:"type:varchar(512);not null;index" json:"name"`
+	DataType  string          `gorm:"type:varchar(50);index" json:"dataType,omitempty"`
+	Category  string          `gorm:"type:varchar(255)" json:"category,omitempty"`
+	Nutrients RecipeNutrition `gorm:"type:jsonb;not null" json:"nutrients"`
+	Portions  FoodPortions    `gorm:"type:jsonb;not null" json:"portions"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// Food data types, from FoodData Central
+const (
+	FoodDataFoundation = "foundation"
+	FoodDataSRLegacy   = "sr_legacy"
+	FoodDataSurvey     = "survey"
+	FoodDataBranded    = "branded"
+)
+
+// BeforeCreate hook to generate ID if not set
+func (f *Food) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == "" {
+		f.ID = generateID("food")
+	}
+	if f.Portions == nil {
+		f.Portions = FoodPortions{}
+	}
+	return nil
+}
+
+// FoodPortion is the gram weight of a household measure of a food, such as
+// 1 cup chopped or 1 large. Unit is a canonical unit from the ingredients
+// package ("cup", "clove") or a size word ("large").
+type FoodPortion struct {
+	Amount      float64 `json:"amount"`
+	Unit        string  `json:"unit,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Grams       float64 `json:"grams"`
+}
+
+// FoodPortions stores a food's portions in a JSONB column
+type FoodPortions []FoodPortion
+
+// Value implements driver.Valuer
+func (p FoodPortions) Value() (driver.Value, error) {
+	if p == nil {
+		return "[]", nil
+	}
+	return jsonValue(p)
+}
+
+// Scan implements sql.Scanner
+func (p *FoodPortions) Scan(value interface{}) error {
+	return jsonScan(value, p)
+}
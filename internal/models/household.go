@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HouseholdRole is a member's role within a household
+type HouseholdRole string
+
+const (
+	HouseholdRoleOwner  HouseholdRole = "owner"
+	HouseholdRoleMember HouseholdRole = "member"
+	HouseholdRoleViewer HouseholdRole = "viewer"
+)
+
+// householdRoleRank orders roles from least to most privileged
+var householdRoleRank = map[HouseholdRole]int{
+	HouseholdRoleViewer: 1,
+	HouseholdRoleMember: 2,
+	HouseholdRoleOwner:  3,
+}
+
+// IsValid reports whether the role is a known household role
+func (r HouseholdRole) IsValid() bool {
+	_, ok := householdRoleRank[r]
+	return ok
+}
+
+// AtLeast reports whether the role grants at least the privileges of min
+func (r HouseholdRole) AtLeast(min HouseholdRole) bool {
+	return householdRoleRank[r] >= householdRoleRank[min]
+}
+
+// Household is a group of users who plan meals together
+type Household struct {
+	ID        string            `gorm:"type:varchar(255);primaryKey" json:"id"`
+	Name      string            `gorm:"type:varchar(255);not null" json:"name"`
+	OwnerID   string            `gorm:"type:varchar(255);not null;index" json:"ownerId"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt    `gorm:"index" json:"-"`
+	Members   []HouseholdMember `gorm:"foreignKey:HouseholdID" json:"members,omitempty"`
+}
+
+// BeforeCreate hook to generate ID if not set
+func (h *Household) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == "" {
+		h.ID = generateID("household")
+	}
+	return nil
+}
+
+// HouseholdMember links a user to a household with a role
+type HouseholdMember struct {
+	HouseholdID string        `gorm:"type:varchar(255);primaryKey" json:"householdId"`
+	UserID      string        `gorm:"type:varchar(255);primaryKey;index" json:"userId"`
+	Role        HouseholdRole `gorm:"type:varchar(20);not null" json:"role"`
+	JoinedAt    time.Time     `json:"joinedAt"`
+	User        *User         `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// BeforeCreate hook to set the join time if not set
+func (m *HouseholdMember) BeforeCreate(tx *gorm.DB) error {
+	if m.JoinedAt.IsZero() {
+		m.JoinedAt = time.Now().UTC()
+	}
+	return nil
+}
+
+// InvitationStatus is the state of a household invitation
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+	InvitationRevoked  InvitationStatus = "revoked"
+)
+
+// HouseholdInvitation is an email invitation to join a household
+type HouseholdInvitation struct {
+	ID          string           `gorm:"type:varchar(255);primaryKey" json:"id"`
+	HouseholdID string           `gorm:"type:varchar(255);not null;index" json:"householdId"`
+	Email       string           `gorm:"type:varchar(255);not null;index" json:"email"`
+	Role        HouseholdRole    `gorm:"type:varchar(20);not null" json:"role"`
+	InvitedByID string           `gorm:"type:varchar(255);not null" json:"invitedById"`
+	Status      InvitationStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	ExpiresAt   time.Time        `json:"expiresAt"`
+	RespondedAt *time.Time       `json:"respondedAt,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	Household   *Household       `gorm:"foreignKey:HouseholdID" json:"household,omitempty"`
+}
+
+// BeforeCreate hook to generate ID if not set
+func (i *HouseholdInvitation) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = generateID("invite")
+	}
+	return nil
+}
+
+// IsExpired checks if the invitation can no longer be accepted
+func (i *HouseholdInvitation) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
@@ -0,0 +1,19 @@
+package models
+
+// MealSlot is one meal of a meal plan: the recipe to cook and, when it
+// differs from the recipe's own yield, how many servings to cook it for.
+// Slots follow the live recipe unless RevisionID pins one of its revisions.
+// Slots are stored as values inside a plan's meals JSON.
+type MealSlot struct {
+	RecipeID   string  `json:"recipeId"`
+	Servings   *int    `json:"servings,omitempty"`
+	RevisionID *string `json:"revisionId,omitempty"`
+}
+
+// ServingsFor returns the servings to cook recipe for in this slot
+func (s MealSlot) ServingsFor(recipe *Recipe) int {
+	if s.Servings != nil {
+		return *s.Servings
+	}
+	return recipe.Servings
+}
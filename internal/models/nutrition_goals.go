@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// Units a user can express macro targets in
+const (
+	MacroUnitGrams   = "grams"
+	MacroUnitPercent = "percent"
+)
+
+// NutritionGoals stores a user's daily nutrition targets. Macros are kept in
+// both grams and percent of calories; MacroUnit records which one the user
+// entered.
+type NutritionGoals struct {
+	UserID          string  `gorm:"type:varchar(255);primaryKey" json:"-"`
+	Preset          string  `gorm:"type:varchar(50)" json:"preset,omitempty"`
+	MacroUnit       string  `gorm:"type:varchar(20);not null;default:'percent'" json:"macroUnit"`
+	Calories        int     `gorm:"not null" json:"calories"`
+	ProteinGrams    float64 `json:"proteinGrams"`
+	CarbsGrams      float64 `json:"carbsGrams"`
+	FatGrams        float64 `json:"fatGrams"`
+	ProteinPercent  float64 `json:"proteinPercent"`
+	CarbsPercent    float64 `json:"carbsPercent"`
+	FatPercent      float64 `json:"fatPercent"`
+	FiberGrams      float64 `json:"fiberGrams"`
+	SodiumLimitMg   float64 `json:"sodiumLimitMg"`
+	SugarLimitGrams float64 `json:"sugarLimitGrams"`
+
+	// Calculator inputs, kept so targets can be re-estimated later
+	Age           *int     `json:"age,omitempty"`
+	Sex           *string  `gorm:"type:varchar(10)" json:"sex,omitempty"`
+	HeightCm      *float64 `json:"heightCm,omitempty"`
+	WeightKg      *float64 `json:"weightKg,omitempty"`
+	ActivityLevel *string  `gorm:"type:varchar(20)" json:"activityLevel,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+)
+
+// OnboardingStepStatus is the state of a single onboarding step for a user
+type OnboardingStepStatus string
+
+const (
+	OnboardingStepPending   OnboardingStepStatus = "pending"
+	OnboardingStepCompleted OnboardingStepStatus = "completed"
+	OnboardingStepSkipped   OnboardingStepStatus = "skipped"
+)
+
+// OnboardingProgress records a user's answer to one onboarding step
+type OnboardingProgress struct {
+	UserID    string               `gorm:"type:varchar(255);primaryKey" json:"-"`
+	StepKey   string               `gorm:"type:varchar(100);primaryKey" json:"step"`
+	Status    OnboardingStepStatus `gorm:"type:varchar(20);not null" json:"status"`
+	Data      JSONMap              `gorm:"type:jsonb" json:"data,omitempty"`
+	CreatedAt time.Time            `json:"createdAt"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+}
+
+// TableName overrides the default table name
+func (OnboardingProgress) TableName() string {
+	return "onboarding_progress"
+}
+
+// IsResolved reports whether the step has been either completed or skipped
+func (p *OnboardingProgress) IsResolved() bool {
+	return p.Status == OnboardingStepCompleted || p.Status == OnboardingStepSkipped
+}
+
+// OnboardingField describes one input the client should render for a step
+type OnboardingField struct {
+	Name        string   `json:"name"`
+	Label       string   `json:"label"`
+	Type        string   `json:"type"` // "text", "number", "select", "multiselect"
+	Required    bool     `json:"required"`
+	Options     []string `json:"options,omitempty"`
+	AllowCustom bool     `json:"allowCustom,omitempty"`
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	// Integer limits a number field to whole numbers
+	Integer bool `json:"integer,omitempty"`
+}
+
+// OnboardingStep describes a step of the onboarding flow as served to clients
+type OnboardingStep struct {
+	Key         string            `json:"key"`
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	Skippable   bool              `json:"skippable"`
+	Fields      []OnboardingField `json:"fields"`
+}
+
+// OnboardingStepState combines a step definition with the user's progress on it
+type OnboardingStepState struct {
+	OnboardingStep
+	Status      OnboardingStepStatus `json:"status"`
+	Data        JSONMap              `json:"data,omitempty"`
+	CompletedAt *time.Time           `json:"completedAt,omitempty"`
+}
+
+// OnboardingState represents a user's overall onboarding progress
+type OnboardingState struct {
+	Steps                  []OnboardingStepState `json:"steps"`
+	CurrentStep            string                `json:"currentStep,omitempty"`
+	ResolvedSteps          int                   `json:"resolvedSteps"`
+	TotalSteps             int                   `json:"totalSteps"`
+	HasCompletedOnboarding bool                  `json:"hasCompletedOnboarding"`
+}
@@ -0,0 +1,352 @@
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/meal-planner/backend/internal/allergens"
+	"github.com/meal-planner/backend/internal/ingredients"
+	"gorm.io/gorm"
+)
+
+// Recipe categories
+var RecipeCategories = []string{"Breakfast", "Lunch", "Dinner", "Snacks", "Desserts"}
+
+// Recipe difficulty levels
+var RecipeDifficulties = []string{"Easy", "Medium", "Hard"}
+
+// Recipe represents a recipe, following the recipes table in DATABASE_DESIGN.md
+type Recipe struct {
+	ID          string `gorm:"type:varchar(255);primaryKey" json:"id"`
+	Name        string `gorm:"type:varchar(255);not null" json:"name"`
+	Description string `gorm:"type:text" json:"description,omitempty"`
+	Category    string `gorm:"type:varchar(50);not null;index" json:"category"`
+	Cuisine     string `gorm:"type:varchar(100);index" json:"cuisine,omitempty"`
+
+	// Timing, in minutes
+	PrepTime  int `gorm:"not null;default:0" json:"prepTime"`
+	CookTime  int `gorm:"not null;default:0" json:"cookTime"`
+	TotalTime int `gorm:"not null;default:0;index" json:"totalTime"`
+
+	Servings   int    `gorm:"not null;default:4" json:"servings"`
+	Difficulty string `gorm:"type:varchar(20);not null;default:'Medium'" json:"difficulty"`
+
+	Ingredients  RecipeIngredients `gorm:"type:jsonb;not null" json:"ingredients"`
+	Instructions StringList        `gorm:"type:jsonb;not null" json:"instructions"`
+	Nutrition    RecipeNutrition   `gorm:"type:jsonb;not null" json:"nutrition"`
+	Tags         StringList        `gorm:"type:jsonb;not null" json:"tags"`
+
+	// NutritionComputed is set when Nutrition was computed from the
+	// ingredients' matched foods rather than entered by hand. Coverage is the
+	// share of measured ingredients that were matched and weighed, and
+	// Confidence summarizes it as high, medium or low.
+	NutritionComputed   bool    `gorm:"not null;default:false" json:"nutritionComputed"`
+	NutritionCoverage   float64 `gorm:"type:decimal(4,3);not null;default:0" json:"nutritionCoverage"`
+	NutritionConfidence string  `gorm:"type:varchar(10)" json:"nutritionConfidence,omitempty"`
+
+	// Allergens are detected from the ingredient names when the recipe is
+	// saved, then adjusted by the author's overrides. AllergenWarnings lists
+	// the ones the caller is allergic to; it is set per caller and not stored.
+	Allergens         StringList        `gorm:"type:jsonb" json:"allergens"`
+	AllergenOverrides AllergenOverrides `gorm:"type:jsonb" json:"allergenOverrides"`
+	AllergenWarnings  []string          `gorm:"-" json:"allergenWarnings,omitempty"`
+
+	// Images
+	ImageURL  string    `gorm:"type:text" json:"imageUrl,omitempty"`
+	ImageURLs StringMap `gorm:"type:jsonb" json:"imageUrls,omitempty"`
+	// ImageBlurHash is the placeholder of the primary uploaded image
+	ImageBlurHash string `gorm:"type:varchar(64)" json:"imageBlurHash,omitempty"`
+
+	// Ratings & reviews, maintained from the reviews table. BayesianRating is
+	// used for sorting; its default is BayesianPriorMean.
+	Rating         float64 `gorm:"type:decimal(3,2);default:0" json:"rating"`
+	ReviewCount    int     `gorm:"default:0" json:"reviewCount"`
+	BayesianRating float64 `gorm:"type:decimal(4,3);not null;default:3.5;index" json:"bayesianRating"`
+
+	// FavoriteCount is denormalized from favorites and kept in sync when
+	// favorites change
+	FavoriteCount int `gorm:"not null;default:0" json:"favoriteCount"`
+	// IsFavorite is set per caller and not stored
+	IsFavorite bool `gorm:"-" json:"isFavorite"`
+
+	// Authorship
+	CreatedByID *string `gorm:"type:varchar(255);index" json:"createdById,omitempty"`
+	IsPublic    bool    `gorm:"not null" json:"isPublic"`
+	IsFeatured  bool    `gorm:"not null;default:false" json:"isFeatured"`
+
+	// Revision is the number of the latest RecipeRevision
+	Revision int `gorm:"not null;default:0" json:"revision"`
+
+	// A fork is a user's copy of another recipe. ForkedFromRevision is the
+	// original's revision when it was copied, so later upstream changes
+	// can be shown.
+	ForkedFromID       *string `gorm:"type:varchar(255);index" json:"forkedFromId,omitempty"`
+	ForkedFromRevision *int    `json:"forkedFromRevision,omitempty"`
+	// ForkCount is denormalized from the recipe's live forks and kept in
+	// sync when forks are created or deleted
+	ForkCount int `gorm:"not null;default:0" json:"forkCount"`
+
+	CreatedAt time.Time      `gorm:"index" json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// BeforeCreate hook to generate ID if not set
+func (r *Recipe) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = generateID("recipe")
+	}
+	if r.ReviewCount == 0 {
+		r.BayesianRating = BayesianRating(0, 0)
+	}
+	return nil
+}
+
+// BeforeSave hook to keep derived columns in sync
+func (r *Recipe) BeforeSave(tx *gorm.DB) error {
+	r.TotalTime = r.PrepTime + r.CookTime
+	if r.Ingredients == nil {
+		r.Ingredients = RecipeIngredients{}
+	}
+	if r.Instructions == nil {
+		r.Instructions = StringList{}
+	}
+	if r.Tags == nil {
+		r.Tags = StringList{}
+	}
+	r.DetectAllergens()
+	return nil
+}
+
+// DetectAllergens sets the allergens of each ingredient from its name, and
+// the recipe's from those and the author's overrides
+func (r *Recipe) DetectAllergens() {
+	lists := [][]string{r.AllergenOverrides.Contains}
+	for i := range r.Ingredients {
+		r.Ingredients[i].Allergens = allergens.Detect(r.Ingredients[i].Name)
+		lists = append(lists, r.Ingredients[i].Allergens)
+	}
+	r.Allergens = allergens.Without(allergens.Merge(lists...), r.AllergenOverrides.FreeOf)
+	if r.Allergens == nil {
+		r.Allergens = StringList{}
+	}
+}
+
+// AllergenOverrides are the author's corrections to detected allergens:
+// Contains adds allergens the ingredient names do not reveal, and FreeOf
+// removes ones that were detected wrongly
+type AllergenOverrides struct {
+	Contains []string `json:"contains,omitempty"`
+	FreeOf   []string `json:"freeOf,omitempty"`
+}
+
+// Value implements driver.Valuer
+func (o AllergenOverrides) Value() (driver.Value, error) {
+	return jsonValue(o)
+}
+
+// Scan implements sql.Scanner
+func (o *AllergenOverrides) Scan(value interface{}) error {
+	return jsonScan(value, o)
+}
+
+// IsOwnedBy checks if the recipe was created by the given user
+func (r *Recipe) IsOwnedBy(userID string) bool {
+	return r.CreatedByID != nil && *r.CreatedByID == userID
+}
+
+// RecipeIngredient is one ingredient line of a recipe. Quantity is zero for
+// lines like "salt to taste"; QuantityMax is set for ranges like "3-4".
+type RecipeIngredient struct {
+	Name        string              `json:"name"`
+	Quantity    float64             `json:"quantity,omitempty"`
+	QuantityMax float64             `json:"quantityMax,omitempty"`
+	Unit        string              `json:"unit,omitempty"`
+	UnknownUnit bool                `json:"unknownUnit,omitempty"`
+	Size        *ingredients.Amount `json:"size,omitempty"`
+	Category    string              `json:"category,omitempty"`
+	Note        string              `json:"note,omitempty"`
+
+	// Allergens are detected from the name when the recipe is saved
+	Allergens []string `json:"allergens,omitempty"`
+
+	// FoodID links the ingredient to the foods table for nutrition. FoodMatch
+	// says how: FoodMatchManual when chosen by the user, FoodMatchAuto when
+	// matched by name, empty when unmatched. Grams is the computed weight.
+	FoodID    string  `json:"foodId,omitempty"`
+	FoodMatch string  `json:"foodMatch,omitempty"`
+	Grams     float64 `json:"grams,omitempty"`
+}
+
+// How a recipe ingredient was linked to a food
+const (
+	FoodMatchManual = "manual"
+	FoodMatchAuto   = "auto"
+)
+
+// ParseRecipeIngredient parses a free-text line like "1 (14 oz) can diced
+// tomatoes" into a structured ingredient
+func ParseRecipeIngredient(line string) RecipeIngredient {
+	parsed := ingredients.Parse(line)
+	return RecipeIngredient{
+		Name:        parsed.Name,
+		Quantity:    parsed.Quantity,
+		QuantityMax: parsed.QuantityMax,
+		Unit:        parsed.Unit,
+		UnknownUnit: parsed.UnknownUnit,
+		Size:        parsed.Size,
+		Note:        parsed.Note,
+	}
+}
+
+// Parsed returns the ingredient in the form used by the ingredients package,
+// e.g. for ingredients.Combine
+func (i RecipeIngredient) Parsed() ingredients.Ingredient {
+	return ingredients.Ingredient{
+		Quantity:    i.Quantity,
+		QuantityMax: i.QuantityMax,
+		Unit:        i.Unit,
+		UnknownUnit: i.UnknownUnit,
+		Size:        i.Size,
+		Name:        i.Name,
+		Note:        i.Note,
+	}
+}
+
+// RecipeIngredients stores a recipe's ingredients in a JSONB column
+type RecipeIngredients []RecipeIngredient
+
+// Value implements driver.Valuer
+func (i RecipeIngredients) Value() (driver.Value, error) {
+	if i == nil {
+		return "[]", nil
+	}
+	return jsonValue(i)
+}
+
+// Scan implements sql.Scanner
+func (i *RecipeIngredients) Scan(value interface{}) error {
+	return jsonScan(value, i)
+}
+
+// RecipeNutrition is nutrition information: per serving for recipes, per
+// 100 g for foods. Calories are kcal; macronutrients, sugar and saturated
+// fat are grams; sodium, cholesterol and minerals are milligrams, as is
+// vitamin C; vitamin D is micrograms.
+type RecipeNutrition struct {
+	Calories      float64 `json:"calories"`
+	Protein       float64 `json:"protein"`
+	Carbohydrates float64 `json:"carbohydrates"`
+	Fat           float64 `json:"fat"`
+	Fiber         float64 `json:"fiber"`
+	Sugar         float64 `json:"sugar,omitempty"`
+	Sodium        float64 `json:"sodium,omitempty"`
+
+	SaturatedFat float64 `json:"saturatedFat,omitempty"`
+	Cholesterol  float64 `json:"cholesterol,omitempty"`
+	Calcium      float64 `json:"calcium,omitempty"`
+	Iron         float64 `json:"iron,omitempty"`
+	Potassium    float64 `json:"potassium,omitempty"`
+	VitaminC     float64 `json:"vitaminC,omitempty"`
+	VitaminD     float64 `json:"vitaminD,omitempty"`
+}
+
+// Value implements driver.Valuer
+func (n RecipeNutrition) Value() (driver.Value, error) {
+	return jsonValue(n)
+}
+
+// Scan implements sql.Scanner
+func (n *RecipeNutrition) Scan(value interface{}) error {
+	return jsonScan(value, n)
+}
+
+// Times returns the nutrition multiplied by f, e.g. by the number of servings
+// to get totals
+func (n RecipeNutrition) Times(f float64) RecipeNutrition {
+	return RecipeNutrition{
+		Calories:      n.Calories * f,
+		Protein:       n.Protein * f,
+		Carbohydrates: n.Carbohydrates * f,
+		Fat:           n.Fat * f,
+		Fiber:         n.Fiber * f,
+		Sugar:         n.Sugar * f,
+		Sodium:        n.Sodium * f,
+		SaturatedFat:  n.SaturatedFat * f,
+		Cholesterol:   n.Cholesterol * f,
+		Calcium:       n.Calcium * f,
+		Iron:          n.Iron * f,
+		Potassium:     n.Potassium * f,
+		VitaminC:      n.VitaminC * f,
+		VitaminD:      n.VitaminD * f,
+	}
+}
+
+// Add returns the sum of n and o
+func (n RecipeNutrition) Add(o RecipeNutrition) RecipeNutrition {
+	return RecipeNutrition{
+		Calories:      n.Calories + o.Calories,
+		Protein:       n.Protein + o.Protein,
+		Carbohydrates: n.Carbohydrates + o.Carbohydrates,
+		Fat:           n.Fat + o.Fat,
+		Fiber:         n.Fiber + o.Fiber,
+		Sugar:         n.Sugar + o.Sugar,
+		Sodium:        n.Sodium + o.Sodium,
+		SaturatedFat:  n.SaturatedFat + o.SaturatedFat,
+		Cholesterol:   n.Cholesterol + o.Cholesterol,
+		Calcium:       n.Calcium + o.Calcium,
+		Iron:          n.Iron + o.Iron,
+		Potassium:     n.Potassium + o.Potassium,
+		VitaminC:      n.VitaminC + o.VitaminC,
+		VitaminD:      n.VitaminD + o.VitaminD,
+	}
+}
+
+// ScaledRecipe is a recipe with its ingredients scaled to a different number
+// of servings. Nutrition stays per serving; NutritionTotal covers all of them.
+type ScaledRecipe struct {
+	Recipe
+	OriginalServings int             `json:"originalServings"`
+	ScaleFactor      float64         `json:"scaleFactor"`
+	NutritionTotal   RecipeNutrition `json:"nutritionTotal"`
+}
+
+// RecipeSearchResult is a recipe matched by full-text search
+type RecipeSearchResult struct {
+	Recipe
+	Rank       float64          `json:"rank"`
+	Highlights RecipeHighlights `gorm:"embedded" json:"highlights"`
+}
+
+// CookableRecipe is a recipe matched against the ingredients a user has on
+// hand. Coverage is the share of its required ingredients on hand; staples
+// and optional ingredients are not required.
+type CookableRecipe struct {
+	Recipe
+	Coverage           float64  `json:"coverage"`
+	MatchedCount       int      `json:"matchedCount"`
+	RequiredCount      int      `json:"requiredCount"`
+	MissingIngredients []string `json:"missingIngredients"`
+}
+
+// RecipeHighlights holds search snippets with matches wrapped in <mark> tags
+type RecipeHighlights struct {
+	Name        string `gorm:"column:name_highlight" json:"name"`
+	Description string `gorm:"column:description_highlight" json:"description,omitempty"`
+}
+
+// FacetCount is the number of recipes matching one filter value
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// RecipeFacets holds facet counts for the recipe filters. MaxTotalTime
+// counts recipes ready within 15, 30, 45 and 60 minutes.
+type RecipeFacets struct {
+	Categories   []FacetCount `json:"category"`
+	Cuisines     []FacetCount `json:"cuisine"`
+	Difficulties []FacetCount `json:"difficulty"`
+	Tags         []FacetCount `json:"tags"`
+	MaxTotalTime []FacetCount `json:"maxTotalTime"`
+}
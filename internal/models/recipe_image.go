@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecipeImage is an uploaded photo of a recipe, stored in several sizes.
+// The recipe's primary image also sets its ImageURL, ImageURLs and
+// ImageBlurHash, so listings need no join.
+type RecipeImage struct {
+	ID           string `gorm:"type:varchar(255);primaryKey" json:"id"`
+	RecipeID     string `gorm:"type:varchar(255);not null;index" json:"recipeId"`
+	UploadedByID string `gorm:"type:varchar(255)" json:"uploadedById"`
+	Position     int    `gorm:"not null" json:"position"`
+	IsPrimary    bool   `gorm:"not null;default:false" json:"isPrimary"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	BlurHash     string `gorm:"type:varchar(64)" json:"blurHash"`
+
+	// URLs and Keys map variant names, e.g. "thumbnail", to the image's
+	// address and to its blob store key
+	URLs      StringMap `gorm:"type:jsonb" json:"urls"`
+	Keys      StringMap `gorm:"type:jsonb" json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// DisplayURL returns the URL of the size shown on recipe pages, which
+// becomes the recipe's ImageURL when the image is primary
+func (i *RecipeImage) DisplayURL() string {
+	return i.URLs["large"]
+}
+
+// BeforeCreate hook to generate ID if not set
+func (i *RecipeImage) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = generateID("img")
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// RecipeNeighbor is an entry of a recipe's cached list of similar recipes,
+// kept current by the similarity job. Rank orders the list from 1, most
+// similar first.
+type RecipeNeighbor struct {
+	RecipeID   string    `gorm:"type:varchar(255);primaryKey" json:"recipeId"`
+	NeighborID string    `gorm:"type:varchar(255);primaryKey;index" json:"neighborId"`
+	Rank       int       `gorm:"not null" json:"rank"`
+	Score      float64   `gorm:"not null" json:"score"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// SimilarRecipe is a recipe recommended as similar to another, with a
+// similarity score from 0 to 1
+type SimilarRecipe struct {
+	Recipe
+	Similarity float64 `json:"similarity"`
+}
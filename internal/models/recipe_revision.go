@@ -0,0 +1,109 @@
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecipeRevision is an immutable record of a recipe as saved by one edit.
+// Revisions are numbered from 1 per recipe; the recipe's Revision is the
+// number of its latest one.
+type RecipeRevision struct {
+	ID       string  `gorm:"type:varchar(255);primaryKey" json:"id"`
+	RecipeID string  `gorm:"type:varchar(255);not null;uniqueIndex:idx_recipe_revisions_recipe_number" json:"recipeId"`
+	Number   int     `gorm:"not null;uniqueIndex:idx_recipe_revisions_recipe_number" json:"number"`
+	AuthorID *string `gorm:"type:varchar(255);index" json:"authorId,omitempty"`
+	Summary  string  `gorm:"type:varchar(500)" json:"summary"`
+	// RestoredFrom is the number of the revision this one restored
+	RestoredFrom *int `json:"restoredFrom,omitempty"`
+	// Snapshot is left out of revision listings
+	Snapshot  *RecipeSnapshot `gorm:"type:jsonb;not null" json:"snapshot,omitempty"`
+	CreatedAt time.Time       `gorm:"index" json:"createdAt"`
+
+	// Set per response and not stored
+	AuthorName string `gorm:"-" json:"authorName,omitempty"`
+}
+
+// BeforeCreate hook to generate ID if not set
+func (r *RecipeRevision) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = generateID("rev")
+	}
+	return nil
+}
+
+// RecipeSnapshot holds the editable fields of a recipe at one revision
+type RecipeSnapshot struct {
+	Name              string            `json:"name"`
+	Description       string            `json:"description,omitempty"`
+	Category          string            `json:"category"`
+	Cuisine           string            `json:"cuisine,omitempty"`
+	PrepTime          int               `json:"prepTime"`
+	CookTime          int               `json:"cookTime"`
+	Servings          int               `json:"servings"`
+	Difficulty        string            `json:"difficulty"`
+	Ingredients       RecipeIngredients `json:"ingredients"`
+	Instructions      StringList        `json:"instructions"`
+	Nutrition         RecipeNutrition   `json:"nutrition"`
+	Tags              StringList        `json:"tags"`
+	ImageURL          string            `json:"imageUrl,omitempty"`
+	IsPublic          bool              `json:"isPublic"`
+	IsFeatured        bool              `json:"isFeatured"`
+	AllergenOverrides AllergenOverrides `json:"allergenOverrides"`
+}
+
+// Value implements driver.Valuer
+func (s RecipeSnapshot) Value() (driver.Value, error) {
+	return jsonValue(s)
+}
+
+// Scan implements sql.Scanner
+func (s *RecipeSnapshot) Scan(value interface{}) error {
+	return jsonScan(value, s)
+}
+
+// Snapshot returns the recipe's editable fields
+func (r *Recipe) Snapshot() RecipeSnapshot {
+	return RecipeSnapshot{
+		Name:              r.Name,
+		Description:       r.Description,
+		Category:          r.Category,
+		Cuisine:           r.Cuisine,
+		PrepTime:          r.PrepTime,
+		CookTime:          r.CookTime,
+		Servings:          r.Servings,
+		Difficulty:        r.Difficulty,
+		Ingredients:       append(RecipeIngredients{}, r.Ingredients...),
+		Instructions:      append(StringList{}, r.Instructions...),
+		Nutrition:         r.Nutrition,
+		Tags:              append(StringList{}, r.Tags...),
+		ImageURL:          r.ImageURL,
+		IsPublic:          r.IsPublic,
+		IsFeatured:        r.IsFeatured,
+		AllergenOverrides: r.AllergenOverrides,
+	}
+}
+
+// ApplySnapshot sets the recipe's editable fields from a snapshot. The
+// image is left as it is: it follows the recipe's uploaded images and their
+// primary image, which revisions do not record.
+func (r *Recipe) ApplySnapshot(s RecipeSnapshot) {
+	r.Name = s.Name
+	r.Description = s.Description
+	r.Category = s.Category
+	r.Cuisine = s.Cuisine
+	r.PrepTime = s.PrepTime
+	r.CookTime = s.CookTime
+	r.TotalTime = s.PrepTime + s.CookTime
+	r.Servings = s.Servings
+	r.Difficulty = s.Difficulty
+	r.Ingredients = append(RecipeIngredients{}, s.Ingredients...)
+	r.Instructions = append(StringList{}, s.Instructions...)
+	r.Nutrition = s.Nutrition
+	r.Tags = append(StringList{}, s.Tags...)
+	r.IsPublic = s.IsPublic
+	r.IsFeatured = s.IsFeatured
+	r.AllergenOverrides = s.AllergenOverrides
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Review is a user's rating and review of a recipe. Each user can review a
+// recipe once.
+type Review struct {
+	ID           string    `gorm:"type:varchar(255);primaryKey" json:"id"`
+	RecipeID     string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_reviews_recipe_user" json:"recipeId"`
+	UserID       string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_reviews_recipe_user;index" json:"userId"`
+	Rating       int       `gorm:"not null" json:"rating"`
+	Comment      string    `gorm:"type:text" json:"comment,omitempty"`
+	PhotoURL     string    `gorm:"type:text" json:"photoUrl,omitempty"`
+	HelpfulCount int       `gorm:"not null;default:0" json:"helpfulCount"`
+	CreatedAt    time.Time `gorm:"index" json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+
+	// Set per response and not stored
+	AuthorName string `gorm:"-" json:"authorName,omitempty"`
+	IsHelpful  bool   `gorm:"-" json:"isHelpful"`
+}
+
+// BeforeCreate hook to generate ID if not set
+func (r *Review) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = generateID("review")
+	}
+	return nil
+}
+
+// ReviewVote records that a user found a review helpful
+type ReviewVote struct {
+	ReviewID  string    `gorm:"type:varchar(255);primaryKey" json:"reviewId"`
+	UserID    string    `gorm:"type:varchar(255);primaryKey" json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Bayesian rating prior: an unreviewed recipe scores BayesianPriorMean, and
+// each real review moves the score as much as one of BayesianPriorWeight
+// imaginary average reviews
+const (
+	BayesianPriorMean   = 3.5
+	BayesianPriorWeight = 5
+)
+
+// BayesianRating blends a recipe's average rating with the prior, so a
+// single 5-star review does not outrank many good ones
+func BayesianRating(average float64, count int) float64 {
+	return (BayesianPriorMean*BayesianPriorWeight + average*float64(count)) / float64(BayesianPriorWeight+count)
+}
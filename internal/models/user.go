@@ -12,6 +12,7 @@ type User struct {
 	Email                   string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
 	Name                    string         `gorm:"type:varchar(255)" json:"name,omitempty"`
 	PasswordHash            string         `gorm:"type:varchar(255);not null" json:"-"`
+	Role                    string         `gorm:"type:varchar(50);not null;default:'user'" json:"role"`
 	HasCompletedOnboarding  bool           `gorm:"default:false" json:"hasCompletedOnboarding"`
 	CreatedAt               time.Time      `json:"createdAt"`
 	UpdatedAt               time.Time      `json:"updatedAt"`
@@ -26,10 +27,34 @@ type User struct {
 	Preferences             *UserPreferences `gorm:"embedded;embeddedPrefix:pref_" json:"preferences,omitempty"`
 }
 
+// User roles
+const (
+	RoleUser      = "user"
+	RoleAdmin     = "admin"
+)
+
 // UserPreferences stores user preferences
 type UserPreferences struct {
 	Theme         string `gorm:"type:varchar(50);default:'light'" json:"theme,omitempty"`
 	Notifications bool   `gorm:"default:true" json:"notifications,omitempty"`
+
+	// Collected during onboarding
+	Dietary          StringList `gorm:"type:jsonb" json:"dietary,omitempty"`
+	Allergies        StringList `gorm:"type:jsonb" json:"allergies,omitempty"`
+	HouseholdSize    int        `gorm:"default:0" json:"householdSize,omitempty"`
+	CookingSkill     string     `gorm:"type:varchar(50)" json:"cookingSkill,omitempty"`
+	Goals            StringList `gorm:"type:jsonb" json:"goals,omitempty"`
+	FavoriteCuisines StringList `gorm:"type:jsonb" json:"favoriteCuisines,omitempty"`
+	PlannedMeals     StringList `gorm:"type:jsonb" json:"plannedMeals,omitempty"`
+
+	// Pantry lists the ingredients the user keeps on hand
+	Pantry StringList `gorm:"type:jsonb" json:"pantry,omitempty"`
+
+	// Regional settings, applied when rendering dates, week boundaries and quantities
+	TimeZone   string `gorm:"type:varchar(64);default:'UTC'" json:"timeZone,omitempty"`
+	Locale     string `gorm:"type:varchar(20);default:'en-US'" json:"locale,omitempty"`
+	UnitSystem string `gorm:"type:varchar(20);default:'us'" json:"unitSystem,omitempty"`
+	WeekStart  string `gorm:"type:varchar(10);default:'sunday'" json:"weekStart,omitempty"`
 }
 
 // LoginAttemptInfo represents login attempt tracking information
@@ -44,8 +69,11 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	if u.ID == "" {
 		u.ID = generateID("user")
 	}
+	if u.Role == "" {
+		u.Role = RoleUser
+	}
 	if u.CreatedAt.IsZero() {
-		u.CreatedAt = time.Now()
+		u.CreatedAt = time.Now().UTC()
 	}
 	return nil
 }
@@ -56,8 +84,9 @@ func (u *User) ToPublicUser() *PublicUser {
 		ID:                     u.ID,
 		Email:                  u.Email,
 		Name:                   u.Name,
+		Role:                   u.Role,
 		HasCompletedOnboarding: u.HasCompletedOnboarding,
-		CreatedAt:              u.CreatedAt.Format(time.RFC3339),
+		CreatedAt:              u.CreatedAt.UTC().Format(time.RFC3339),
 		Preferences:            u.Preferences,
 	}
 }
@@ -67,11 +96,17 @@ type PublicUser struct {
 	ID                     string            `json:"id"`
 	Email                  string            `json:"email"`
 	Name                   string            `json:"name,omitempty"`
+	Role                   string            `json:"role"`
 	HasCompletedOnboarding bool              `json:"hasCompletedOnboarding"`
 	CreatedAt              string            `json:"createdAt"`
 	Preferences            *UserPreferences  `json:"preferences,omitempty"`
 }
 
+// IsAdmin checks if the user can curate public content
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
 // GetLoginAttemptInfo returns login attempt information
 func (u *User) GetLoginAttemptInfo() *LoginAttemptInfo {
 	return &LoginAttemptInfo{
@@ -93,14 +128,14 @@ func (u *User) IsAccountLocked() bool {
 func (u *User) ResetLoginAttempts() {
 	u.LoginAttempts = 0
 	u.AccountLockedUntil = nil
-	now := time.Now()
+	now := time.Now().UTC()
 	u.LastLoginAttempt = &now
 }
 
 // IncrementLoginAttempts increments failed login attempts
 func (u *User) IncrementLoginAttempts(maxAttempts int, lockDuration time.Duration) {
 	u.LoginAttempts++
-	now := time.Now()
+	now := time.Now().UTC()
 	u.LastLoginAttempt = &now
 
 	if u.LoginAttempts >= maxAttempts {
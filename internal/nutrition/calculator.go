@@ -0,0 +1,91 @@
+package nutrition
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+var (
+	ErrInvalidSex           = errors.New("sex must be one of: male, female")
+	ErrInvalidAge           = errors.New("age must be between 15 and 100")
+	ErrInvalidHeight        = errors.New("heightCm must be between 100 and 250")
+	ErrInvalidWeight        = errors.New("weightKg must be between 30 and 300")
+	ErrInvalidActivityLevel = errors.New("activityLevel must be one of: sedentary, light, moderate, active, very_active")
+)
+
+// activityMultipliers scale basal metabolic rate to total daily energy expenditure
+var activityMultipliers = map[string]float64{
+	"sedentary":   1.2,
+	"light":       1.375,
+	"moderate":    1.55,
+	"active":      1.725,
+	"very_active": 1.9,
+}
+
+// BodyMetrics are the inputs to the energy expenditure calculator
+type BodyMetrics struct {
+	Age           int     `json:"age"`
+	Sex           string  `json:"sex"`
+	HeightCm      float64 `json:"heightCm"`
+	WeightKg      float64 `json:"weightKg"`
+	ActivityLevel string  `json:"activityLevel"`
+}
+
+// Validate checks the metrics are within plausible ranges
+func (m *BodyMetrics) Validate() error {
+	m.Sex = strings.ToLower(strings.TrimSpace(m.Sex))
+	m.ActivityLevel = strings.ToLower(strings.TrimSpace(m.ActivityLevel))
+
+	if m.Sex != "male" && m.Sex != "female" {
+		return ErrInvalidSex
+	}
+	if m.Age < 15 || m.Age > 100 {
+		return ErrInvalidAge
+	}
+	if m.HeightCm < 100 || m.HeightCm > 250 {
+		return ErrInvalidHeight
+	}
+	if m.WeightKg < 30 || m.WeightKg > 300 {
+		return ErrInvalidWeight
+	}
+	if _, ok := activityMultipliers[m.ActivityLevel]; !ok {
+		return ErrInvalidActivityLevel
+	}
+	return nil
+}
+
+// BMR estimates basal metabolic rate in kcal/day using the Mifflin-St Jeor equation
+func BMR(m BodyMetrics) float64 {
+	bmr := 10*m.WeightKg + 6.25*m.HeightCm - 5*float64(m.Age)
+	if m.Sex == "male" {
+		return bmr + 5
+	}
+	return bmr - 161
+}
+
+// TDEE estimates total daily energy expenditure in kcal/day
+func TDEE(m BodyMetrics) float64 {
+	multiplier, ok := activityMultipliers[m.ActivityLevel]
+	if !ok {
+		multiplier = activityMultipliers["sedentary"]
+	}
+	return BMR(m) * multiplier
+}
+
+// Estimate calculates daily targets from body metrics for the given preset
+func Estimate(m BodyMetrics, presetKey string) (*Targets, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	preset, ok := FindPreset(presetKey)
+	if !ok {
+		return nil, ErrUnknownPreset
+	}
+	return preset.Targets(TDEE(m)), nil
+}
+
+// round1 rounds to one decimal place
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
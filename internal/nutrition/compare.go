@@ -0,0 +1,99 @@
+package nutrition
+
+import (
+	"math"
+)
+
+// Comparison statuses
+const (
+	StatusUnder   = "under"
+	StatusOnTrack = "on_track"
+	StatusOver    = "over"
+)
+
+// onTrackTolerance is how far from a target still counts as on track
+const onTrackTolerance = 0.10
+
+// Totals are the nutrients consumed or planned for a day
+type Totals struct {
+	Calories float64 `json:"calories"`
+	Protein  float64 `json:"protein"`
+	Carbs    float64 `json:"carbohydrates"`
+	Fat      float64 `json:"fat"`
+	Fiber    float64 `json:"fiber"`
+	Sodium   float64 `json:"sodium"`
+	Sugar    float64 `json:"sugar"`
+}
+
+// Comparison reports how one nutrient compares to its target or limit
+type Comparison struct {
+	Nutrient string  `json:"nutrient"`
+	Actual   float64 `json:"actual"`
+	Target   float64 `json:"target"`
+	IsLimit  bool    `json:"isLimit"`
+	Percent  float64 `json:"percent"`
+	Status   string  `json:"status"`
+}
+
+// Compare checks totals against targets. Calories and macros are targets to
+// hit within a tolerance, fiber is a minimum, and sodium and sugar are limits.
+func Compare(totals Totals, targets *Targets) []Comparison {
+	comparisons := []Comparison{
+		compareTarget("calories", totals.Calories, float64(targets.Calories)),
+		compareTarget("protein", totals.Protein, targets.ProteinGrams),
+		compareTarget("carbohydrates", totals.Carbs, targets.CarbsGrams),
+		compareTarget("fat", totals.Fat, targets.FatGrams),
+		compareMinimum("fiber", totals.Fiber, targets.FiberGrams),
+		compareLimit("sodium", totals.Sodium, targets.SodiumLimitMg),
+		compareLimit("sugar", totals.Sugar, targets.SugarLimitGrams),
+	}
+	return comparisons
+}
+
+func compareTarget(nutrient string, actual, target float64) Comparison {
+	c := newComparison(nutrient, actual, target, false)
+	switch {
+	case target <= 0:
+		c.Status = StatusOnTrack
+	case actual < target*(1-onTrackTolerance):
+		c.Status = StatusUnder
+	case actual > target*(1+onTrackTolerance):
+		c.Status = StatusOver
+	default:
+		c.Status = StatusOnTrack
+	}
+	return c
+}
+
+func compareMinimum(nutrient string, actual, target float64) Comparison {
+	c := newComparison(nutrient, actual, target, false)
+	if target > 0 && actual < target*(1-onTrackTolerance) {
+		c.Status = StatusUnder
+	} else {
+		c.Status = StatusOnTrack
+	}
+	return c
+}
+
+func compareLimit(nutrient string, actual, limit float64) Comparison {
+	c := newComparison(nutrient, actual, limit, true)
+	if limit > 0 && actual > limit {
+		c.Status = StatusOver
+	} else {
+		c.Status = StatusOnTrack
+	}
+	return c
+}
+
+func newComparison(nutrient string, actual, target float64, isLimit bool) Comparison {
+	c := Comparison{
+		Nutrient: nutrient,
+		Actual:   round1(actual),
+		Target:   round1(target),
+		IsLimit:  isLimit,
+	}
+	if target > 0 {
+		c.Percent = math.Round(actual / target * 100)
+	}
+	return c
+}
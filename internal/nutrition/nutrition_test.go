@@ -0,0 +1,156 @@
+package nutrition
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBMR(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics BodyMetrics
+		want    float64
+	}{
+		{
+			name:    "male",
+			metrics: BodyMetrics{Age: 30, Sex: "male", HeightCm: 180, WeightKg: 80},
+			want:    1780, // 800 + 1125 - 150 + 5
+		},
+		{
+			name:    "female",
+			metrics: BodyMetrics{Age: 30, Sex: "female", HeightCm: 165, WeightKg: 60},
+			want:    1320.25, // 600 + 1031.25 - 150 - 161
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BMR(tt.metrics); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("BMR() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBodyMetricsValidate(t *testing.T) {
+	valid := BodyMetrics{Age: 30, Sex: "Female", HeightCm: 165, WeightKg: 60, ActivityLevel: "Moderate"}
+
+	tests := []struct {
+		name    string
+		modify  func(m *BodyMetrics)
+		wantErr error
+	}{
+		{name: "valid metrics", modify: func(m *BodyMetrics) {}, wantErr: nil},
+		{name: "unknown sex", modify: func(m *BodyMetrics) { m.Sex = "other" }, wantErr: ErrInvalidSex},
+		{name: "too young", modify: func(m *BodyMetrics) { m.Age = 10 }, wantErr: ErrInvalidAge},
+		{name: "height out of range", modify: func(m *BodyMetrics) { m.HeightCm = 20 }, wantErr: ErrInvalidHeight},
+		{name: "weight out of range", modify: func(m *BodyMetrics) { m.WeightKg = 500 }, wantErr: ErrInvalidWeight},
+		{name: "unknown activity level", modify: func(m *BodyMetrics) { m.ActivityLevel = "extreme" }, wantErr: ErrInvalidActivityLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := valid
+			tt.modify(&m)
+			if err := m.Validate(); err != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	metrics := BodyMetrics{Age: 30, Sex: "male", HeightCm: 180, WeightKg: 80, ActivityLevel: "moderate"}
+	// TDEE = 1780 * 1.55 = 2759
+
+	tests := []struct {
+		name         string
+		preset       string
+		wantCalories int
+		wantProtein  float64
+		wantErr      error
+	}{
+		{name: "maintenance", preset: "maintenance", wantCalories: 2759, wantProtein: 138},
+		{name: "weight loss", preset: "weight_loss", wantCalories: 2259, wantProtein: 169.4},
+		{name: "high protein", preset: "high_protein", wantCalories: 2759, wantProtein: 241.4},
+		{name: "unknown preset", preset: "bulk", wantErr: ErrUnknownPreset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := Estimate(metrics, tt.preset)
+			if err != tt.wantErr {
+				t.Fatalf("Estimate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if targets.Calories != tt.wantCalories {
+				t.Errorf("Estimate() calories = %v, want %v", targets.Calories, tt.wantCalories)
+			}
+			if targets.ProteinGrams != tt.wantProtein {
+				t.Errorf("Estimate() protein = %v, want %v", targets.ProteinGrams, tt.wantProtein)
+			}
+		})
+	}
+}
+
+func TestPresetTargetsMinimumCalories(t *testing.T) {
+	preset, _ := FindPreset("weight_loss")
+	targets := preset.Targets(1400)
+	if targets.Calories != MinimumCalories {
+		t.Errorf("Targets() calories = %v, want %v", targets.Calories, MinimumCalories)
+	}
+}
+
+func TestSetMacroGrams(t *testing.T) {
+	targets := &Targets{Calories: 2000}
+	targets.SetMacroGrams(150, 200, 66.7)
+
+	if targets.ProteinPercent != 30 {
+		t.Errorf("ProteinPercent = %v, want 30", targets.ProteinPercent)
+	}
+	if targets.CarbsPercent != 40 {
+		t.Errorf("CarbsPercent = %v, want 40", targets.CarbsPercent)
+	}
+	if targets.FatPercent != 30 {
+		t.Errorf("FatPercent = %v, want 30", targets.FatPercent)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	targets := &Targets{
+		Calories:        2000,
+		ProteinGrams:    100,
+		CarbsGrams:      250,
+		FatGrams:        67,
+		FiberGrams:      28,
+		SodiumLimitMg:   2300,
+		SugarLimitGrams: 50,
+	}
+	totals := Totals{
+		Calories: 2050,
+		Protein:  60,
+		Carbs:    300,
+		Fat:      65,
+		Fiber:    30,
+		Sodium:   2500,
+		Sugar:    40,
+	}
+
+	want := map[string]string{
+		"calories":      StatusOnTrack,
+		"protein":       StatusUnder,
+		"carbohydrates": StatusOver,
+		"fat":           StatusOnTrack,
+		"fiber":         StatusOnTrack,
+		"sodium":        StatusOver,
+		"sugar":         StatusOnTrack,
+	}
+
+	for _, c := range Compare(totals, targets) {
+		if c.Status != want[c.Nutrient] {
+			t.Errorf("Compare() %s status = %v, want %v", c.Nutrient, c.Status, want[c.Nutrient])
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package nutrition
+
+import (
+	"errors"
+	"math"
+)
+
+// Energy per gram of each macronutrient
+const (
+	KcalPerGramProtein = 4.0
+	KcalPerGramCarbs   = 4.0
+	KcalPerGramFat     = 9.0
+)
+
+// Default limits, following common dietary guidelines
+const (
+	DefaultSodiumLimitMg = 2300.0
+	DefaultFiberGrams    = 28.0
+	MinimumCalories      = 1200
+)
+
+var ErrUnknownPreset = errors.New("preset must be one of: maintenance, weight_loss, high_protein")
+
+// Targets are a user's daily nutrition goals
+type Targets struct {
+	Calories        int     `json:"calories"`
+	ProteinGrams    float64 `json:"proteinGrams"`
+	CarbsGrams      float64 `json:"carbsGrams"`
+	FatGrams        float64 `json:"fatGrams"`
+	ProteinPercent  float64 `json:"proteinPercent"`
+	CarbsPercent    float64 `json:"carbsPercent"`
+	FatPercent      float64 `json:"fatPercent"`
+	FiberGrams      float64 `json:"fiberGrams"`
+	SodiumLimitMg   float64 `json:"sodiumLimitMg"`
+	SugarLimitGrams float64 `json:"sugarLimitGrams"`
+}
+
+// Preset is a named starting point for daily targets
+type Preset struct {
+	Key              string  `json:"key"`
+	Name             string  `json:"name"`
+	CalorieDelta     int     `json:"calorieDelta"`
+	ProteinPercent   float64 `json:"proteinPercent"`
+	CarbsPercent     float64 `json:"carbsPercent"`
+	FatPercent       float64 `json:"fatPercent"`
+	DefaultCalories  int     `json:"defaultCalories"`
+	SugarCaloriesPct float64 `json:"-"`
+}
+
+// Presets lists the available goal presets
+var Presets = []Preset{
+	{
+		Key:              "maintenance",
+		Name:             "Maintenance",
+		ProteinPercent:   20,
+		CarbsPercent:     50,
+		FatPercent:       30,
+		DefaultCalories:  2000,
+		SugarCaloriesPct: 10,
+	},
+	{
+		Key:              "weight_loss",
+		Name:             "Weight loss",
+		CalorieDelta:     -500,
+		ProteinPercent:   30,
+		CarbsPercent:     40,
+		FatPercent:       30,
+		DefaultCalories:  2000,
+		SugarCaloriesPct: 5,
+	},
+	{
+		Key:              "high_protein",
+		Name:             "High protein",
+		ProteinPercent:   35,
+		CarbsPercent:     35,
+		FatPercent:       30,
+		DefaultCalories:  2000,
+		SugarCaloriesPct: 10,
+	},
+}
+
+// FindPreset returns the preset with the given key
+func FindPreset(key string) (*Preset, bool) {
+	for i := range Presets {
+		if Presets[i].Key == key {
+			return &Presets[i], true
+		}
+	}
+	return nil, false
+}
+
+// Targets builds daily targets from the estimated maintenance calories.
+// A zero maintenance value uses the preset's default calories.
+func (p *Preset) Targets(maintenanceCalories float64) *Targets {
+	if maintenanceCalories <= 0 {
+		maintenanceCalories = float64(p.DefaultCalories)
+	}
+
+	calories := int(math.Round(maintenanceCalories)) + p.CalorieDelta
+	if calories < MinimumCalories {
+		calories = MinimumCalories
+	}
+
+	t := &Targets{
+		Calories:      calories,
+		FiberGrams:    DefaultFiberGrams,
+		SodiumLimitMg: DefaultSodiumLimitMg,
+	}
+	t.SetMacroPercents(p.ProteinPercent, p.CarbsPercent, p.FatPercent)
+	t.SugarLimitGrams = round1(float64(calories) * p.SugarCaloriesPct / 100 / KcalPerGramCarbs)
+	return t
+}
+
+// SetMacroPercents sets macros as percentages of calories and derives grams
+func (t *Targets) SetMacroPercents(protein, carbs, fat float64) {
+	t.ProteinPercent = round1(protein)
+	t.CarbsPercent = round1(carbs)
+	t.FatPercent = round1(fat)
+
+	calories := float64(t.Calories)
+	t.ProteinGrams = round1(calories * protein / 100 / KcalPerGramProtein)
+	t.CarbsGrams = round1(calories * carbs / 100 / KcalPerGramCarbs)
+	t.FatGrams = round1(calories * fat / 100 / KcalPerGramFat)
+}
+
+// SetMacroGrams sets macros in grams and derives their share of calories
+func (t *Targets) SetMacroGrams(protein, carbs, fat float64) {
+	t.ProteinGrams = round1(protein)
+	t.CarbsGrams = round1(carbs)
+	t.FatGrams = round1(fat)
+
+	if t.Calories <= 0 {
+		t.ProteinPercent, t.CarbsPercent, t.FatPercent = 0, 0, 0
+		return
+	}
+	calories := float64(t.Calories)
+	t.ProteinPercent = round1(protein * KcalPerGramProtein / calories * 100)
+	t.CarbsPercent = round1(carbs * KcalPerGramCarbs / calories * 100)
+	t.FatPercent = round1(fat * KcalPerGramFat / calories * 100)
+}
@@ -0,0 +1,69 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// cursor marks a position in a sorted result set
+type cursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+
+	// Before selects the page preceding the position instead of following it
+	Before bool `json:"b,omitempty"`
+}
+
+func encodeCursor(c *cursor) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(value string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// keysetCondition builds the WHERE condition selecting rows after (or, when
+// backward, before) the row with the given sort values. With mixed sort
+// directions a row-value comparison does not work, so it expands to
+//
+//	(a > ?) OR (a = ? AND b < ?) OR (a = ? AND b = ? AND id > ?)
+func keysetCondition(fields []SortField, values []interface{}, backward bool) clause.Expr {
+	var sql strings.Builder
+	var vars []interface{}
+
+	sql.WriteString("(")
+	for i, field := range fields {
+		if i > 0 {
+			sql.WriteString(" OR ")
+		}
+		sql.WriteString("(")
+		for j := 0; j < i; j++ {
+			sql.WriteString("? = ? AND ")
+			vars = append(vars, clause.Column{Name: fields[j].Column}, values[j])
+		}
+		op := " > "
+		if field.Desc != backward {
+			op = " < "
+		}
+		sql.WriteString("?" + op + "?)")
+		vars = append(vars, clause.Column{Name: field.Column}, values[i])
+	}
+	sql.WriteString(")")
+
+	return clause.Expr{SQL: sql.String(), Vars: vars}
+}
@@ -0,0 +1,134 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var schemaCache sync.Map
+
+// Find counts the rows matched by query, loads the requested page into dest
+// and returns the pagination envelope. The query should hold only filters;
+// Find applies ordering, limit and offset or cursor.
+func Find[T any](query *gorm.DB, p *Params, dest *[]T) (*Pagination, error) {
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	sch, err := schema.Parse(new(T), &schemaCache, query.NamingStrategy)
+	if err != nil {
+		return nil, err
+	}
+	sortFields := make([]*schema.Field, len(p.Sort))
+	for i, s := range p.Sort {
+		if sortFields[i] = sch.LookUpField(unqualified(s.Column)); sortFields[i] == nil && !p.config.OffsetOnly {
+			return nil, fmt.Errorf("pagination: %s has no column %q", sch.Name, s.Column)
+		}
+	}
+
+	backward := p.cursor != nil && p.cursor.Before
+	q := query
+	if p.cursor != nil {
+		values, err := cursorValues(p.cursor, sortFields)
+		if err != nil {
+			return nil, newError("cursor is invalid or does not match the sort order")
+		}
+		q = q.Where(keysetCondition(p.Sort, values, backward))
+	} else if p.Page > 1 {
+		q = q.Offset((p.Page - 1) * p.Limit)
+	}
+	for _, s := range p.Sort {
+		q = q.Order(clause.OrderByColumn{Column: clause.Column{Name: s.Column}, Desc: s.Desc != backward})
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	var items []T
+	if err := q.Limit(p.Limit + 1).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	more := len(items) > p.Limit
+	if more {
+		items = items[:p.Limit]
+	}
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	*dest = items
+
+	page := &Pagination{Limit: p.Limit, Total: total}
+	switch {
+	case p.cursor == nil:
+		page.Page = p.Page
+		page.TotalPages = int((total + int64(p.Limit) - 1) / int64(p.Limit))
+		page.HasNext = more
+		page.HasPrev = p.Page > 1
+	case backward:
+		page.HasNext = true
+		page.HasPrev = more
+	default:
+		page.HasNext = more
+		page.HasPrev = true
+	}
+
+	if !p.config.OffsetOnly && len(items) > 0 {
+		if page.HasNext {
+			if page.NextCursor, err = itemCursor(p.Sort, sortFields, &items[len(items)-1], false); err != nil {
+				return nil, err
+			}
+		}
+		if page.HasPrev {
+			if page.PrevCursor, err = itemCursor(p.Sort, sortFields, &items[0], true); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return page, nil
+}
+
+// unqualified strips the table name from a column such as "favorites.created_at"
+func unqualified(column string) string {
+	return column[strings.LastIndex(column, ".")+1:]
+}
+
+// cursorValues decodes the cursor's sort values into the Go types of the
+// corresponding model fields, so they bind to the right column types
+func cursorValues(c *cursor, fields []*schema.Field) ([]interface{}, error) {
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		v := reflect.New(field.FieldType)
+		if err := json.Unmarshal(c.Values[i], v.Interface()); err != nil {
+			return nil, err
+		}
+		values[i] = v.Elem().Interface()
+	}
+	return values, nil
+}
+
+// itemCursor builds a cursor positioned at item
+func itemCursor(sortFields []SortField, fields []*schema.Field, item interface{}, before bool) (string, error) {
+	c := &cursor{Sort: sortSignature(sortFields), Before: before}
+	rv := reflect.ValueOf(item).Elem()
+	for _, field := range fields {
+		value, _ := field.ValueOf(context.Background(), rv)
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		c.Values = append(c.Values, raw)
+	}
+	return encodeCursor(c)
+}
@@ -0,0 +1,229 @@
+// Package pagination implements the list conventions from API_SPECIFICATION.md:
+// limit, page or cursor, and sort parameters checked against a per-resource
+// allowlist, applied to a GORM query and returned in the standard envelope.
+//
+// Cursors are opaque keyset cursors holding the sort values of the last (or
+// first) item of a page, so pages stay stable when rows are inserted.
+package pagination
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+
+	// maxSortFields caps the number of fields a client may sort by
+	maxSortFields = 3
+)
+
+// Error is returned for invalid pagination or sort parameters
+type Error struct {
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newError(format string, args ...interface{}) error {
+	return &Error{Message: fmt.Sprintf(format, args...)}
+}
+
+// Config describes how a resource may be paginated and sorted
+type Config struct {
+	// DefaultLimit and MaxLimit default to the package constants
+	DefaultLimit int
+	MaxLimit     int
+
+	// Sortable maps the field names clients may sort by to their columns.
+	// Columns may be table-qualified when the query joins other tables.
+	Sortable map[string]string
+
+	// DefaultSort is used when the request has no sort, e.g. "-createdAt"
+	DefaultSort string
+
+	// KeyColumn is a unique column appended to every sort as a tie-breaker.
+	// Defaults to "id".
+	KeyColumn string
+
+	// OffsetOnly disables cursors, for results ordered by computed values
+	// (such as search rank) that cannot be compared in a WHERE clause
+	OffsetOnly bool
+}
+
+// SortField is one field of a sort order
+type SortField struct {
+	Field  string
+	Column string
+	Desc   bool
+}
+
+// Params are the parsed pagination parameters of a request
+type Params struct {
+	Limit  int
+	Page   int
+	Sort   []SortField
+	cursor *cursor
+	config Config
+}
+
+// Pagination is the standard pagination envelope. Page and TotalPages are
+// only set for page-based requests.
+type Pagination struct {
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	TotalPages int    `json:"totalPages,omitempty"`
+	HasNext    bool   `json:"hasNext"`
+	HasPrev    bool   `json:"hasPrev"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+}
+
+// Parse reads limit, page, cursor and sort from the request query string
+func Parse(c *gin.Context, cfg Config) (*Params, error) {
+	return ParseQuery(c.Request.URL.Query(), cfg)
+}
+
+// ParseQuery reads limit, page, cursor and sort from query values
+func ParseQuery(values url.Values, cfg Config) (*Params, error) {
+	if cfg.DefaultLimit <= 0 {
+		cfg.DefaultLimit = DefaultLimit
+	}
+	if cfg.MaxLimit <= 0 {
+		cfg.MaxLimit = MaxLimit
+	}
+	if cfg.KeyColumn == "" {
+		cfg.KeyColumn = "id"
+	}
+
+	p := &Params{Limit: cfg.DefaultLimit, Page: 1, config: cfg}
+
+	if v := values.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return nil, newError("limit must be a positive number")
+		}
+		if limit > cfg.MaxLimit {
+			limit = cfg.MaxLimit
+		}
+		p.Limit = limit
+	}
+
+	sortParam := values.Get("sort")
+	if sortParam == "" {
+		sortParam = cfg.DefaultSort
+	}
+	sortFields, err := parseSort(sortParam, cfg)
+	if err != nil {
+		return nil, err
+	}
+	p.Sort = sortFields
+
+	cursorParam := values.Get("cursor")
+	pageParam := values.Get("page")
+	if cursorParam != "" && pageParam != "" {
+		return nil, newError("use either page or cursor, not both")
+	}
+	if pageParam != "" {
+		page, err := strconv.Atoi(pageParam)
+		if err != nil || page < 1 {
+			return nil, newError("page must be a positive number")
+		}
+		p.Page = page
+	}
+	if cursorParam != "" {
+		if cfg.OffsetOnly {
+			return nil, newError("cursor is not supported here; use page")
+		}
+		cur, err := decodeCursor(cursorParam)
+		if err != nil || cur.Sort != sortSignature(p.Sort) || len(cur.Values) != len(p.Sort) {
+			return nil, newError("cursor is invalid or does not match the sort order")
+		}
+		p.cursor = cur
+		p.Page = 0
+	}
+
+	return p, nil
+}
+
+// parseSort parses "-rating,name" or "rating:desc,name:asc" against the
+// allowlist and appends the key column as a tie-breaker
+func parseSort(value string, cfg Config) ([]SortField, error) {
+	var fields []SortField
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		desc := false
+		switch {
+		case strings.HasPrefix(part, "-"):
+			desc = true
+			part = part[1:]
+		case strings.HasPrefix(part, "+"):
+			part = part[1:]
+		}
+		if name, dir, ok := strings.Cut(part, ":"); ok {
+			switch strings.ToLower(dir) {
+			case "asc":
+			case "desc":
+				desc = true
+			default:
+				return nil, newError("sort direction must be asc or desc")
+			}
+			part = name
+		}
+
+		column, ok := cfg.Sortable[part]
+		if !ok {
+			return nil, newError("cannot sort by %q; allowed fields: %s", part, strings.Join(sortableNames(cfg), ", "))
+		}
+		if seen[column] {
+			return nil, newError("cannot sort by %q more than once", part)
+		}
+		seen[column] = true
+		fields = append(fields, SortField{Field: part, Column: column, Desc: desc})
+	}
+
+	if len(fields) > maxSortFields {
+		return nil, newError("cannot sort by more than %d fields", maxSortFields)
+	}
+	if !seen[cfg.KeyColumn] {
+		fields = append(fields, SortField{Field: cfg.KeyColumn, Column: cfg.KeyColumn})
+	}
+	return fields, nil
+}
+
+func sortableNames(cfg Config) []string {
+	names := make([]string, 0, len(cfg.Sortable))
+	for name := range cfg.Sortable {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortSignature identifies a sort order, so cursors cannot be reused with
+// a different one
+func sortSignature(fields []SortField) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.Column
+		if f.Desc {
+			parts[i] = "-" + f.Column
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,163 @@
+package pagination
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm/clause"
+)
+
+var testConfig = Config{
+	Sortable: map[string]string{
+		"name":      "name",
+		"rating":    "rating",
+		"createdAt": "created_at",
+	},
+	DefaultSort: "-createdAt",
+}
+
+func TestParseQuerySort(t *testing.T) {
+	tests := []struct {
+		name    string
+		sort    string
+		want    string
+		wantErr bool
+	}{
+		{name: "default sort", sort: "", want: "-created_at,id"},
+		{name: "prefix syntax", sort: "-rating,name", want: "-rating,name,id"},
+		{name: "colon syntax", sort: "rating:desc,createdAt:asc", want: "-rating,created_at,id"},
+		{name: "plus prefix", sort: "+name", want: "name,id"},
+		{name: "unknown field", sort: "password", wantErr: true},
+		{name: "bad direction", sort: "name:up", wantErr: true},
+		{name: "duplicate field", sort: "name,-name", wantErr: true},
+		{name: "too many fields", sort: "name,rating,createdAt,name", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseQuery(url.Values{"sort": {tt.sort}}, testConfig)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := sortSignature(p.Sort); got != tt.want {
+				t.Errorf("sort = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQueryLimitAndPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     url.Values
+		wantLimit int
+		wantPage  int
+		wantErr   bool
+	}{
+		{name: "defaults", query: url.Values{}, wantLimit: DefaultLimit, wantPage: 1},
+		{name: "explicit", query: url.Values{"limit": {"5"}, "page": {"3"}}, wantLimit: 5, wantPage: 3},
+		{name: "limit is capped", query: url.Values{"limit": {"1000"}}, wantLimit: MaxLimit, wantPage: 1},
+		{name: "invalid limit", query: url.Values{"limit": {"abc"}}, wantErr: true},
+		{name: "zero page", query: url.Values{"page": {"0"}}, wantErr: true},
+		{name: "page and cursor", query: url.Values{"page": {"2"}, "cursor": {"abc"}}, wantErr: true},
+		{name: "garbage cursor", query: url.Values{"cursor": {"!!!"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseQuery(tt.query, testConfig)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if _, ok := err.(*Error); !ok {
+					t.Errorf("error type = %T, want *Error", err)
+				}
+				return
+			}
+			if p.Limit != tt.wantLimit || p.Page != tt.wantPage {
+				t.Errorf("limit, page = %d, %d, want %d, %d", p.Limit, p.Page, tt.wantLimit, tt.wantPage)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	p, err := ParseQuery(url.Values{"sort": {"-rating"}}, testConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := encodeCursor(&cursor{
+		Sort:   sortSignature(p.Sort),
+		Values: []json.RawMessage{json.RawMessage(`4.5`), json.RawMessage(`"recipe-1"`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err = ParseQuery(url.Values{"sort": {"-rating"}, "cursor": {encoded}}, testConfig)
+	if err != nil {
+		t.Fatalf("ParseQuery() with cursor error = %v", err)
+	}
+	if p.cursor == nil || p.cursor.Before || p.Page != 0 {
+		t.Errorf("cursor not applied: %+v", p)
+	}
+
+	// A cursor is bound to the sort order it was issued for
+	if _, err := ParseQuery(url.Values{"sort": {"name"}, "cursor": {encoded}}, testConfig); err == nil {
+		t.Error("ParseQuery() accepted a cursor for a different sort")
+	}
+
+	offsetOnly := testConfig
+	offsetOnly.OffsetOnly = true
+	if _, err := ParseQuery(url.Values{"sort": {"-rating"}, "cursor": {encoded}}, offsetOnly); err == nil {
+		t.Error("ParseQuery() accepted a cursor with OffsetOnly")
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	fields := []SortField{
+		{Column: "rating", Desc: true},
+		{Column: "id"},
+	}
+	values := []interface{}{4.5, "recipe-1"}
+	rating := clause.Column{Name: "rating"}
+	id := clause.Column{Name: "id"}
+
+	tests := []struct {
+		name     string
+		backward bool
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			name:     "forward",
+			wantSQL:  "((? < ?) OR (? = ? AND ? > ?))",
+			wantVars: []interface{}{rating, 4.5, rating, 4.5, id, "recipe-1"},
+		},
+		{
+			name:     "backward",
+			backward: true,
+			wantSQL:  "((? > ?) OR (? = ? AND ? < ?))",
+			wantVars: []interface{}{rating, 4.5, rating, 4.5, id, "recipe-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr := keysetCondition(fields, values, tt.backward)
+			if expr.SQL != tt.wantSQL {
+				t.Errorf("SQL = %q, want %q", expr.SQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(expr.Vars, tt.wantVars) {
+				t.Errorf("Vars = %v, want %v", expr.Vars, tt.wantVars)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Package pantry matches recipe ingredients against the ingredients a cook
+// has on hand. Names are compared as normalized words (see foods.Tokens), so
+// an item on hand satisfies every ingredient it is a kind of: "chicken
+// thighs" satisfies "chicken" and "large eggs" satisfies "egg", but
+// "chicken" does not satisfy "chicken thighs".
+package pantry
+
+import (
+	"strings"
+
+	"github.com/meal-planner/backend/internal/foods"
+	"github.com/meal-planner/backend/internal/models"
+)
+
+// staples are ingredients kitchens are assumed to have, by their normalized
+// names
+var staples = map[string]bool{
+	"salt": true, "sea salt": true, "kosher salt": true, "table salt": true,
+	"pepper": true, "black pepper": true, "ground black pepper": true,
+	"ground pepper": true, "salt pepper": true,
+	"oil": true, "olive oil": true, "vegetable oil": true, "canola oil": true,
+	"cooking oil": true, "neutral oil": true, "cooking spray": true,
+	"water": true, "cold water": true, "warm water": true, "hot water": true,
+	"boiling water": true, "ice": true, "ice water": true,
+}
+
+// optionalNotes mark ingredients a recipe can do without
+var optionalNotes = []string{"optional", "for garnish", "to garnish", "for serving", "to serve"}
+
+// IsStaple reports whether an ingredient is assumed to be on hand
+func IsStaple(name string) bool {
+	return staples[key(foods.Tokens(name))]
+}
+
+func key(tokens []string) string {
+	return strings.Join(tokens, " ")
+}
+
+// isOptional reports whether an ingredient's note marks it as optional
+func isOptional(note string) bool {
+	note = strings.ToLower(note)
+	for _, phrase := range optionalNotes {
+		if strings.Contains(note, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pantry is a set of ingredients on hand
+type Pantry struct {
+	// items are the words of each item on hand
+	items []map[string]bool
+	// terms are the words worth searching for: those of items that are
+	// not staples, leaving out words that are staples themselves
+	terms []string
+	// assumeStaples treats staples as on hand without listing them
+	assumeStaples bool
+}
+
+// New returns a pantry holding the named items. With assumeStaples, staples
+// such as salt, oil and water count as on hand too.
+func New(items []string, assumeStaples bool) *Pantry {
+	p := &Pantry{assumeStaples: assumeStaples}
+	for _, item := range items {
+		tokens := foods.Tokens(item)
+		if len(tokens) == 0 {
+			continue
+		}
+		set := make(map[string]bool, len(tokens))
+		for _, t := range tokens {
+			set[t] = true
+		}
+		p.items = append(p.items, set)
+		if !staples[key(tokens)] {
+			for _, t := range tokens {
+				if !staples[t] {
+					p.terms = append(p.terms, t)
+				}
+			}
+		}
+	}
+	return p
+}
+
+// Len returns the number of items in the pantry, not counting staples
+func (p *Pantry) Len() int {
+	return len(p.items)
+}
+
+// Has reports whether an ingredient is on hand: whether some item has every
+// word of the ingredient's name
+func (p *Pantry) Has(name string) bool {
+	tokens := foods.Tokens(name)
+	if len(tokens) == 0 {
+		return false
+	}
+	if p.assumeStaples && staples[key(tokens)] {
+		return true
+	}
+	for _, item := range p.items {
+		if containsAll(item, tokens) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(set map[string]bool, tokens []string) bool {
+	for _, t := range tokens {
+		if !set[t] {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchTerms returns substrings that the name of every ingredient the
+// pantry has must contain at least one of, for narrowing a database search.
+// Staples, and staple words such as "oil" in "sesame oil", are left out:
+// nearly every recipe uses them, so they would match the whole catalog.
+func (p *Pantry) SearchTerms() []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, t := range p.terms {
+		// Tokens are singular; "berry" is found in "berries" as "berr"
+		if len(t) > 3 && strings.HasSuffix(t, "y") {
+			t = t[:len(t)-1]
+		}
+		if !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+// Match is how much of a recipe the pantry covers
+type Match struct {
+	// Required counts the distinct ingredients the recipe needs, leaving out
+	// optional ones and, when assumed, staples
+	Required int
+	Matched  int
+	// Coverage is Matched over Required, or 1 when nothing is required
+	Coverage float64
+	// Missing names the required ingredients not on hand, as the recipe
+	// writes them
+	Missing []string
+}
+
+// Match compares a recipe's ingredients with the pantry
+func (p *Pantry) Match(ingredients []models.RecipeIngredient) Match {
+	m := Match{Missing: []string{}}
+	seen := make(map[string]bool)
+	for _, ing := range ingredients {
+		tokens := foods.Tokens(ing.Name)
+		k := key(tokens)
+		if k == "" || seen[k] || isOptional(ing.Note) {
+			continue
+		}
+		seen[k] = true
+		if p.assumeStaples && staples[k] {
+			continue
+		}
+
+		m.Required++
+		if p.Has(ing.Name) {
+			m.Matched++
+		} else {
+			m.Missing = append(m.Missing, ing.Name)
+		}
+	}
+	m.Coverage = 1
+	if m.Required > 0 {
+		m.Coverage = float64(m.Matched) / float64(m.Required)
+	}
+	return m
+}
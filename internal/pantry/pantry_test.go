@@ -0,0 +1,113 @@
+package pantry
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/meal-planner/backend/internal/models"
+)
+
+func TestHas(t *testing.T) {
+	p := New([]string{"chicken thighs", "Large Eggs", "extra-virgin olive oil", "cherry tomatoes", "  "}, false)
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"chicken", true},
+		{"Chicken Thigh", true},
+		{"boneless skinless chicken thighs", true},
+		{"chicken breast", false},
+		{"eggs", true},
+		{"olive oil", true},
+		{"tomatoes", true},
+		{"cherry tomato", true},
+		{"sun-dried tomatoes", false},
+		{"salt", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := p.Has(tt.name); got != tt.want {
+			t.Errorf("Has(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if p.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", p.Len())
+	}
+}
+
+func TestIsStaple(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"salt", true},
+		{"Kosher Salt", true},
+		{"freshly ground black pepper", true},
+		{"salt and pepper", true},
+		{"extra virgin olive oil", true},
+		{"water", true},
+		{"sesame oil", false},
+		{"coconut water", false},
+		{"red bell pepper", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsStaple(tt.name); got != tt.want {
+			t.Errorf("IsStaple(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	ingredients := []models.RecipeIngredient{
+		{Name: "chicken"},
+		{Name: "rice"},
+		{Name: "onion"},
+		{Name: "onions", Note: "sliced"},
+		{Name: "salt"},
+		{Name: "vegetable oil"},
+		{Name: "cilantro", Note: "for garnish"},
+		{Name: "lime", Note: "optional"},
+	}
+
+	p := New([]string{"chicken thighs", "rice"}, true)
+	got := p.Match(ingredients)
+	want := Match{Required: 3, Matched: 2, Coverage: 2.0 / 3, Missing: []string{"onion"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Match() = %+v, want %+v", got, want)
+	}
+
+	// Without assumed staples, salt and oil must be on hand
+	got = New([]string{"chicken thighs", "rice"}, false).Match(ingredients)
+	want = Match{Required: 5, Matched: 2, Coverage: 0.4, Missing: []string{"onion", "salt", "vegetable oil"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Match() without staples = %+v, want %+v", got, want)
+	}
+
+	got = p.Match([]models.RecipeIngredient{{Name: "water"}, {Name: "salt"}})
+	if got.Required != 0 || got.Coverage != 1 {
+		t.Errorf("Match(staples only) = %+v, want nothing required and full coverage", got)
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		items []string
+		want  []string
+	}{
+		{[]string{"blueberries", "chicken thighs", "chicken"}, []string{"blueberr", "chicken", "thigh"}},
+		// Staples would match nearly every recipe
+		{[]string{"salt", "olive oil", "sesame oil", "water", "tofu"}, []string{"sesame", "tofu"}},
+		{[]string{"kosher salt"}, nil},
+	}
+	for _, tt := range tests {
+		got := New(tt.items, false).SearchTerms()
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("New(%q).SearchTerms() = %v, want %v", tt.items, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Package recipeimport extracts schema.org Recipe data from HTML pages, as
+// published by most food blogs in JSON-LD or microdata, and normalizes it
+// into a recipe draft.
+package recipeimport
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/models"
+	"golang.org/x/net/html"
+)
+
+// ErrNoRecipe is returned when the page has no schema.org Recipe
+var ErrNoRecipe = errors.New("no schema.org recipe found in the page")
+
+// Source formats
+const (
+	SourceJSONLD    = "json-ld"
+	SourceMicrodata = "microdata"
+)
+
+// Draft is an imported recipe in the shape of a create-recipe request, for
+// the user to review before saving
+type Draft struct {
+	Name         string                    `json:"name"`
+	Description  string                    `json:"description,omitempty"`
+	Category     string                    `json:"category,omitempty"`
+	Cuisine      string                    `json:"cuisine,omitempty"`
+	PrepTime     int                       `json:"prepTime"`
+	CookTime     int                       `json:"cookTime"`
+	Servings     int                       `json:"servings,omitempty"`
+	Difficulty   string                    `json:"difficulty,omitempty"`
+	Ingredients  []models.RecipeIngredient `json:"ingredients"`
+	Instructions []string                  `json:"instructions"`
+	Nutrition    models.RecipeNutrition    `json:"nutrition"`
+	Tags         []string                  `json:"tags"`
+	ImageURL     string                    `json:"imageUrl,omitempty"`
+	SourceURL    string                    `json:"sourceUrl,omitempty"`
+}
+
+// Result is the outcome of an import. Warnings list fields that were
+// missing or could not be parsed.
+type Result struct {
+	Draft    Draft    `json:"draft"`
+	Source   string   `json:"source"`
+	Warnings []string `json:"warnings"`
+}
+
+func (r *Result) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Parse reads an HTML page and extracts its recipe, preferring JSON-LD over
+// microdata when a page has both
+func Parse(r io.Reader) (*Result, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	if node := findJSONLDRecipe(doc); node != nil {
+		return convert(node, SourceJSONLD), nil
+	}
+	if node := findMicrodataRecipe(doc); node != nil {
+		return convert(node, SourceMicrodata), nil
+	}
+	return nil, ErrNoRecipe
+}
+
+// convert maps a schema.org Recipe object onto a draft. Microdata is first
+// turned into the same map shape as JSON-LD, so both share this code.
+func convert(node map[string]interface{}, source string) *Result {
+	res := &Result{Source: source, Warnings: []string{}}
+	d := &res.Draft
+
+	d.Name = cleanText(firstString(node["name"]))
+	if d.Name == "" {
+		res.warn("name: not found")
+	}
+	d.Description = cleanText(firstString(node["description"]))
+	d.ImageURL = imageURL(node["image"])
+	d.SourceURL = strings.TrimSpace(firstString(node["url"]))
+
+	categories := strings.Join(stringsOf(node["recipeCategory"]), ", ")
+	if category, ok := MatchCategory(categories); ok {
+		d.Category = category
+	} else if categories != "" {
+		res.warn("recipeCategory: %q does not match a known category", categories)
+	} else {
+		res.warn("recipeCategory: not found")
+	}
+	if cuisines := stringsOf(node["recipeCuisine"]); len(cuisines) > 0 {
+		d.Cuisine = cleanText(cuisines[0])
+	}
+
+	convertTimes(node, res)
+
+	if yield, ok := node["recipeYield"]; ok {
+		servings, ok := parseYield(yield)
+		if ok {
+			d.Servings = servings
+		} else {
+			res.warn("recipeYield: could not read a number of servings from %q", strings.Join(stringsOf(yield), ", "))
+		}
+	} else {
+		res.warn("recipeYield: not found")
+	}
+
+	ingredients := node["recipeIngredient"]
+	if ingredients == nil {
+		ingredients = node["ingredients"]
+	}
+	d.Ingredients = []models.RecipeIngredient{}
+	for _, line := range stringsOf(ingredients) {
+		if line = cleanText(line); line != "" {
+			ing := models.ParseRecipeIngredient(line)
+			if ing.UnknownUnit {
+				res.warn("recipeIngredient: unrecognized unit %q in %q", ing.Unit, line)
+			}
+			d.Ingredients = append(d.Ingredients, ing)
+		}
+	}
+	if len(d.Ingredients) == 0 {
+		res.warn("recipeIngredient: not found")
+	}
+
+	d.Instructions = instructionSteps(node["recipeInstructions"])
+	if len(d.Instructions) == 0 {
+		res.warn("recipeInstructions: not found")
+	}
+
+	d.Tags = keywords(node["keywords"])
+
+	if nutrition, ok := firstMap(node["nutrition"]); ok {
+		convertNutrition(nutrition, res)
+	}
+
+	return res
+}
+
+// convertTimes fills prep and cook time, deriving a missing one from
+// totalTime when possible
+func convertTimes(node map[string]interface{}, res *Result) {
+	minutes := func(field string) (int, bool) {
+		value := strings.TrimSpace(firstString(node[field]))
+		if value == "" {
+			return 0, false
+		}
+		m, err := ParseDuration(value)
+		if err != nil {
+			res.warn("%s: %v", field, err)
+			return 0, false
+		}
+		return m, true
+	}
+
+	prep, hasPrep := minutes("prepTime")
+	cook, hasCook := minutes("cookTime")
+	total, hasTotal := minutes("totalTime")
+
+	switch {
+	case hasTotal && !hasPrep && !hasCook:
+		cook = total
+		res.warn("prepTime, cookTime: not found; totalTime used as cook time")
+	case hasTotal && hasPrep && !hasCook && total >= prep:
+		cook = total - prep
+	case hasTotal && hasCook && !hasPrep && total >= cook:
+		prep = total - cook
+	case !hasPrep && !hasCook && !hasTotal:
+		res.warn("prepTime, cookTime: not found")
+	}
+
+	res.Draft.PrepTime = prep
+	res.Draft.CookTime = cook
+}
+
+// convertNutrition reads NutritionInformation values such as "240 calories"
+// or "12 g"
+func convertNutrition(node map[string]interface{}, res *Result) {
+	fields := []struct {
+		name string
+		dest *float64
+	}{
+		{"calories", &res.Draft.Nutrition.Calories},
+		{"proteinContent", &res.Draft.Nutrition.Protein},
+		{"carbohydrateContent", &res.Draft.Nutrition.Carbohydrates},
+		{"fatContent", &res.Draft.Nutrition.Fat},
+		{"fiberContent", &res.Draft.Nutrition.Fiber},
+		{"sugarContent", &res.Draft.Nutrition.Sugar},
+		{"sodiumContent", &res.Draft.Nutrition.Sodium},
+	}
+	for _, f := range fields {
+		value := firstString(node[f.name])
+		if value == "" {
+			continue
+		}
+		n, ok := leadingNumber(value)
+		if !ok {
+			res.warn("nutrition.%s: could not read %q", f.name, value)
+			continue
+		}
+		*f.dest = n
+	}
+}
@@ -0,0 +1,116 @@
+package recipeimport
+
+import (
+	"encoding/json"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// maxSearchDepth bounds the search for a Recipe inside nested JSON-LD
+const maxSearchDepth = 8
+
+// findJSONLDRecipe returns the first Recipe object in the page's JSON-LD
+// scripts, looking inside arrays and @graph containers
+func findJSONLDRecipe(doc *html.Node) map[string]interface{} {
+	var found map[string]interface{}
+	walk(doc, func(n *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if n.Type != html.ElementNode || n.DataAtom != atom.Script ||
+			!strings.Contains(strings.ToLower(attr(n, "type")), "ld+json") {
+			return true
+		}
+
+		var data interface{}
+		if err := json.Unmarshal([]byte(scriptText(n)), &data); err != nil {
+			// Skip malformed blocks; another block may hold the recipe
+			return false
+		}
+		found = findRecipeObject(data, 0)
+		return false
+	})
+	return found
+}
+
+func findRecipeObject(v interface{}, depth int) map[string]interface{} {
+	if depth > maxSearchDepth {
+		return nil
+	}
+	switch v := v.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if recipe := findRecipeObject(item, depth+1); recipe != nil {
+				return recipe
+			}
+		}
+	case map[string]interface{}:
+		if hasType(v, "Recipe") {
+			return v
+		}
+		for _, key := range []string{"@graph", "mainEntity", "mainEntityOfPage", "itemListElement", "item"} {
+			if recipe := findRecipeObject(v[key], depth+1); recipe != nil {
+				return recipe
+			}
+		}
+	}
+	return nil
+}
+
+// hasType reports whether a JSON-LD object has the given @type, which may
+// be a string or a list and may be a full schema.org URL
+func hasType(obj map[string]interface{}, want string) bool {
+	for _, t := range stringsOf(obj["@type"]) {
+		t = t[strings.LastIndex(t, "/")+1:]
+		if strings.EqualFold(t, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func scriptText(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+	}
+	text := strings.TrimSpace(b.String())
+	// Some sites wrap the JSON in an HTML comment or CDATA section
+	text = strings.TrimPrefix(text, "<!--")
+	text = strings.TrimSuffix(text, "-->")
+	text = strings.TrimPrefix(text, "//<![CDATA[")
+	text = strings.TrimSuffix(text, "//]]>")
+	return strings.TrimSpace(text)
+}
+
+// walk visits nodes depth first; returning false skips a node's children
+func walk(n *html.Node, visit func(*html.Node) bool) {
+	if !visit(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, visit)
+	}
+}
+
+func attr(n *html.Node, name string) string {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, name string) bool {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package recipeimport
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// findMicrodataRecipe returns the properties of the first element with
+// itemtype schema.org/Recipe, in the same shape as a JSON-LD object
+func findMicrodataRecipe(doc *html.Node) map[string]interface{} {
+	var found map[string]interface{}
+	walk(doc, func(n *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if n.Type == html.ElementNode && hasAttr(n, "itemscope") && isRecipeItemType(attr(n, "itemtype")) {
+			found = microdataItem(n)
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+func isRecipeItemType(itemType string) bool {
+	for _, t := range strings.Fields(itemType) {
+		t = strings.TrimSuffix(t, "/")
+		if strings.EqualFold(t[strings.LastIndex(t, "/")+1:], "Recipe") {
+			return true
+		}
+	}
+	return false
+}
+
+// microdataItem collects the properties of an itemscope element. Nested
+// items become nested maps; repeated properties become lists.
+func microdataItem(scope *html.Node) map[string]interface{} {
+	item := map[string]interface{}{}
+	if itemType := attr(scope, "itemtype"); itemType != "" {
+		item["@type"] = itemType
+	}
+
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+
+			props := strings.Fields(attr(c, "itemprop"))
+			nested := hasAttr(c, "itemscope")
+			if len(props) > 0 {
+				var value interface{}
+				if nested {
+					value = microdataItem(c)
+				} else {
+					value = microdataValue(c)
+				}
+				for _, prop := range props {
+					addProperty(item, prop, value)
+				}
+			}
+
+			// Properties inside a nested item belong to that item
+			if !nested {
+				collect(c)
+			}
+		}
+	}
+	collect(scope)
+	return item
+}
+
+func addProperty(item map[string]interface{}, name string, value interface{}) {
+	switch existing := item[name].(type) {
+	case nil:
+		item[name] = value
+	case []interface{}:
+		item[name] = append(existing, value)
+	default:
+		item[name] = []interface{}{existing, value}
+	}
+}
+
+// microdataValue returns an element's property value per the microdata spec
+func microdataValue(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.Meta:
+		return attr(n, "content")
+	case atom.Img, atom.Audio, atom.Video, atom.Source, atom.Embed, atom.Iframe:
+		return attr(n, "src")
+	case atom.A, atom.Link, atom.Area:
+		return attr(n, "href")
+	case atom.Object:
+		return attr(n, "data")
+	case atom.Data, atom.Meter:
+		return attr(n, "value")
+	case atom.Time:
+		if dt := attr(n, "datetime"); dt != "" {
+			return dt
+		}
+	}
+	if content := attr(n, "content"); content != "" {
+		return content
+	}
+	return textContent(n)
+}
+
+// blockElements start a new line in textContent, so lists and paragraphs
+// inside a property can be split into steps
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Li: true, atom.Br: true, atom.Div: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ol: true, atom.Ul: true, atom.Tr: true,
+}
+
+// textContent returns the text of n, with a line break around block elements
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			if n.DataAtom == atom.Script || n.DataAtom == atom.Style {
+				return
+			}
+		}
+		block := n.Type == html.ElementNode && blockElements[n.DataAtom]
+		if block {
+			b.WriteString("\n")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+		if block {
+			b.WriteString("\n")
+		}
+	}
+	visit(n)
+	return b.String()
+}
@@ -0,0 +1,274 @@
+package recipeimport
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	isoDurationPattern = regexp.MustCompile(`(?i)^P(?:(\d+(?:\.\d+)?)W)?(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+	numberPattern      = regexp.MustCompile(`\d+(?:[.,]\d+)?`)
+	tagPattern         = regexp.MustCompile(`<[^>]*>`)
+	spacePattern       = regexp.MustCompile(`[ \t\r\f\v\x{00a0}]+`)
+	stepNumberPattern  = regexp.MustCompile(`(?i)^(?:step\s*)?\d+[.):]\s+`)
+	lineBreakPattern   = regexp.MustCompile(`(?i)<\s*(br|/p|/li|/div)\s*/?>`)
+	timePartPattern    = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(days?|d|hours?|hrs?|h|minutes?|mins?|m|seconds?|secs?|s)`)
+	timeRestPattern    = regexp.MustCompile(`(?i)^(?:\s|,|and)*$`)
+)
+
+// ParseDuration converts an ISO-8601 duration such as "PT1H30M" to whole
+// minutes, rounding seconds up. A bare number is read as minutes.
+func ParseDuration(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+		return n, nil
+	}
+
+	m := isoDurationPattern.FindStringSubmatch(value)
+	if m == nil || strings.EqualFold(value, "P") || strings.HasSuffix(strings.ToUpper(value), "T") {
+		return 0, fmt.Errorf("could not parse duration %q", value)
+	}
+
+	part := func(s string) float64 {
+		if s == "" {
+			return 0
+		}
+		f, _ := strconv.ParseFloat(s, 64)
+		return f
+	}
+	seconds := part(m[1])*7*24*3600 + part(m[2])*24*3600 + part(m[3])*3600 + part(m[4])*60 + part(m[5])
+	return int(math.Ceil(seconds / 60)), nil
+}
+
+// ParseTime reads a recipe time as whole minutes. Besides what
+// ParseDuration reads, it accepts times written out, such as "45 min",
+// "1 hour 30 minutes" or "1h30m".
+func ParseTime(value string) (int, error) {
+	if m, err := ParseDuration(value); err == nil {
+		return m, nil
+	}
+	parts := timePartPattern.FindAllStringSubmatch(value, -1)
+	if len(parts) == 0 || !timeRestPattern.MatchString(timePartPattern.ReplaceAllString(value, "")) {
+		return 0, fmt.Errorf("could not parse duration %q", value)
+	}
+	seconds := 0.0
+	for _, part := range parts {
+		n, _ := strconv.ParseFloat(strings.Replace(part[1], ",", ".", 1), 64)
+		switch unit := strings.ToLower(part[2]); {
+		case strings.HasPrefix(unit, "d"):
+			seconds += n * 24 * 3600
+		case strings.HasPrefix(unit, "h"):
+			seconds += n * 3600
+		case strings.HasPrefix(unit, "m"):
+			seconds += n * 60
+		default:
+			seconds += n
+		}
+	}
+	return int(math.Ceil(seconds / 60)), nil
+}
+
+// parseYield reads a number of servings from recipeYield, which may be a
+// number, a string such as "4-6 servings", or a list of either
+func parseYield(v interface{}) (int, bool) {
+	for _, s := range stringsOf(v) {
+		n, ok := leadingNumber(s)
+		if ok && n >= 1 && n <= 100 {
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// leadingNumber returns the first number in s, e.g. 240 for "240 kcal"
+func leadingNumber(s string) (float64, bool) {
+	match := numberPattern.FindString(s)
+	if match == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(strings.Replace(match, ",", ".", 1), 64)
+	return n, err == nil
+}
+
+// cleanText decodes entities, strips markup and collapses whitespace
+func cleanText(s string) string {
+	s = html.UnescapeString(tagPattern.ReplaceAllString(s, " "))
+	return strings.TrimSpace(strings.Join(strings.Fields(s), " "))
+}
+
+// splitLines splits text into trimmed, non-empty lines, treating <br>, <p>
+// and <li> markup as line breaks
+func splitLines(s string) []string {
+	s = lineBreakPattern.ReplaceAllString(s, "\n")
+	s = html.UnescapeString(tagPattern.ReplaceAllString(s, " "))
+
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(spacePattern.ReplaceAllString(line, " "))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// instructionSteps flattens recipeInstructions, which may be text, a list of
+// strings, HowToStep objects or HowToSection groups of steps
+func instructionSteps(v interface{}) []string {
+	steps := []string{}
+	var add func(v interface{}, depth int)
+	add = func(v interface{}, depth int) {
+		if depth > maxSearchDepth {
+			return
+		}
+		switch v := v.(type) {
+		case string:
+			for _, line := range splitLines(v) {
+				if line = stepNumberPattern.ReplaceAllString(line, ""); line != "" {
+					steps = append(steps, line)
+				}
+			}
+		case []interface{}:
+			for _, item := range v {
+				add(item, depth+1)
+			}
+		case map[string]interface{}:
+			if items, ok := v["itemListElement"]; ok {
+				add(items, depth+1)
+				return
+			}
+			text := firstString(v["text"])
+			if text == "" {
+				text = firstString(v["name"])
+			}
+			add(text, depth+1)
+		}
+	}
+	add(v, 0)
+	return steps
+}
+
+// keywords reads schema.org keywords, given as a comma-separated string or
+// a list, into de-duplicated tags
+func keywords(v interface{}) []string {
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, s := range stringsOf(v) {
+		for _, tag := range strings.Split(s, ",") {
+			tag = cleanText(tag)
+			key := strings.ToLower(tag)
+			if tag == "" || len(tag) > 100 || seen[key] {
+				continue
+			}
+			seen[key] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// categoryKeywords maps words used in recipeCategory to our categories
+var categoryKeywords = []struct {
+	keyword  string
+	category string
+}{
+	{"breakfast", "Breakfast"},
+	{"brunch", "Breakfast"},
+	{"lunch", "Lunch"},
+	{"dinner", "Dinner"},
+	{"main", "Dinner"},
+	{"entree", "Dinner"},
+	{"entrée", "Dinner"},
+	{"supper", "Dinner"},
+	{"snack", "Snacks"},
+	{"appetizer", "Snacks"},
+	{"starter", "Snacks"},
+	{"dessert", "Desserts"},
+	{"baking", "Desserts"},
+	{"cake", "Desserts"},
+	{"cookie", "Desserts"},
+}
+
+// MatchCategory maps a category such as "Main course" or "desserts" onto one
+// of models.RecipeCategories
+func MatchCategory(value string) (string, bool) {
+	value = strings.ToLower(value)
+	best, bestIndex := "", -1
+	for _, k := range categoryKeywords {
+		if i := strings.Index(value, k.keyword); i >= 0 && (bestIndex < 0 || i < bestIndex) {
+			best, bestIndex = k.category, i
+		}
+	}
+	return best, bestIndex >= 0
+}
+
+// imageURL reads schema.org image, which may be a URL, an ImageObject or a
+// list of either
+func imageURL(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []interface{}:
+		for _, item := range v {
+			if u := imageURL(item); u != "" {
+				return u
+			}
+		}
+	case map[string]interface{}:
+		if u := firstString(v["url"]); u != "" {
+			return strings.TrimSpace(u)
+		}
+		return strings.TrimSpace(firstString(v["contentUrl"]))
+	}
+	return ""
+}
+
+// firstString returns v as a string, or the first string in a list
+func firstString(v interface{}) string {
+	if values := stringsOf(v); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// stringsOf returns the string values of v, which may be a string, a
+// number, or a list of either. Objects are skipped.
+func stringsOf(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			switch item := item.(type) {
+			case string:
+				values = append(values, item)
+			case float64:
+				values = append(values, strconv.FormatFloat(item, 'f', -1, 64))
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// firstMap returns v as an object, or the first object in a list
+func firstMap(v interface{}) (map[string]interface{}, bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return v, true
+	case []interface{}:
+		for _, item := range v {
+			if m, ok := item.(map[string]interface{}); ok {
+				return m, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,377 @@
+package recipeimport
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/meal-planner/backend/internal/models"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{in: "PT20M", want: 20},
+		{in: "PT1H30M", want: 90},
+		{in: "P0DT0H45M", want: 45},
+		{in: "PT1.5H", want: 90},
+		{in: "PT90S", want: 2},
+		{in: "P1D", want: 1440},
+		{in: "pt10m", want: 10},
+		{in: "15", want: 15},
+		{in: "PT", wantErr: true},
+		{in: "P", wantErr: true},
+		{in: "20 minutes", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDuration(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDuration(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDuration(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{in: "45", want: 45},
+		{in: "PT1H", want: 60},
+		{in: "20 minutes", want: 20},
+		{in: "1 hour 30 minutes", want: 90},
+		{in: "1h30m", want: 90},
+		{in: "1.5 hours", want: 90},
+		{in: "2 hrs, 5 mins", want: 125},
+		{in: "90 seconds", want: 2},
+		{in: "about an hour", wantErr: true},
+		{in: "2 mangoes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTime(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTime(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTime(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseYield(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     interface{}
+		want   int
+		wantOK bool
+	}{
+		{name: "number", in: float64(4), want: 4, wantOK: true},
+		{name: "text", in: "6 servings", want: 6, wantOK: true},
+		{name: "range", in: "4-6", want: 4, wantOK: true},
+		{name: "list", in: []interface{}{"8", "8 cookies"}, want: 8, wantOK: true},
+		{name: "no number", in: "one loaf", wantOK: false},
+		{name: "too many", in: "500", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseYield(tt.in)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseYield(%v) = %d, %v, want %d, %v", tt.in, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+const jsonLDPage = `<!DOCTYPE html>
+<html><head>
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "WebSite", "name": "Blog"}</script>
+<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@graph": [
+    {"@type": "WebPage", "name": "Page"},
+    {
+      "@type": ["Recipe", "NewsArticle"],
+      "name": "Weeknight Chicken &amp; Rice",
+      "description": "<p>An easy one-pot dinner.</p>",
+      "image": [{"@type": "ImageObject", "url": "https://example.com/chicken.jpg"}],
+      "url": "https://example.com/chicken-rice",
+      "prepTime": "PT10M",
+      "totalTime": "PT45M",
+      "recipeYield": ["4", "4 servings"],
+      "recipeCategory": "Main Course",
+      "recipeCuisine": ["Mexican"],
+      "keywords": "easy, one-pot, Easy",
+      "recipeIngredient": ["2 cups rice", " 1 lb chicken  thighs ", ""],
+      "recipeInstructions": [
+        {"@type": "HowToSection", "name": "Prep", "itemListElement": [
+          {"@type": "HowToStep", "text": "Rinse the rice."},
+          {"@type": "HowToStep", "text": "Season the chicken."}
+        ]},
+        {"@type": "HowToStep", "text": "Simmer everything for 30 minutes."}
+      ],
+      "nutrition": {"@type": "NutritionInformation", "calories": "520 kcal", "proteinContent": "35 g", "sodiumContent": "640 mg"}
+    }
+  ]
+}
+</script>
+</head><body></body></html>`
+
+func TestParseJSONLD(t *testing.T) {
+	res, err := Parse(strings.NewReader(jsonLDPage))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	d := res.Draft
+	if res.Source != SourceJSONLD {
+		t.Errorf("Source = %q, want %q", res.Source, SourceJSONLD)
+	}
+	if d.Name != "Weeknight Chicken & Rice" {
+		t.Errorf("Name = %q", d.Name)
+	}
+	if d.Description != "An easy one-pot dinner." {
+		t.Errorf("Description = %q", d.Description)
+	}
+	if d.ImageURL != "https://example.com/chicken.jpg" || d.SourceURL != "https://example.com/chicken-rice" {
+		t.Errorf("ImageURL, SourceURL = %q, %q", d.ImageURL, d.SourceURL)
+	}
+	if d.PrepTime != 10 || d.CookTime != 35 {
+		t.Errorf("PrepTime, CookTime = %d, %d, want 10, 35", d.PrepTime, d.CookTime)
+	}
+	if d.Servings != 4 || d.Category != "Dinner" || d.Cuisine != "Mexican" {
+		t.Errorf("Servings, Category, Cuisine = %d, %q, %q", d.Servings, d.Category, d.Cuisine)
+	}
+	if !reflect.DeepEqual(d.Tags, []string{"easy", "one-pot"}) {
+		t.Errorf("Tags = %v", d.Tags)
+	}
+	wantIngredient := models.RecipeIngredient{Name: "chicken thighs", Quantity: 1, Unit: "lb"}
+	if len(d.Ingredients) != 2 || !reflect.DeepEqual(d.Ingredients[1], wantIngredient) {
+		t.Errorf("Ingredients = %+v", d.Ingredients)
+	}
+	wantSteps := []string{"Rinse the rice.", "Season the chicken.", "Simmer everything for 30 minutes."}
+	if !reflect.DeepEqual(d.Instructions, wantSteps) {
+		t.Errorf("Instructions = %v, want %v", d.Instructions, wantSteps)
+	}
+	if d.Nutrition.Calories != 520 || d.Nutrition.Protein != 35 || d.Nutrition.Sodium != 640 {
+		t.Errorf("Nutrition = %+v", d.Nutrition)
+	}
+	if len(res.Warnings) != 0 {
+		t.Errorf("Warnings = %v, want none", res.Warnings)
+	}
+}
+
+const microdataPage = `<html><body>
+<div itemscope itemtype="http://schema.org/Recipe">
+  <h1 itemprop="name">Grandma's Pancakes</h1>
+  <img itemprop="image" src="https://example.com/pancakes.jpg">
+  <meta itemprop="prepTime" content="PT5M">
+  <time itemprop="cookTime" datetime="PT15M">15 minutes</time>
+  <span itemprop="recipeYield">Makes 8 pancakes</span>
+  <span itemprop="recipeCategory">Breakfast</span>
+  <ul>
+    <li itemprop="recipeIngredient">1 cup flour</li>
+    <li itemprop="recipeIngredient">1 egg</li>
+  </ul>
+  <div itemprop="nutrition" itemscope itemtype="http://schema.org/NutritionInformation">
+    <span itemprop="calories">210 calories</span>
+    <span itemprop="name">should not leak into the recipe</span>
+  </div>
+  <div itemprop="recipeInstructions">
+    <ol><li>1. Whisk everything.</li><li>Step 2: Fry until golden.</li></ol>
+  </div>
+</div>
+</body></html>`
+
+func TestParseMicrodata(t *testing.T) {
+	res, err := Parse(strings.NewReader(microdataPage))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	d := res.Draft
+	if res.Source != SourceMicrodata {
+		t.Errorf("Source = %q, want %q", res.Source, SourceMicrodata)
+	}
+	if d.Name != "Grandma's Pancakes" || d.ImageURL != "https://example.com/pancakes.jpg" {
+		t.Errorf("Name, ImageURL = %q, %q", d.Name, d.ImageURL)
+	}
+	if d.PrepTime != 5 || d.CookTime != 15 || d.Servings != 8 || d.Category != "Breakfast" {
+		t.Errorf("PrepTime, CookTime, Servings, Category = %d, %d, %d, %q", d.PrepTime, d.CookTime, d.Servings, d.Category)
+	}
+	if len(d.Ingredients) != 2 || d.Ingredients[0].Name != "flour" || d.Ingredients[0].Unit != "cup" {
+		t.Errorf("Ingredients = %+v", d.Ingredients)
+	}
+	wantSteps := []string{"Whisk everything.", "Fry until golden."}
+	if !reflect.DeepEqual(d.Instructions, wantSteps) {
+		t.Errorf("Instructions = %v, want %v", d.Instructions, wantSteps)
+	}
+	if d.Nutrition.Calories != 210 {
+		t.Errorf("Calories = %v, want 210", d.Nutrition.Calories)
+	}
+}
+
+func TestParseWarnings(t *testing.T) {
+	page := `<script type="application/ld+json">{"@type": "Recipe", "name": "Toast",
+		"totalTime": "about ten minutes", "recipeYield": "one loaf", "recipeCategory": "Bread"}</script>`
+
+	res, err := Parse(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []string{
+		`recipeCategory: "Bread" does not match a known category`,
+		`totalTime: could not parse duration "about ten minutes"`,
+		`prepTime, cookTime: not found`,
+		`recipeYield: could not read a number of servings from "one loaf"`,
+		`recipeIngredient: not found`,
+		`recipeInstructions: not found`,
+	}
+	if !reflect.DeepEqual(res.Warnings, want) {
+		t.Errorf("Warnings =\n%q\nwant\n%q", res.Warnings, want)
+	}
+}
+
+func TestParseNoRecipe(t *testing.T) {
+	page := `<html><script type="application/ld+json">{not json}</script><p>Hello</p></html>`
+	if _, err := Parse(strings.NewReader(page)); err != ErrNoRecipe {
+		t.Errorf("Parse() error = %v, want ErrNoRecipe", err)
+	}
+}
+
+func readRows(t *testing.T, read func(io.Reader, func(Row) error) error, src string) []Row {
+	t.Helper()
+	var rows []Row
+	err := read(strings.NewReader(src), func(row Row) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("read error = %v", err)
+	}
+	return rows
+}
+
+func TestReadCSV(t *testing.T) {
+	src := "Name,Course,Prep Time,cook_time,Servings,Ingredients,Instructions,Tags,Calories\n" +
+		"Pancakes,Brunch,10,1 hour 5 min,4,\"2 cups flour\n2 eggs\",\"1. Whisk.\n2. Fry.\",\"quick, sweet\",350\n" +
+		",,,,,,,,\n" +
+		"Soup,Soups,soon,,four,salt; pepper,Simmer; Serve,,lots\n"
+
+	rows := readRows(t, ReadCSV, src)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+
+	first := rows[0]
+	if first.Line != 2 || len(first.Errors) != 0 {
+		t.Errorf("row 1: line %d, errors %v", first.Line, first.Errors)
+	}
+	d := first.Draft
+	if d.Name != "Pancakes" || d.Category != "Breakfast" || d.PrepTime != 10 || d.CookTime != 65 || d.Servings != 4 {
+		t.Errorf("row 1 draft = %+v", d)
+	}
+	if len(d.Ingredients) != 2 || d.Ingredients[0].Name != "flour" || d.Ingredients[0].Quantity != 2 {
+		t.Errorf("row 1 ingredients = %+v", d.Ingredients)
+	}
+	if want := []string{"Whisk.", "Fry."}; !reflect.DeepEqual(d.Instructions, want) {
+		t.Errorf("row 1 instructions = %q, want %q", d.Instructions, want)
+	}
+	if want := []string{"quick", "sweet"}; !reflect.DeepEqual(d.Tags, want) {
+		t.Errorf("row 1 tags = %q, want %q", d.Tags, want)
+	}
+	if d.Nutrition.Calories != 350 {
+		t.Errorf("row 1 calories = %v, want 350", d.Nutrition.Calories)
+	}
+
+	second := rows[1]
+	if second.Line != 6 {
+		t.Errorf("row 2 line = %d, want 6", second.Line)
+	}
+	wantErrors := []FieldError{
+		{Field: "prepTime", Message: "could not read minutes from soon"},
+		{Field: "servings", Message: "must be a whole number"},
+		{Field: "calories", Message: "must be a number"},
+	}
+	if !reflect.DeepEqual(second.Errors, wantErrors) {
+		t.Errorf("row 2 errors = %+v, want %+v", second.Errors, wantErrors)
+	}
+	if second.Draft.Category != "Soups" || len(second.Draft.Ingredients) != 2 || len(second.Draft.Instructions) != 2 {
+		t.Errorf("row 2 draft = %+v", second.Draft)
+	}
+}
+
+func TestReadCSVHeader(t *testing.T) {
+	tests := []struct {
+		src     string
+		wantErr string
+	}{
+		{"", "file is empty"},
+		{"Category,Servings\nDinner,4\n", "a name column is required"},
+		{"Name,Rating\nSoup,5\n", `unknown column "Rating"`},
+	}
+
+	for _, tt := range tests {
+		err := ReadCSV(strings.NewReader(tt.src), func(Row) error { return nil })
+		if err == nil || err.Error() != tt.wantErr {
+			t.Errorf("ReadCSV(%q) error = %v, want %q", tt.src, err, tt.wantErr)
+		}
+	}
+}
+
+func TestReadJSONL(t *testing.T) {
+	src := `{"name": "Omelette", "category": "Breakfast", "prepTime": 5, "cookTime": "PT10M", "servings": 2,` +
+		` "ingredients": ["3 eggs", {"name": "butter", "quantity": 1, "unit": "tbsp"}],` +
+		` "instructions": ["Beat the eggs.", "Cook in butter."], "tags": ["eggs"], "nutrition": {"calories": 280}}
+
+{"name": 7, "rating": 5, "nutrition": {"vitamins": 1}}
+not json
+`
+	rows := readRows(t, ReadJSONL, src)
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(rows))
+	}
+
+	d := rows[0].Draft
+	if len(rows[0].Errors) != 0 || d.Name != "Omelette" || d.PrepTime != 5 || d.CookTime != 10 || d.Servings != 2 {
+		t.Errorf("row 1 = %+v, errors %v", d, rows[0].Errors)
+	}
+	wantIngredients := []models.RecipeIngredient{
+		{Name: "butter", Quantity: 1, Unit: "tbsp"},
+		{Name: "eggs", Quantity: 3},
+	}
+	if !reflect.DeepEqual(d.Ingredients, wantIngredients) {
+		t.Errorf("row 1 ingredients = %+v, want %+v", d.Ingredients, wantIngredients)
+	}
+	if d.Nutrition.Calories != 280 {
+		t.Errorf("row 1 calories = %v, want 280", d.Nutrition.Calories)
+	}
+
+	wantErrors := []FieldError{
+		{Field: "name", Message: "must be text"},
+		{Field: "nutrition.vitamins", Message: "unknown field"},
+		{Field: "rating", Message: "unknown field"},
+	}
+	if rows[1].Line != 3 || !reflect.DeepEqual(rows[1].Errors, wantErrors) {
+		t.Errorf("row 2 line %d errors = %+v, want %+v", rows[1].Line, rows[1].Errors, wantErrors)
+	}
+	if rows[2].Line != 4 || len(rows[2].Errors) != 1 || !strings.HasPrefix(rows[2].Errors[0].Message, "invalid JSON") {
+		t.Errorf("row 3 line %d errors = %+v", rows[2].Line, rows[2].Errors)
+	}
+}
@@ -0,0 +1,373 @@
+package recipeimport
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/meal-planner/backend/internal/models"
+)
+
+// maxRowLine limits the length of one line of a JSON-lines file
+const maxRowLine = 1 << 20
+
+// Row is one recipe read from a bulk import file. Errors lists the fields
+// that could not be read; the draft holds the rest.
+type Row struct {
+	// Line is where the row starts in its file, counting from 1
+	Line   int
+	Draft  Draft
+	Errors []FieldError
+}
+
+// FieldError is a value of a row that could not be read
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (r *Row) fail(field, format string, args ...interface{}) {
+	r.Errors = append(r.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// rowFields maps column names and JSON keys, compared by normalizeColumn,
+// to the draft field they fill
+var rowFields = map[string]string{
+	"name":          "name",
+	"title":         "name",
+	"description":   "description",
+	"category":      "category",
+	"course":        "category",
+	"cuisine":       "cuisine",
+	"preptime":      "prepTime",
+	"cooktime":      "cookTime",
+	"servings":      "servings",
+	"yield":         "servings",
+	"difficulty":    "difficulty",
+	"ingredients":   "ingredients",
+	"instructions":  "instructions",
+	"steps":         "instructions",
+	"directions":    "instructions",
+	"tags":          "tags",
+	"keywords":      "tags",
+	"imageurl":      "imageUrl",
+	"image":         "imageUrl",
+	"sourceurl":     "sourceUrl",
+	"source":        "sourceUrl",
+	"url":           "sourceUrl",
+	"nutrition":     "nutrition",
+	"calories":      "calories",
+	"protein":       "protein",
+	"carbohydrates": "carbohydrates",
+	"carbs":         "carbohydrates",
+	"fat":           "fat",
+	"fiber":         "fiber",
+	"sugar":         "sugar",
+	"sodium":        "sodium",
+}
+
+// normalizeColumn folds "Prep Time", "prep_time" and "prepTime" together
+func normalizeColumn(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// ReadCSV reads recipes from a CSV file with a header row, calling fn with
+// each row. Columns are matched by name, e.g. "Name", "Prep Time" or
+// "prep_time". Ingredients and instructions go one per line within their
+// cell, or separated by semicolons; tags are separated by commas. Times are
+// minutes or text like "1 hour 15 minutes".
+func ReadCSV(r io.Reader, fn func(Row) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("file is empty")
+		}
+		return err
+	}
+	fields := make([]string, len(header))
+	hasName := false
+	for i, column := range header {
+		if i == 0 {
+			column = strings.TrimPrefix(column, "\ufeff")
+		}
+		field, ok := rowFields[normalizeColumn(column)]
+		if !ok && strings.TrimSpace(column) != "" {
+			return fmt.Errorf("unknown column %q", column)
+		}
+		fields[i] = field
+		hasName = hasName || field == "name"
+	}
+	if !hasName {
+		return errors.New("a name column is required")
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := Row{Line: line}
+		blank := true
+		for i, value := range record {
+			if i >= len(fields) || fields[i] == "" {
+				if strings.TrimSpace(value) != "" {
+					row.fail("", "column %d has no header", i+1)
+				}
+				continue
+			}
+			if strings.TrimSpace(value) != "" {
+				blank = false
+				setRowField(&row, fields[i], value)
+			}
+		}
+		if blank {
+			continue
+		}
+		if err := fn(finishRow(row)); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadJSONL reads recipes from a JSON-lines file, one object per line,
+// calling fn with each row. Objects use the fields of a create-recipe
+// request; ingredients may be lines of text or ingredient objects.
+func ReadJSONL(r io.Reader, fn func(Row) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxRowLine)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if text == "" {
+			continue
+		}
+
+		row := Row{Line: line}
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &obj); err != nil {
+			row.fail("", "invalid JSON: %v", err)
+		}
+		for _, key := range sortedKeys(obj) {
+			field, ok := rowFields[normalizeColumn(key)]
+			if !ok {
+				row.fail(key, "unknown field")
+				continue
+			}
+			if obj[key] != nil {
+				setRowField(&row, field, obj[key])
+			}
+		}
+		if err := fn(finishRow(row)); err != nil {
+			return err
+		}
+	}
+	if errors.Is(scanner.Err(), bufio.ErrTooLong) {
+		return fmt.Errorf("line %d is longer than %d KB", line+1, maxRowLine>>10)
+	}
+	return scanner.Err()
+}
+
+// setRowField reads one value into the row's draft. CSV values are strings;
+// JSON values may also be numbers, lists or objects.
+func setRowField(row *Row, field string, value interface{}) {
+	d := &row.Draft
+	text := func() (string, bool) {
+		s, ok := value.(string)
+		if !ok {
+			row.fail(field, "must be text")
+		}
+		return strings.TrimSpace(s), ok
+	}
+
+	switch field {
+	case "name":
+		d.Name, _ = text()
+	case "description":
+		d.Description, _ = text()
+	case "category":
+		// Unmatched categories are kept so validation can name them
+		if s, ok := text(); ok {
+			d.Category = s
+			if category, ok := MatchCategory(s); ok {
+				d.Category = category
+			}
+		}
+	case "cuisine":
+		d.Cuisine, _ = text()
+	case "difficulty":
+		d.Difficulty, _ = text()
+	case "imageUrl":
+		d.ImageURL, _ = text()
+	case "sourceUrl":
+		d.SourceURL, _ = text()
+	case "prepTime", "cookTime":
+		minutes, ok := rowMinutes(value)
+		if !ok {
+			row.fail(field, "could not read minutes from %v", value)
+		} else if field == "prepTime" {
+			d.PrepTime = minutes
+		} else {
+			d.CookTime = minutes
+		}
+	case "servings":
+		n, ok := leadingNumber(firstString(value))
+		if !ok || n != float64(int(n)) {
+			row.fail(field, "must be a whole number")
+		}
+		d.Servings = int(n)
+	case "ingredients":
+		rowIngredients(row, value)
+	case "instructions":
+		if s, ok := value.(string); ok && !strings.Contains(s, "\n") && strings.Contains(s, ";") {
+			value = strings.ReplaceAll(s, ";", "\n")
+		}
+		d.Instructions = instructionSteps(value)
+	case "tags":
+		d.Tags = keywords(value)
+	case "nutrition":
+		nutrition, ok := value.(map[string]interface{})
+		if !ok {
+			row.fail(field, "must be an object")
+			return
+		}
+		for _, key := range sortedKeys(nutrition) {
+			if f, ok := rowFields[normalizeColumn(key)]; ok && nutritionField(d, f) != nil {
+				setRowField(row, f, nutrition[key])
+			} else {
+				row.fail("nutrition."+key, "unknown field")
+			}
+		}
+	default:
+		dest := nutritionField(d, field)
+		n, ok := leadingNumber(firstString(value))
+		if !ok || n < 0 {
+			row.fail(field, "must be a number")
+			return
+		}
+		*dest = n
+	}
+}
+
+// nutritionField returns the draft's nutrition value for a field, or nil
+// when the field is not a nutrient
+func nutritionField(d *Draft, field string) *float64 {
+	switch field {
+	case "calories":
+		return &d.Nutrition.Calories
+	case "protein":
+		return &d.Nutrition.Protein
+	case "carbohydrates":
+		return &d.Nutrition.Carbohydrates
+	case "fat":
+		return &d.Nutrition.Fat
+	case "fiber":
+		return &d.Nutrition.Fiber
+	case "sugar":
+		return &d.Nutrition.Sugar
+	case "sodium":
+		return &d.Nutrition.Sodium
+	}
+	return nil
+}
+
+// rowMinutes reads a time given as a number of minutes or as text
+func rowMinutes(value interface{}) (int, bool) {
+	if n, ok := value.(float64); ok {
+		return int(n), n >= 0 && n == float64(int(n))
+	}
+	s, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+	minutes, err := ParseTime(s)
+	return minutes, err == nil
+}
+
+// rowIngredients reads ingredient lines from text or a list. Objects in a
+// list are read as structured ingredients.
+func rowIngredients(row *Row, value interface{}) {
+	d := &row.Draft
+	var lines []string
+	switch v := value.(type) {
+	case string:
+		lines = splitLines(v)
+		if len(lines) == 1 && strings.Contains(lines[0], ";") {
+			lines = strings.Split(lines[0], ";")
+		}
+	case []interface{}:
+		for i, item := range v {
+			switch item := item.(type) {
+			case string:
+				lines = append(lines, item)
+			case map[string]interface{}:
+				data, _ := json.Marshal(item)
+				var ing models.RecipeIngredient
+				if err := json.Unmarshal(data, &ing); err != nil {
+					row.fail(fmt.Sprintf("ingredients[%d]", i), "invalid ingredient: %v", err)
+					continue
+				}
+				d.Ingredients = append(d.Ingredients, ing)
+			default:
+				row.fail(fmt.Sprintf("ingredients[%d]", i), "must be text or an object")
+			}
+		}
+	default:
+		row.fail("ingredients", "must be text or a list")
+	}
+
+	for _, line := range lines {
+		if line = cleanText(line); line != "" {
+			d.Ingredients = append(d.Ingredients, models.ParseRecipeIngredient(line))
+		}
+	}
+}
+
+// sortedKeys returns the keys of an object in order, so errors are
+// reported in a stable order
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// finishRow fills the lists a row left empty, so drafts match those of
+// page imports
+func finishRow(row Row) Row {
+	if row.Draft.Ingredients == nil {
+		row.Draft.Ingredients = []models.RecipeIngredient{}
+	}
+	if row.Draft.Instructions == nil {
+		row.Draft.Instructions = []string{}
+	}
+	if row.Draft.Tags == nil {
+		row.Draft.Tags = []string{}
+	}
+	return row
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CollectionFilter selects the collections listed for a user: their own,
+// and the household collections of their households
+type CollectionFilter struct {
+	UserID       string
+	HouseholdIDs []string
+}
+
+// ErrCollectionFull is returned by AddRecipe when the collection already
+// holds the most recipes allowed
+var ErrCollectionFull = errors.New("collection is full")
+
+type CollectionRepository interface {
+	Create(collection *models.Collection) error
+	FindByID(id string) (*models.Collection, error)
+	FindByShareToken(token string) (*models.Collection, error)
+	List(filter CollectionFilter, page *pagination.Params) ([]models.Collection, *pagination.Pagination, error)
+	Update(collection *models.Collection) error
+	Delete(id string) error
+
+	// AddRecipe appends a recipe to the end of a collection, or returns
+	// ErrCollectionFull when it already holds maxRecipes. Adding a recipe
+	// already in the collection changes nothing. AddRecipe and RemoveRecipe
+	// keep the collection's recipe count in sync.
+	AddRecipe(collectionID, recipeID string, maxRecipes int) error
+	RemoveRecipe(collectionID, recipeID string) error
+	// ReorderRecipes sets the order of a collection's recipes. It returns
+	// false without saving unless recipeIDs lists every recipe in the
+	// collection exactly once.
+	ReorderRecipes(collectionID string, recipeIDs []string) (bool, error)
+	// ListRecipes lists a collection's recipes in order, skipping recipes
+	// that were deleted or that the filter leaves out, such as recipes not
+	// visible to the viewer
+	ListRecipes(collectionID string, filter RecipeFilter, page *pagination.Params) ([]models.CollectionRecipe, *pagination.Pagination, error)
+}
+
+type collectionRepository struct {
+	db *gorm.DB
+}
+
+func NewCollectionRepository(db *gorm.DB) CollectionRepository {
+	return &collectionRepository{db: db}
+}
+
+func (r *collectionRepository) Create(collection *models.Collection) error {
+	return r.db.Create(collection).Error
+}
+
+func (r *collectionRepository) FindByID(id string) (*models.Collection, error) {
+	return r.find(r.db.Where("id = ?", id))
+}
+
+func (r *collectionRepository) FindByShareToken(token string) (*models.Collection, error) {
+	return r.find(r.db.Where("share_token = ?", token))
+}
+
+func (r *collectionRepository) find(query *gorm.DB) (*models.Collection, error) {
+	var collection models.Collection
+	err := query.First(&collection).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &collection, nil
+}
+
+func (r *collectionRepository) List(filter CollectionFilter, page *pagination.Params) ([]models.Collection, *pagination.Pagination, error) {
+	query := r.db.Model(&models.Collection{})
+	if len(filter.HouseholdIDs) > 0 {
+		query = query.Where("owner_id = ? OR (visibility = ? AND household_id IN ?)",
+			filter.UserID, models.CollectionHousehold, filter.HouseholdIDs)
+	} else {
+		query = query.Where("owner_id = ?", filter.UserID)
+	}
+
+	var collections []models.Collection
+	result, err := pagination.Find(query, page, &collections)
+	return collections, result, err
+}
+
+// Update saves the editable fields of a collection, leaving the recipe
+// count to AddRecipe and RemoveRecipe
+func (r *collectionRepository) Update(collection *models.Collection) error {
+	return r.db.Omit("recipe_count").Save(collection).Error
+}
+
+func (r *collectionRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", id).Delete(&models.CollectionRecipe{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Collection{}, "id = ?", id).Error
+	})
+}
+
+func (r *collectionRepository) AddRecipe(collectionID, recipeID string, maxRecipes int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockCollection(tx, collectionID); err != nil {
+			return err
+		}
+
+		// The size is checked under the collection lock, so concurrent adds
+		// cannot together go over the limit
+		var count int64
+		err := tx.Model(&models.CollectionRecipe{}).
+			Where("collection_id = ?", collectionID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count >= int64(maxRecipes) {
+			var present int64
+			err := tx.Model(&models.CollectionRecipe{}).
+				Where("collection_id = ? AND recipe_id = ?", collectionID, recipeID).
+				Count(&present).Error
+			if err != nil || present > 0 {
+				return err
+			}
+			return ErrCollectionFull
+		}
+
+		// Positions are assigned under the collection lock, so concurrent
+		// adds cannot take the same one
+		var last *int
+		err = tx.Model(&models.CollectionRecipe{}).
+			Where("collection_id = ?", collectionID).
+			Select("MAX(position)").
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+		position := 0
+		if last != nil {
+			position = *last + 1
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit("Recipe").
+			Create(&models.CollectionRecipe{CollectionID: collectionID, RecipeID: recipeID, Position: position})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&models.Collection{}).Where("id = ?", collectionID).
+			UpdateColumn("recipe_count", gorm.Expr("recipe_count + 1")).Error
+	})
+}
+
+func (r *collectionRepository) RemoveRecipe(collectionID, recipeID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("collection_id = ? AND recipe_id = ?", collectionID, recipeID).
+			Delete(&models.CollectionRecipe{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&models.Collection{}).Where("id = ?", collectionID).
+			UpdateColumn("recipe_count", gorm.Expr("GREATEST(recipe_count - 1, 0)")).Error
+	})
+}
+
+func (r *collectionRepository) ReorderRecipes(collectionID string, recipeIDs []string) (bool, error) {
+	saved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockCollection(tx, collectionID); err != nil {
+			return err
+		}
+
+		var current []string
+		err := tx.Model(&models.CollectionRecipe{}).
+			Where("collection_id = ?", collectionID).
+			Pluck("recipe_id", &current).Error
+		if err != nil {
+			return err
+		}
+		if !sameRecipeIDs(current, recipeIDs) {
+			return nil
+		}
+
+		for position, recipeID := range recipeIDs {
+			err := tx.Model(&models.CollectionRecipe{}).
+				Where("collection_id = ? AND recipe_id = ?", collectionID, recipeID).
+				UpdateColumn("position", position).Error
+			if err != nil {
+				return err
+			}
+		}
+		saved = true
+		return nil
+	})
+	return saved, err
+}
+
+// sameRecipeIDs reports whether ordered lists every ID of current exactly once
+func sameRecipeIDs(current, ordered []string) bool {
+	if len(current) != len(ordered) {
+		return false
+	}
+	remaining := make(map[string]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+	for _, id := range ordered {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
+
+// lockCollection takes a row lock on the collection so its recipes are
+// added and reordered by one transaction at a time
+func lockCollection(tx *gorm.DB, collectionID string) error {
+	var id string
+	return tx.Model(&models.Collection{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", collectionID).
+		Select("id").
+		Scan(&id).Error
+}
+
+func (r *collectionRepository) ListRecipes(collectionID string, filter RecipeFilter, page *pagination.Params) ([]models.CollectionRecipe, *pagination.Pagination, error) {
+	query := r.db.Model(&models.CollectionRecipe{}).
+		Joins("JOIN recipes ON recipes.id = collection_recipes.recipe_id AND recipes.deleted_at IS NULL").
+		Where("collection_recipes.collection_id = ?", collectionID)
+	query = applyRecipeFilter(query, filter)
+
+	var entries []models.CollectionRecipe
+	result, err := pagination.Find(query, page, &entries)
+	if err != nil || len(entries) == 0 {
+		return entries, result, err
+	}
+
+	// Load the page's recipes in one query
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.RecipeID
+	}
+	var recipes []models.Recipe
+	if err := r.db.Where("id IN ?", ids).Find(&recipes).Error; err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[string]*models.Recipe, len(recipes))
+	for i := range recipes {
+		byID[recipes[i].ID] = &recipes[i]
+	}
+	for i := range entries {
+		entries[i].Recipe = byID[entries[i].RecipeID]
+	}
+	return entries, result, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/meal-planner/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type EaterProfileRepository interface {
+	Create(profile *models.EaterProfile) error
+	FindByID(householdID, id string) (*models.EaterProfile, error)
+	FindByHousehold(householdID string) ([]models.EaterProfile, error)
+	FindByIDs(householdID string, ids []string) ([]models.EaterProfile, error)
+	Update(profile *models.EaterProfile) error
+	Delete(householdID, id string) error
+}
+
+type eaterProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewEaterProfileRepository(db *gorm.DB) EaterProfileRepository {
+	return &eaterProfileRepository{db: db}
+}
+
+func (r *eaterProfileRepository) Create(profile *models.EaterProfile) error {
+	return r.db.Create(profile).Error
+}
+
+func (r *eaterProfileRepository) FindByID(householdID, id string) (*models.EaterProfile, error) {
+	var profile models.EaterProfile
+	err := r.db.Where("household_id = ? AND id = ?", householdID, id).First(&profile).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *eaterProfileRepository) FindByHousehold(householdID string) ([]models.EaterProfile, error) {
+	var profiles []models.EaterProfile
+	err := r.db.Where("household_id = ?", householdID).Order("created_at").Find(&profiles).Error
+	return profiles, err
+}
+
+func (r *eaterProfileRepository) FindByIDs(householdID string, ids []string) ([]models.EaterProfile, error) {
+	var profiles []models.EaterProfile
+	err := r.db.Where("household_id = ? AND id IN ?", householdID, ids).Order("created_at").Find(&profiles).Error
+	return profiles, err
+}
+
+func (r *eaterProfileRepository) Update(profile *models.EaterProfile) error {
+	return r.db.Save(profile).Error
+}
+
+func (r *eaterProfileRepository) Delete(householdID, id string) error {
+	return r.db.Delete(&models.EaterProfile{}, "household_id = ? AND id = ?", householdID, id).Error
+}
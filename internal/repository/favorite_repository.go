@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FavoriteRepository interface {
+	// Add favorites a recipe and returns its new favorite count. Adding an
+	// existing favorite changes nothing.
+	Add(userID, recipeID string) (int, error)
+	// Remove unfavorites a recipe and returns its new favorite count.
+	// Removing a missing favorite changes nothing.
+	Remove(userID, recipeID string) (int, error)
+	// ListByUser lists a user's favorites with their recipes, skipping
+	// recipes that were deleted or that the filter leaves out, such as
+	// recipes no longer visible to the user
+	ListByUser(userID string, filter RecipeFilter, page *pagination.Params) ([]models.Favorite, *pagination.Pagination, error)
+	// FavoritedRecipeIDs reports which of the given recipes the user has favorited
+	FavoritedRecipeIDs(userID string, recipeIDs []string) (map[string]bool, error)
+}
+
+type favoriteRepository struct {
+	db *gorm.DB
+}
+
+func NewFavoriteRepository(db *gorm.DB) FavoriteRepository {
+	return &favoriteRepository{db: db}
+}
+
+func (r *favoriteRepository) Add(userID, recipeID string) (int, error) {
+	var count int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.Favorite{UserID: userID, RecipeID: recipeID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			err := tx.Model(&models.Recipe{}).Where("id = ?", recipeID).
+				UpdateColumn("favorite_count", gorm.Expr("favorite_count + 1")).Error
+			if err != nil {
+				return err
+			}
+		}
+		return favoriteCount(tx, recipeID, &count)
+	})
+	return count, err
+}
+
+func (r *favoriteRepository) Remove(userID, recipeID string) (int, error) {
+	var count int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND recipe_id = ?", userID, recipeID).Delete(&models.Favorite{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			err := tx.Model(&models.Recipe{}).Where("id = ?", recipeID).
+				UpdateColumn("favorite_count", gorm.Expr("GREATEST(favorite_count - 1, 0)")).Error
+			if err != nil {
+				return err
+			}
+		}
+		return favoriteCount(tx, recipeID, &count)
+	})
+	return count, err
+}
+
+func favoriteCount(tx *gorm.DB, recipeID string, count *int) error {
+	return tx.Model(&models.Recipe{}).Unscoped().Where("id = ?", recipeID).
+		Select("favorite_count").Scan(count).Error
+}
+
+func (r *favoriteRepository) ListByUser(userID string, filter RecipeFilter, page *pagination.Params) ([]models.Favorite, *pagination.Pagination, error) {
+	query := r.db.Model(&models.Favorite{}).
+		Joins("JOIN recipes ON recipes.id = favorites.recipe_id AND recipes.deleted_at IS NULL").
+		Where("favorites.user_id = ?", userID)
+	query = applyRecipeFilter(query, filter)
+
+	var favorites []models.Favorite
+	result, err := pagination.Find(query, page, &favorites)
+	if err != nil || len(favorites) == 0 {
+		return favorites, result, err
+	}
+
+	// Load the page's recipes in one query
+	ids := make([]string, len(favorites))
+	for i, f := range favorites {
+		ids[i] = f.RecipeID
+	}
+	var recipes []models.Recipe
+	if err := r.db.Where("id IN ?", ids).Find(&recipes).Error; err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[string]*models.Recipe, len(recipes))
+	for i := range recipes {
+		byID[recipes[i].ID] = &recipes[i]
+	}
+	for i := range favorites {
+		favorites[i].Recipe = byID[favorites[i].RecipeID]
+	}
+	return favorites, result, nil
+}
+
+func (r *favoriteRepository) FavoritedRecipeIDs(userID string, recipeIDs []string) (map[string]bool, error) {
+	favorited := make(map[string]bool)
+	if len(recipeIDs) == 0 {
+		return favorited, nil
+	}
+
+	var ids []string
+	err := r.db.Model(&models.Favorite{}).
+		Where("user_id = ? AND recipe_id IN ?", userID, recipeIDs).
+		Pluck("recipe_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		favorited[id] = true
+	}
+	return favorited, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// foodCandidateLimit caps the foods fetched to match one ingredient
+const foodCandidateLimit = 100
+
+type FoodRepository interface {
+	FindByID(id string) (*models.Food, error)
+	// FindByIDs returns the foods found, keyed by ID
+	FindByIDs(ids []string) (map[string]*models.Food, error)
+	// Search lists foods whose name contains every word of the query,
+	// shortest names first
+	Search(query string, limit int) ([]models.Food, error)
+	// Candidates lists foods whose name contains any of the terms, shortest
+	// names first, for matching an ingredient
+	Candidates(terms []string) ([]models.Food, error)
+	// Upsert creates foods, updating those already imported with the same
+	// FoodData Central ID
+	Upsert(foods []models.Food) error
+}
+
+type foodRepository struct {
+	db *gorm.DB
+}
+
+func NewFoodRepository(db *gorm.DB) FoodRepository {
+	return &foodRepository{db: db}
+}
+
+func (r *foodRepository) FindByID(id string) (*models.Food, error) {
+	var food models.Food
+	err := r.db.Where("id = ?", id).First(&food).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &food, nil
+}
+
+func (r *foodRepository) FindByIDs(ids []string) (map[string]*models.Food, error) {
+	found := make(map[string]*models.Food, len(ids))
+	if len(ids) == 0 {
+		return found, nil
+	}
+	var foods []models.Food
+	if err := r.db.Where("id IN ?", ids).Find(&foods).Error; err != nil {
+		return nil, err
+	}
+	for i := range foods {
+		found[foods[i].ID] = &foods[i]
+	}
+	return found, nil
+}
+
+func (r *foodRepository) Search(query string, limit int) ([]models.Food, error) {
+	db := r.db.Model(&models.Food{})
+	for _, word := range strings.Fields(query) {
+		db = db.Where("name ILIKE ?", "%"+escapeLike(word)+"%")
+	}
+	var foods []models.Food
+	err := db.Order("length(name), name").Limit(limit).Find(&foods).Error
+	return foods, err
+}
+
+func (r *foodRepository) Candidates(terms []string) ([]models.Food, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	conditions := make([]string, len(terms))
+	args := make([]interface{}, len(terms))
+	for i, term := range terms {
+		conditions[i] = "name ILIKE ?"
+		args[i] = "%" + escapeLike(term) + "%"
+	}
+	var foods []models.Food
+	err := r.db.Where(strings.Join(conditions, " OR "), args...).
+		Order("length(name), name").Limit(foodCandidateLimit).Find(&foods).Error
+	return foods, err
+}
+
+func (r *foodRepository) Upsert(foods []models.Food) error {
+	if len(foods) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "fdc_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "data_type", "category", "nutrients", "portions", "updated_at"}),
+	}).Create(&foods).Error
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/meal-planner/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type HouseholdRepository interface {
+	Create(household *models.Household, owner *models.HouseholdMember) error
+	FindByID(id string) (*models.Household, error)
+	FindByUser(userID string) ([]models.Household, error)
+	Update(household *models.Household) error
+	// Delete removes a household with its members and eater profiles,
+	// revokes its pending invitations and makes its collections private
+	Delete(id string) error
+
+	FindMember(householdID, userID string) (*models.HouseholdMember, error)
+	UpdateMember(member *models.HouseholdMember) error
+	RemoveMember(householdID, userID string) error
+	TransferOwnership(householdID, fromUserID, toUserID string) error
+
+	CreateInvitation(invitation *models.HouseholdInvitation) error
+	FindInvitationByID(id string) (*models.HouseholdInvitation, error)
+	FindPendingInvitation(householdID, email string) (*models.HouseholdInvitation, error)
+	FindInvitationsByEmail(email string) ([]models.HouseholdInvitation, error)
+	FindInvitationsByHousehold(householdID string) ([]models.HouseholdInvitation, error)
+	UpdateInvitation(invitation *models.HouseholdInvitation) error
+	AcceptInvitation(invitation *models.HouseholdInvitation, member *models.HouseholdMember) error
+}
+
+type householdRepository struct {
+	db *gorm.DB
+}
+
+func NewHouseholdRepository(db *gorm.DB) HouseholdRepository {
+	return &householdRepository{db: db}
+}
+
+func (r *householdRepository) Create(household *models.Household, owner *models.HouseholdMember) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Members").Create(household).Error; err != nil {
+			return err
+		}
+		owner.HouseholdID = household.ID
+		return tx.Omit("User").Create(owner).Error
+	})
+}
+
+func (r *householdRepository) FindByID(id string) (*models.Household, error) {
+	var household models.Household
+	err := r.db.Preload("Members.User").Where("id = ?", id).First(&household).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &household, nil
+}
+
+func (r *householdRepository) FindByUser(userID string) ([]models.Household, error) {
+	var households []models.Household
+	err := r.db.Preload("Members.User").
+		Joins("JOIN household_members ON household_members.household_id = households.id").
+		Where("household_members.user_id = ?", userID).
+		Order("households.created_at").
+		Find(&households).Error
+	return households, err
+}
+
+func (r *householdRepository) Update(household *models.Household) error {
+	return r.db.Omit("Members").Save(household).Error
+}
+
+func (r *householdRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("household_id = ?", id).Delete(&models.HouseholdMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.HouseholdInvitation{}).
+			Where("household_id = ? AND status = ?", id, models.InvitationPending).
+			Update("status", models.InvitationRevoked).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("household_id = ?", id).Delete(&models.EaterProfile{}).Error; err != nil {
+			return err
+		}
+		// Household collections stay with their owners as private ones
+		if err := tx.Model(&models.Collection{}).
+			Where("household_id = ?", id).
+			Updates(map[string]interface{}{"household_id": nil, "visibility": models.CollectionPrivate}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Household{}, "id = ?", id).Error
+	})
+}
+
+func (r *householdRepository) FindMember(householdID, userID string) (*models.HouseholdMember, error) {
+	var member models.HouseholdMember
+	err := r.db.Where("household_id = ? AND user_id = ?", householdID, userID).First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *householdRepository) UpdateMember(member *models.HouseholdMember) error {
+	return r.db.Model(&models.HouseholdMember{}).
+		Where("household_id = ? AND user_id = ?", member.HouseholdID, member.UserID).
+		Update("role", member.Role).Error
+}
+
+func (r *householdRepository) RemoveMember(householdID, userID string) error {
+	return r.db.Where("household_id = ? AND user_id = ?", householdID, userID).
+		Delete(&models.HouseholdMember{}).Error
+}
+
+func (r *householdRepository) TransferOwnership(householdID, fromUserID, toUserID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.HouseholdMember{}).
+			Where("household_id = ? AND user_id = ?", householdID, fromUserID).
+			Update("role", models.HouseholdRoleMember).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.HouseholdMember{}).
+			Where("household_id = ? AND user_id = ?", householdID, toUserID).
+			Update("role", models.HouseholdRoleOwner).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Household{}).
+			Where("id = ?", householdID).
+			Update("owner_id", toUserID).Error
+	})
+}
+
+func (r *householdRepository) CreateInvitation(invitation *models.HouseholdInvitation) error {
+	return r.db.Omit("Household").Create(invitation).Error
+}
+
+func (r *householdRepository) FindInvitationByID(id string) (*models.HouseholdInvitation, error) {
+	var invitation models.HouseholdInvitation
+	err := r.db.Preload("Household").Where("id = ?", id).First(&invitation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *householdRepository) FindPendingInvitation(householdID, email string) (*models.HouseholdInvitation, error) {
+	var invitation models.HouseholdInvitation
+	err := r.db.Where("household_id = ? AND LOWER(email) = LOWER(?) AND status = ?", householdID, email, models.InvitationPending).
+		First(&invitation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *householdRepository) FindInvitationsByEmail(email string) ([]models.HouseholdInvitation, error) {
+	var invitations []models.HouseholdInvitation
+	err := r.db.Preload("Household").
+		Where("LOWER(email) = LOWER(?) AND status = ?", email, models.InvitationPending).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *householdRepository) FindInvitationsByHousehold(householdID string) ([]models.HouseholdInvitation, error) {
+	var invitations []models.HouseholdInvitation
+	err := r.db.Where("household_id = ?", householdID).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *householdRepository) UpdateInvitation(invitation *models.HouseholdInvitation) error {
+	return r.db.Omit("Household").Save(invitation).Error
+}
+
+func (r *householdRepository) AcceptInvitation(invitation *models.HouseholdInvitation, member *models.HouseholdMember) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Household").Save(invitation).Error; err != nil {
+			return err
+		}
+		return tx.Omit("User").Create(member).Error
+	})
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/meal-planner/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type NutritionGoalsRepository interface {
+	FindByUser(userID string) (*models.NutritionGoals, error)
+	Save(goals *models.NutritionGoals) error
+}
+
+type nutritionGoalsRepository struct {
+	db *gorm.DB
+}
+
+func NewNutritionGoalsRepository(db *gorm.DB) NutritionGoalsRepository {
+	return &nutritionGoalsRepository{db: db}
+}
+
+func (r *nutritionGoalsRepository) FindByUser(userID string) (*models.NutritionGoals, error) {
+	var goals models.NutritionGoals
+	err := r.db.Where("user_id = ?", userID).First(&goals).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &goals, nil
+}
+
+func (r *nutritionGoalsRepository) Save(goals *models.NutritionGoals) error {
+	return r.db.Save(goals).Error
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/meal-planner/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OnboardingRepository interface {
+	FindByUser(userID string) ([]models.OnboardingProgress, error)
+	// Save upserts step records and, when user is not nil, saves the user,
+	// in one transaction
+	Save(progress []models.OnboardingProgress, user *models.User) error
+}
+
+type onboardingRepository struct {
+	db *gorm.DB
+}
+
+func NewOnboardingRepository(db *gorm.DB) OnboardingRepository {
+	return &onboardingRepository{db: db}
+}
+
+func (r *onboardingRepository) FindByUser(userID string) ([]models.OnboardingProgress, error) {
+	var progress []models.OnboardingProgress
+	err := r.db.Where("user_id = ?", userID).Find(&progress).Error
+	return progress, err
+}
+
+func (r *onboardingRepository) Save(progress []models.OnboardingProgress, user *models.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(progress) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "step_key"}},
+				DoUpdates: clause.AssignmentColumns([]string{"status", "data", "updated_at"}),
+			}).Create(&progress).Error
+			if err != nil {
+				return err
+			}
+		}
+		if user == nil {
+			return nil
+		}
+		return tx.Save(user).Error
+	})
+}
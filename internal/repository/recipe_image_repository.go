@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/meal-planner/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrTooManyImages is returned by Create when the images would take a
+// recipe past the most allowed
+var ErrTooManyImages = errors.New("recipe has too many images")
+
+// RecipeImageRepository stores the metadata of uploaded recipe images and
+// keeps the recipe's image fields in sync with its primary image
+type RecipeImageRepository interface {
+	// Create appends images to a recipe, or returns ErrTooManyImages when
+	// the recipe would have more than maxImages. The first image of a
+	// recipe without a primary image becomes primary.
+	Create(recipeID string, images []models.RecipeImage, maxImages int) error
+	FindByID(recipeID, id string) (*models.RecipeImage, error)
+	ListByRecipe(recipeID string) ([]models.RecipeImage, error)
+	Count(recipeID string) (int64, error)
+	SetPrimary(recipeID, id string) error
+	// Delete removes an image. When it was primary, the next image in
+	// order becomes primary.
+	Delete(image *models.RecipeImage) error
+}
+
+type recipeImageRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeImageRepository(db *gorm.DB) RecipeImageRepository {
+	return &recipeImageRepository{db: db}
+}
+
+func (r *recipeImageRepository) Create(recipeID string, images []models.RecipeImage, maxImages int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// The count, positions and the primary image are checked and chosen
+		// under the recipe lock, so concurrent uploads cannot together go
+		// over the limit
+		if err := lockRecipe(tx, recipeID); err != nil {
+			return err
+		}
+		var stats struct {
+			Count        int
+			Last         *int
+			PrimaryCount int
+		}
+		err := tx.Model(&models.RecipeImage{}).
+			Select("COUNT(*) AS count, MAX(position) AS last, COUNT(*) FILTER (WHERE is_primary) AS primary_count").
+			Where("recipe_id = ?", recipeID).
+			Scan(&stats).Error
+		if err != nil {
+			return err
+		}
+		if stats.Count+len(images) > maxImages {
+			return ErrTooManyImages
+		}
+
+		position := 0
+		if stats.Last != nil {
+			position = *stats.Last + 1
+		}
+		for i := range images {
+			images[i].RecipeID = recipeID
+			images[i].Position = position + i
+			images[i].IsPrimary = stats.PrimaryCount == 0 && i == 0
+		}
+		if err := tx.Create(&images).Error; err != nil {
+			return err
+		}
+		if stats.PrimaryCount == 0 {
+			return setRecipeImage(tx, recipeID, &images[0])
+		}
+		return nil
+	})
+}
+
+func (r *recipeImageRepository) FindByID(recipeID, id string) (*models.RecipeImage, error) {
+	var image models.RecipeImage
+	err := r.db.Where("recipe_id = ? AND id = ?", recipeID, id).First(&image).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *recipeImageRepository) ListByRecipe(recipeID string) ([]models.RecipeImage, error) {
+	var images []models.RecipeImage
+	err := r.db.Where("recipe_id = ?", recipeID).Order("position").Find(&images).Error
+	return images, err
+}
+
+func (r *recipeImageRepository) Count(recipeID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.RecipeImage{}).Where("recipe_id = ?", recipeID).Count(&count).Error
+	return count, err
+}
+
+func (r *recipeImageRepository) SetPrimary(recipeID, id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockRecipe(tx, recipeID); err != nil {
+			return err
+		}
+		var image models.RecipeImage
+		if err := tx.Where("recipe_id = ? AND id = ?", recipeID, id).First(&image).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&models.RecipeImage{}).Where("recipe_id = ?", recipeID).
+			UpdateColumn("is_primary", gorm.Expr("id = ?", id)).Error
+		if err != nil {
+			return err
+		}
+		return setRecipeImage(tx, recipeID, &image)
+	})
+}
+
+func (r *recipeImageRepository) Delete(image *models.RecipeImage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockRecipe(tx, image.RecipeID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.RecipeImage{}, "id = ?", image.ID).Error; err != nil {
+			return err
+		}
+		if !image.IsPrimary {
+			return nil
+		}
+
+		var next models.RecipeImage
+		err := tx.Where("recipe_id = ?", image.RecipeID).Order("position").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return setRecipeImage(tx, image.RecipeID, nil)
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&next).UpdateColumn("is_primary", true).Error; err != nil {
+			return err
+		}
+		return setRecipeImage(tx, image.RecipeID, &next)
+	})
+}
+
+// setRecipeImage copies the primary image onto the recipe, or clears the
+// recipe's image when image is nil
+func setRecipeImage(tx *gorm.DB, recipeID string, image *models.RecipeImage) error {
+	columns := map[string]interface{}{
+		"image_url":       "",
+		"image_urls":      nil,
+		"image_blur_hash": "",
+	}
+	if image != nil {
+		columns["image_url"] = image.DisplayURL()
+		columns["image_urls"] = image.URLs
+		columns["image_blur_hash"] = image.BlurHash
+	}
+	return tx.Model(&models.Recipe{}).Where("id = ?", recipeID).UpdateColumns(columns).Error
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"github.com/meal-planner/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// RecipeNeighborRepository stores each recipe's cached list of similar
+// recipes
+type RecipeNeighborRepository interface {
+	// Replace sets the neighbor lists of the given recipes in one
+	// transaction. An empty list removes a recipe's neighbors.
+	Replace(lists map[string][]models.RecipeNeighbor) error
+	// ReferencingRecipes returns the recipes whose lists include any of
+	// the given recipes
+	ReferencingRecipes(neighborIDs []string) ([]string, error)
+	// DeleteStale removes the lists of, and entries for, recipes that no
+	// longer exist
+	DeleteStale() error
+	// Similar returns the cached neighbors of a recipe that match the
+	// filter, most similar first
+	Similar(recipeID string, filter RecipeFilter, limit int) ([]models.SimilarRecipe, error)
+}
+
+type recipeNeighborRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeNeighborRepository(db *gorm.DB) RecipeNeighborRepository {
+	return &recipeNeighborRepository{db: db}
+}
+
+func (r *recipeNeighborRepository) Replace(lists map[string][]models.RecipeNeighbor) error {
+	if len(lists) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(lists))
+	var rows []models.RecipeNeighbor
+	for id, list := range lists {
+		ids = append(ids, id)
+		rows = append(rows, list...)
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("recipe_id IN ?", ids).Delete(&models.RecipeNeighbor{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 1000).Error
+	})
+}
+
+func (r *recipeNeighborRepository) ReferencingRecipes(neighborIDs []string) ([]string, error) {
+	var ids []string
+	if len(neighborIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.RecipeNeighbor{}).
+		Where("neighbor_id IN ?", neighborIDs).
+		Distinct().Pluck("recipe_id", &ids).Error
+	return ids, err
+}
+
+func (r *recipeNeighborRepository) DeleteStale() error {
+	return r.db.Where(`NOT EXISTS (SELECT 1 FROM recipes WHERE recipes.id = recipe_neighbors.recipe_id AND recipes.deleted_at IS NULL)
+		OR NOT EXISTS (SELECT 1 FROM recipes WHERE recipes.id = recipe_neighbors.neighbor_id AND recipes.deleted_at IS NULL)`).
+		Delete(&models.RecipeNeighbor{}).Error
+}
+
+func (r *recipeNeighborRepository) Similar(recipeID string, filter RecipeFilter, limit int) ([]models.SimilarRecipe, error) {
+	var results []models.SimilarRecipe
+	err := applyRecipeFilter(r.db.Model(&models.Recipe{}), filter).
+		Joins("JOIN recipe_neighbors ON recipe_neighbors.neighbor_id = recipes.id AND recipe_neighbors.recipe_id = ?", recipeID).
+		Select("recipes.*, recipe_neighbors.score AS similarity").
+		Order("recipe_neighbors.rank").
+		Limit(limit).
+		Find(&results).Error
+	return results, err
+}
@@ -0,0 +1,439 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecipeFilter narrows a recipe listing
+type RecipeFilter struct {
+	// ViewerID limits results to public recipes plus the viewer's own.
+	// Leave empty to list every recipe (admins).
+	ViewerID string
+	// PublicOnly limits results to public recipes, for anonymous visitors
+	PublicOnly  bool
+	CreatedByID string
+
+	// Facet filters; several values within one facet match any of them
+	Categories   []string
+	Cuisines     []string
+	Difficulties []string
+	MaxTotalTime int
+	Tags         []string
+	MatchAnyTag  bool
+
+	// Calories per serving
+	MinCalories *float64
+	MaxCalories *float64
+
+	// RequiredTags must all be present, e.g. the caller's diets
+	RequiredTags []string
+	// ExcludedAllergens drops recipes with any of the allergen groups
+	ExcludedAllergens []string
+	// ExcludedIngredients drops recipes with an ingredient whose name
+	// contains any of the terms, e.g. allergies outside the allergen groups
+	ExcludedIngredients []string
+}
+
+type RecipeRepository interface {
+	// Create saves a new recipe together with its first revision. Creating
+	// a fork counts it on the original.
+	Create(recipe *models.Recipe, revision *models.RecipeRevision) error
+	// CreateBatch saves new recipes, each with its first revision, in one
+	// transaction: either all are saved or none
+	CreateBatch(recipes []*models.Recipe, revisions []*models.RecipeRevision) error
+	FindByID(id string) (*models.Recipe, error)
+	// UpdateNutrition saves recomputed nutrition and the ingredients' food
+	// matches without recording a revision. It returns false without saving
+	// when the recipe was revised since it was loaded; saving the edit
+	// computed its nutrition already.
+	UpdateNutrition(recipe *models.Recipe) (bool, error)
+	// Revise saves an edit of a recipe together with its next revision. It
+	// returns false without saving when the recipe was revised since it was
+	// loaded, so concurrent edits cannot silently overwrite each other.
+	Revise(recipe *models.Recipe, revision *models.RecipeRevision) (bool, error)
+	Delete(id string) error
+
+	// Names returns the names of the recipes the user created, or of the
+	// recipes without a creator when createdByID is nil, for finding
+	// duplicates before a bulk import
+	Names(createdByID *string) ([]string, error)
+
+	// ForEach calls fn with every recipe, loading them in batches. It stops
+	// at the first error fn returns.
+	ForEach(fn func(recipe *models.Recipe) error) error
+	// ChangedSince returns the recipes saved at or after since, and the IDs
+	// of the recipes deleted at or after it
+	ChangedSince(since time.Time) ([]models.Recipe, []string, error)
+
+	List(filter RecipeFilter, page *pagination.Params) ([]models.Recipe, *pagination.Pagination, error)
+
+	// WithIngredients returns up to limit recipes matching the filter that
+	// have an ingredient whose name contains any of the terms. Recipes with
+	// the largest share of such ingredients come first, then the best rated.
+	WithIngredients(terms []string, filter RecipeFilter, limit int) ([]models.Recipe, error)
+
+	// Search runs a full-text search. tsQuery and highlightQuery use
+	// to_tsquery syntax; results are ordered by relevance.
+	Search(tsQuery, highlightQuery string, filter RecipeFilter, page *pagination.Params) ([]models.RecipeSearchResult, *pagination.Pagination, error)
+
+	// Facets counts matching recipes per facet value. Each facet is counted
+	// with every filter applied except its own, so selecting "Italian" still
+	// shows how many recipes the other cuisines would add.
+	Facets(filter RecipeFilter) (*models.RecipeFacets, error)
+}
+
+type recipeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeRepository(db *gorm.DB) RecipeRepository {
+	return &recipeRepository{db: db}
+}
+
+func (r *recipeRepository) Create(recipe *models.Recipe, revision *models.RecipeRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createRecipe(tx, recipe, revision)
+	})
+}
+
+func (r *recipeRepository) CreateBatch(recipes []*models.Recipe, revisions []*models.RecipeRevision) error {
+	if len(recipes) != len(revisions) {
+		return fmt.Errorf("got %d recipes but %d revisions", len(recipes), len(revisions))
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range recipes {
+			if err := createRecipe(tx, recipes[i], revisions[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *recipeRepository) Names(createdByID *string) ([]string, error) {
+	query := r.db.Model(&models.Recipe{})
+	if createdByID != nil {
+		query = query.Where("created_by_id = ?", *createdByID)
+	} else {
+		query = query.Where("created_by_id IS NULL")
+	}
+	var names []string
+	err := query.Pluck("name", &names).Error
+	return names, err
+}
+
+// createRecipe saves a recipe and its first revision within a transaction
+func createRecipe(tx *gorm.DB, recipe *models.Recipe, revision *models.RecipeRevision) error {
+	recipe.Revision = 1
+	if err := tx.Create(recipe).Error; err != nil {
+		return err
+	}
+	revision.RecipeID = recipe.ID
+	revision.Number = recipe.Revision
+	if err := tx.Create(revision).Error; err != nil {
+		return err
+	}
+	if recipe.ForkedFromID == nil {
+		return nil
+	}
+	return tx.Model(&models.Recipe{}).Unscoped().Where("id = ?", *recipe.ForkedFromID).
+		UpdateColumn("fork_count", gorm.Expr("fork_count + 1")).Error
+}
+
+func (r *recipeRepository) FindByID(id string) (*models.Recipe, error) {
+	var recipe models.Recipe
+	err := r.db.Where("id = ?", id).First(&recipe).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &recipe, nil
+}
+
+// recipeCounters are maintained by other tables and by Revise, and left
+// alone when saving a recipe so concurrent updates are not overwritten
+var recipeCounters = []string{"favorite_count", "rating", "review_count", "bayesian_rating", "revision", "fork_count"}
+
+func (r *recipeRepository) UpdateNutrition(recipe *models.Recipe) (bool, error) {
+	result := r.db.Model(&models.Recipe{}).
+		Where("id = ? AND revision = ?", recipe.ID, recipe.Revision).
+		UpdateColumns(map[string]interface{}{
+			"ingredients":          recipe.Ingredients,
+			"nutrition":            recipe.Nutrition,
+			"nutrition_computed":   recipe.NutritionComputed,
+			"nutrition_coverage":   recipe.NutritionCoverage,
+			"nutrition_confidence": recipe.NutritionConfidence,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *recipeRepository) Revise(recipe *models.Recipe, revision *models.RecipeRevision) (bool, error) {
+	saved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Recipe{}).
+			Where("id = ? AND revision = ?", recipe.ID, recipe.Revision).
+			UpdateColumn("revision", gorm.Expr("revision + 1"))
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Omit(recipeCounters...).Save(recipe).Error; err != nil {
+			return err
+		}
+		revision.RecipeID = recipe.ID
+		revision.Number = recipe.Revision + 1
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+		saved = true
+		return nil
+	})
+	if saved {
+		recipe.Revision = revision.Number
+	}
+	return saved, err
+}
+
+// Delete deletes a recipe, uncounting it on the original when it is a fork
+func (r *recipeRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var forkedFromID *string
+		err := tx.Model(&models.Recipe{}).Where("id = ?", id).
+			Select("forked_from_id").Scan(&forkedFromID).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Recipe{}, "id = ?", id)
+		if result.Error != nil || result.RowsAffected == 0 || forkedFromID == nil {
+			return result.Error
+		}
+		return tx.Model(&models.Recipe{}).Unscoped().Where("id = ?", *forkedFromID).
+			UpdateColumn("fork_count", gorm.Expr("GREATEST(fork_count - 1, 0)")).Error
+	})
+}
+
+func (r *recipeRepository) ForEach(fn func(recipe *models.Recipe) error) error {
+	var batch []models.Recipe
+	return r.db.Order("id").FindInBatches(&batch, 200, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+func (r *recipeRepository) ChangedSince(since time.Time) ([]models.Recipe, []string, error) {
+	var recipes []models.Recipe
+	if err := r.db.Where("updated_at >= ?", since).Order("id").Find(&recipes).Error; err != nil {
+		return nil, nil, err
+	}
+	var deletedIDs []string
+	err := r.db.Model(&models.Recipe{}).Unscoped().
+		Where("deleted_at >= ?", since).Pluck("id", &deletedIDs).Error
+	return recipes, deletedIDs, err
+}
+
+func (r *recipeRepository) List(filter RecipeFilter, page *pagination.Params) ([]models.Recipe, *pagination.Pagination, error) {
+	var recipes []models.Recipe
+	result, err := pagination.Find(applyRecipeFilter(r.db.Model(&models.Recipe{}), filter), page, &recipes)
+	return recipes, result, err
+}
+
+func (r *recipeRepository) WithIngredients(terms []string, filter RecipeFilter, limit int) ([]models.Recipe, error) {
+	var recipes []models.Recipe
+	if len(terms) == 0 {
+		return recipes, nil
+	}
+	patterns := make(models.StringList, len(terms))
+	for i, term := range terms {
+		patterns[i] = "%" + escapeLike(term) + "%"
+	}
+	// The patterns are bound as one JSON array; gorm would expand a slice
+	// into a list of values
+	const matches = "i->>'name' ILIKE ANY (SELECT jsonb_array_elements_text(?::jsonb))"
+	// Ranking by the share of ingredients a term matches, an estimate of
+	// coverage, keeps the best covered recipes within the limit
+	order := clause.Expr{
+		SQL: `(SELECT SUM(CASE WHEN ` + matches + ` THEN 1 ELSE 0 END)::float / GREATEST(COUNT(*), 1)
+			FROM jsonb_array_elements(recipes.ingredients) i) DESC, bayesian_rating DESC, id`,
+		Vars:               []interface{}{patterns},
+		WithoutParentheses: true,
+	}
+	err := applyRecipeFilter(r.db.Model(&models.Recipe{}), filter).
+		Where("EXISTS (SELECT 1 FROM jsonb_array_elements(recipes.ingredients) i WHERE "+matches+")", patterns).
+		Order(clause.OrderBy{Expression: order}).
+		Limit(limit).
+		Find(&recipes).Error
+	return recipes, err
+}
+
+// headlineOptions configures ts_headline snippets
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2"
+
+func (r *recipeRepository) Search(tsQuery, highlightQuery string, filter RecipeFilter, page *pagination.Params) ([]models.RecipeSearchResult, *pagination.Pagination, error) {
+	query := applyRecipeFilter(r.db.Model(&models.Recipe{}), filter).
+		Where("search_vector @@ to_tsquery('english', ?)", tsQuery).
+		Select(`recipes.*,
+			ts_rank(search_vector, to_tsquery('english', ?)) AS rank,
+			ts_headline('english', name, to_tsquery('english', ?), 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlight,
+			ts_headline('english', coalesce(description, ''), to_tsquery('english', ?), ?) AS description_highlight`,
+			tsQuery, highlightQuery, highlightQuery, headlineOptions)
+
+	var results []models.RecipeSearchResult
+	result, err := pagination.Find(query, page, &results)
+	return results, result, err
+}
+
+// maxTagFacets caps the number of tag values returned as facets
+const maxTagFacets = 50
+
+func (r *recipeRepository) Facets(filter RecipeFilter) (*models.RecipeFacets, error) {
+	facets := &models.RecipeFacets{}
+
+	f := filter
+	f.Categories = nil
+	if err := r.countBy(f, "category", &facets.Categories); err != nil {
+		return nil, err
+	}
+
+	f = filter
+	f.Cuisines = nil
+	if err := r.countBy(f, "cuisine", &facets.Cuisines); err != nil {
+		return nil, err
+	}
+
+	f = filter
+	f.Difficulties = nil
+	if err := r.countBy(f, "difficulty", &facets.Difficulties); err != nil {
+		return nil, err
+	}
+
+	f = filter
+	f.Tags = nil
+	err := applyRecipeFilter(r.db.Model(&models.Recipe{}), f).
+		Joins("CROSS JOIN LATERAL jsonb_array_elements_text(recipes.tags) AS tag(value)").
+		Select("tag.value AS value, COUNT(*) AS count").
+		Group("tag.value").
+		Order("count DESC, value").
+		Limit(maxTagFacets).
+		Scan(&facets.Tags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	f = filter
+	f.MaxTotalTime = 0
+	var times struct {
+		Under15 int64
+		Under30 int64
+		Under45 int64
+		Under60 int64
+	}
+	err = applyRecipeFilter(r.db.Model(&models.Recipe{}), f).
+		Select(`COUNT(*) FILTER (WHERE total_time <= 15) AS under15,
+			COUNT(*) FILTER (WHERE total_time <= 30) AS under30,
+			COUNT(*) FILTER (WHERE total_time <= 45) AS under45,
+			COUNT(*) FILTER (WHERE total_time <= 60) AS under60`).
+		Scan(&times).Error
+	if err != nil {
+		return nil, err
+	}
+	facets.MaxTotalTime = []models.FacetCount{
+		{Value: "15", Count: times.Under15},
+		{Value: "30", Count: times.Under30},
+		{Value: "45", Count: times.Under45},
+		{Value: "60", Count: times.Under60},
+	}
+
+	return facets, nil
+}
+
+// countBy counts recipes per non-empty value of column
+func (r *recipeRepository) countBy(filter RecipeFilter, column string, dest *[]models.FacetCount) error {
+	return applyRecipeFilter(r.db.Model(&models.Recipe{}), filter).
+		Select(column + " AS value, COUNT(*) AS count").
+		Where(column + " <> ''").
+		Group(column).
+		Order("count DESC, value").
+		Scan(dest).Error
+}
+
+func applyRecipeFilter(query *gorm.DB, filter RecipeFilter) *gorm.DB {
+	if filter.ViewerID != "" {
+		query = query.Where("(recipes.is_public = ? OR recipes.created_by_id = ?)", true, filter.ViewerID)
+	}
+	if filter.PublicOnly {
+		query = query.Where("recipes.is_public = ?", true)
+	}
+	if filter.CreatedByID != "" {
+		query = query.Where("recipes.created_by_id = ?", filter.CreatedByID)
+	}
+	if len(filter.Categories) > 0 {
+		query = query.Where("LOWER(category) IN ?", lowerAll(filter.Categories))
+	}
+	if len(filter.Cuisines) > 0 {
+		query = query.Where("LOWER(cuisine) IN ?", lowerAll(filter.Cuisines))
+	}
+	if len(filter.Difficulties) > 0 {
+		query = query.Where("LOWER(difficulty) IN ?", lowerAll(filter.Difficulties))
+	}
+	if filter.MaxTotalTime > 0 {
+		query = query.Where("total_time <= ?", filter.MaxTotalTime)
+	}
+	if filter.MinCalories != nil {
+		query = query.Where("COALESCE((nutrition->>'calories')::numeric, 0) >= ?", *filter.MinCalories)
+	}
+	if filter.MaxCalories != nil {
+		query = query.Where("COALESCE((nutrition->>'calories')::numeric, 0) <= ?", *filter.MaxCalories)
+	}
+
+	if len(filter.Tags) > 0 {
+		if filter.MatchAnyTag {
+			query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(recipes.tags) t WHERE LOWER(t) IN ?)", lowerAll(filter.Tags))
+		} else {
+			query = requireTags(query, filter.Tags)
+		}
+	}
+	query = requireTags(query, filter.RequiredTags)
+
+	if len(filter.ExcludedAllergens) > 0 {
+		query = query.Where("NOT EXISTS (SELECT 1 FROM jsonb_array_elements_text(COALESCE(recipes.allergens, '[]'::jsonb)) a WHERE a IN ?)",
+			filter.ExcludedAllergens)
+	}
+	for _, term := range filter.ExcludedIngredients {
+		query = query.Where("NOT EXISTS (SELECT 1 FROM jsonb_array_elements(recipes.ingredients) i WHERE i->>'name' ILIKE ?)",
+			"%"+escapeLike(term)+"%")
+	}
+	return query
+}
+
+func requireTags(query *gorm.DB, tags []string) *gorm.DB {
+	for _, tag := range tags {
+		query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(recipes.tags) t WHERE LOWER(t) = ?)", strings.ToLower(tag))
+	}
+	return query
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, v := range values {
+		lowered[i] = strings.ToLower(v)
+	}
+	return lowered
+}
+
+// escapeLike escapes LIKE wildcards so the term matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/pagination"
+	"gorm.io/gorm"
+)
+
+// RecipeRevisionRepository reads recipe revisions. Revisions are written
+// with their recipe by RecipeRepository and never changed.
+type RecipeRevisionRepository interface {
+	FindByNumber(recipeID string, number int) (*models.RecipeRevision, error)
+	FindByID(id string) (*models.RecipeRevision, error)
+	// List lists a recipe's revisions without their snapshots
+	List(recipeID string, page *pagination.Params) ([]models.RecipeRevision, *pagination.Pagination, error)
+	AuthorNames(userIDs []string) (map[string]string, error)
+}
+
+type recipeRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeRevisionRepository(db *gorm.DB) RecipeRevisionRepository {
+	return &recipeRevisionRepository{db: db}
+}
+
+func (r *recipeRevisionRepository) FindByNumber(recipeID string, number int) (*models.RecipeRevision, error) {
+	return r.find(r.db.Where("recipe_id = ? AND number = ?", recipeID, number))
+}
+
+func (r *recipeRevisionRepository) FindByID(id string) (*models.RecipeRevision, error) {
+	return r.find(r.db.Where("id = ?", id))
+}
+
+func (r *recipeRevisionRepository) find(query *gorm.DB) (*models.RecipeRevision, error) {
+	var revision models.RecipeRevision
+	err := query.First(&revision).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *recipeRevisionRepository) List(recipeID string, page *pagination.Params) ([]models.RecipeRevision, *pagination.Pagination, error) {
+	var revisions []models.RecipeRevision
+	query := r.db.Model(&models.RecipeRevision{}).
+		Omit("snapshot").
+		Where("recipe_id = ?", recipeID)
+	result, err := pagination.Find(query, page, &revisions)
+	return revisions, result, err
+}
+
+func (r *recipeRevisionRepository) AuthorNames(userIDs []string) (map[string]string, error) {
+	return userNames(r.db, userIDs)
+}
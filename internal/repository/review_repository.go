@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"errors"
+	"math"
+
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReviewRepository interface {
+	FindByID(recipeID, id string) (*models.Review, error)
+	FindByUser(recipeID, userID string) (*models.Review, error)
+	List(recipeID string, page *pagination.Params) ([]models.Review, *pagination.Pagination, error)
+
+	// Create, Update and Delete keep the recipe's rating aggregates in sync.
+	// Create returns false without saving if the user already reviewed the
+	// recipe.
+	Create(review *models.Review) (bool, error)
+	Update(review *models.Review) error
+	Delete(review *models.Review) error
+
+	// AddVote and RemoveVote are idempotent and return the new helpful count
+	AddVote(reviewID, userID string) (int, error)
+	RemoveVote(reviewID, userID string) (int, error)
+	// VotedReviewIDs reports which of the given reviews the user found helpful
+	VotedReviewIDs(userID string, reviewIDs []string) (map[string]bool, error)
+	// AuthorNames returns the display names of the given users
+	AuthorNames(userIDs []string) (map[string]string, error)
+}
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+func (r *reviewRepository) FindByID(recipeID, id string) (*models.Review, error) {
+	var review models.Review
+	err := r.db.Where("recipe_id = ? AND id = ?", recipeID, id).First(&review).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *reviewRepository) FindByUser(recipeID, userID string) (*models.Review, error) {
+	var review models.Review
+	err := r.db.Where("recipe_id = ? AND user_id = ?", recipeID, userID).First(&review).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *reviewRepository) List(recipeID string, page *pagination.Params) ([]models.Review, *pagination.Pagination, error) {
+	var reviews []models.Review
+	result, err := pagination.Find(r.db.Model(&models.Review{}).Where("recipe_id = ?", recipeID), page, &reviews)
+	return reviews, result, err
+}
+
+func (r *reviewRepository) Create(review *models.Review) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockRecipe(tx, review.RecipeID); err != nil {
+			return err
+		}
+
+		// Checked under the recipe lock, so concurrent requests cannot both pass
+		var existing int64
+		if err := tx.Model(&models.Review{}).
+			Where("recipe_id = ? AND user_id = ?", review.RecipeID, review.UserID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return nil
+		}
+
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+		created = true
+		return refreshRecipeRating(tx, review.RecipeID)
+	})
+	return created, err
+}
+
+func (r *reviewRepository) Update(review *models.Review) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockRecipe(tx, review.RecipeID); err != nil {
+			return err
+		}
+		if err := tx.Omit("helpful_count").Save(review).Error; err != nil {
+			return err
+		}
+		return refreshRecipeRating(tx, review.RecipeID)
+	})
+}
+
+func (r *reviewRepository) Delete(review *models.Review) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockRecipe(tx, review.RecipeID); err != nil {
+			return err
+		}
+		if err := tx.Where("review_id = ?", review.ID).Delete(&models.ReviewVote{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Review{}, "id = ?", review.ID).Error; err != nil {
+			return err
+		}
+		return refreshRecipeRating(tx, review.RecipeID)
+	})
+}
+
+// lockRecipe takes a row lock on the recipe so rating aggregates are
+// recomputed by one transaction at a time
+func lockRecipe(tx *gorm.DB, recipeID string) error {
+	var id string
+	return tx.Model(&models.Recipe{}).Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", recipeID).
+		Select("id").
+		Scan(&id).Error
+}
+
+// refreshRecipeRating recomputes the recipe's rating aggregates from its reviews
+func refreshRecipeRating(tx *gorm.DB, recipeID string) error {
+	var stats struct {
+		Count   int
+		Average float64
+	}
+	err := tx.Model(&models.Review{}).
+		Select("COUNT(*) AS count, COALESCE(AVG(rating), 0) AS average").
+		Where("recipe_id = ?", recipeID).
+		Scan(&stats).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&models.Recipe{}).Unscoped().Where("id = ?", recipeID).UpdateColumns(map[string]interface{}{
+		"rating":          math.Round(stats.Average*100) / 100,
+		"review_count":    stats.Count,
+		"bayesian_rating": math.Round(models.BayesianRating(stats.Average, stats.Count)*1000) / 1000,
+	}).Error
+}
+
+func (r *reviewRepository) AddVote(reviewID, userID string) (int, error) {
+	var count int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.ReviewVote{ReviewID: reviewID, UserID: userID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			err := tx.Model(&models.Review{}).Where("id = ?", reviewID).
+				UpdateColumn("helpful_count", gorm.Expr("helpful_count + 1")).Error
+			if err != nil {
+				return err
+			}
+		}
+		return helpfulCount(tx, reviewID, &count)
+	})
+	return count, err
+}
+
+func (r *reviewRepository) RemoveVote(reviewID, userID string) (int, error) {
+	var count int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("review_id = ? AND user_id = ?", reviewID, userID).Delete(&models.ReviewVote{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			err := tx.Model(&models.Review{}).Where("id = ?", reviewID).
+				UpdateColumn("helpful_count", gorm.Expr("GREATEST(helpful_count - 1, 0)")).Error
+			if err != nil {
+				return err
+			}
+		}
+		return helpfulCount(tx, reviewID, &count)
+	})
+	return count, err
+}
+
+func helpfulCount(tx *gorm.DB, reviewID string, count *int) error {
+	return tx.Model(&models.Review{}).Where("id = ?", reviewID).
+		Select("helpful_count").Scan(count).Error
+}
+
+func (r *reviewRepository) VotedReviewIDs(userID string, reviewIDs []string) (map[string]bool, error) {
+	voted := make(map[string]bool)
+	if len(reviewIDs) == 0 {
+		return voted, nil
+	}
+
+	var ids []string
+	err := r.db.Model(&models.ReviewVote{}).
+		Where("user_id = ? AND review_id IN ?", userID, reviewIDs).
+		Pluck("review_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		voted[id] = true
+	}
+	return voted, nil
+}
+
+func (r *reviewRepository) AuthorNames(userIDs []string) (map[string]string, error) {
+	return userNames(r.db, userIDs)
+}
+
+// userNames returns the names of the given users, keyed by ID
+func userNames(db *gorm.DB, userIDs []string) (map[string]string, error) {
+	names := make(map[string]string)
+	if len(userIDs) == 0 {
+		return names, nil
+	}
+
+	var users []models.User
+	if err := db.Select("id", "name").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+	return names, nil
+}
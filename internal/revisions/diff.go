@@ -0,0 +1,161 @@
+// Package revisions compares recipe revisions field by field.
+package revisions
+
+import (
+	"strings"
+
+	"github.com/meal-planner/backend/internal/models"
+)
+
+// Change is one field that differs between two revisions. Single-valued
+// fields report From and To; list fields such as ingredients report the
+// entries Added and Removed, and leave From and To null. Ingredients and
+// instructions are ordered, so when only their order differs they report
+// Reordered instead.
+type Change struct {
+	Field     string      `json:"field"`
+	From      interface{} `json:"from"`
+	To        interface{} `json:"to"`
+	Added     []string    `json:"added,omitempty"`
+	Removed   []string    `json:"removed,omitempty"`
+	Reordered bool        `json:"reordered,omitempty"`
+}
+
+// nutrients lists the nutrition fields by their JSON name
+var nutrients = []struct {
+	name string
+	get  func(models.RecipeNutrition) float64
+}{
+	{"calories", func(n models.RecipeNutrition) float64 { return n.Calories }},
+	{"protein", func(n models.RecipeNutrition) float64 { return n.Protein }},
+	{"carbohydrates", func(n models.RecipeNutrition) float64 { return n.Carbohydrates }},
+	{"fat", func(n models.RecipeNutrition) float64 { return n.Fat }},
+	{"fiber", func(n models.RecipeNutrition) float64 { return n.Fiber }},
+	{"sugar", func(n models.RecipeNutrition) float64 { return n.Sugar }},
+	{"sodium", func(n models.RecipeNutrition) float64 { return n.Sodium }},
+	{"saturatedFat", func(n models.RecipeNutrition) float64 { return n.SaturatedFat }},
+	{"cholesterol", func(n models.RecipeNutrition) float64 { return n.Cholesterol }},
+	{"calcium", func(n models.RecipeNutrition) float64 { return n.Calcium }},
+	{"iron", func(n models.RecipeNutrition) float64 { return n.Iron }},
+	{"potassium", func(n models.RecipeNutrition) float64 { return n.Potassium }},
+	{"vitaminC", func(n models.RecipeNutrition) float64 { return n.VitaminC }},
+	{"vitaminD", func(n models.RecipeNutrition) float64 { return n.VitaminD }},
+}
+
+// Diff returns the fields that differ from one snapshot to another, in the
+// order they appear in a recipe. Fields are named as in the recipe JSON;
+// nutrients are named like "nutrition.calories". Ingredients are compared
+// as formatted lines, so a changed quantity shows as one line removed and
+// one added.
+func Diff(from, to models.RecipeSnapshot) []Change {
+	var changes []Change
+	value := func(field string, a, b interface{}) {
+		if a != b {
+			changes = append(changes, Change{Field: field, From: a, To: b})
+		}
+	}
+	list := func(field string, a, b []string) {
+		added, removed := listDiff(a, b)
+		if len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, Change{Field: field, Added: added, Removed: removed})
+		}
+	}
+	orderedList := func(field string, a, b []string) {
+		added, removed := listDiff(a, b)
+		switch {
+		case len(added) > 0 || len(removed) > 0:
+			changes = append(changes, Change{Field: field, Added: added, Removed: removed})
+		case !equal(a, b):
+			changes = append(changes, Change{Field: field, Reordered: true})
+		}
+	}
+
+	value("name", from.Name, to.Name)
+	value("description", from.Description, to.Description)
+	value("category", from.Category, to.Category)
+	value("cuisine", from.Cuisine, to.Cuisine)
+	value("prepTime", from.PrepTime, to.PrepTime)
+	value("cookTime", from.CookTime, to.CookTime)
+	value("servings", from.Servings, to.Servings)
+	value("difficulty", from.Difficulty, to.Difficulty)
+	orderedList("ingredients", ingredientLines(from.Ingredients), ingredientLines(to.Ingredients))
+	orderedList("instructions", from.Instructions, to.Instructions)
+	for _, n := range nutrients {
+		value("nutrition."+n.name, n.get(from.Nutrition), n.get(to.Nutrition))
+	}
+	list("tags", from.Tags, to.Tags)
+	value("imageUrl", from.ImageURL, to.ImageURL)
+	value("isPublic", from.IsPublic, to.IsPublic)
+	value("isFeatured", from.IsFeatured, to.IsFeatured)
+	list("allergenOverrides.contains", from.AllergenOverrides.Contains, to.AllergenOverrides.Contains)
+	list("allergenOverrides.freeOf", from.AllergenOverrides.FreeOf, to.AllergenOverrides.FreeOf)
+	return changes
+}
+
+func ingredientLines(items models.RecipeIngredients) []string {
+	lines := make([]string, len(items))
+	for i, ing := range items {
+		lines[i] = ing.Parsed().String()
+	}
+	return lines
+}
+
+// listDiff returns the entries of b missing from a, and of a missing from
+// b, counting repeated entries
+func listDiff(a, b []string) (added, removed []string) {
+	remaining := make(map[string]int, len(a))
+	for _, s := range a {
+		remaining[s]++
+	}
+	for _, s := range b {
+		if remaining[s] > 0 {
+			remaining[s]--
+		} else {
+			added = append(added, s)
+		}
+	}
+	for _, s := range a {
+		if remaining[s] > 0 {
+			remaining[s]--
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Summarize describes changes for a revision summary, e.g. "Changed name,
+// ingredients and nutrition"
+func Summarize(changes []Change) string {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, c := range changes {
+		field, _, _ := strings.Cut(c.Field, ".")
+		if field == "allergenOverrides" {
+			field = "allergens"
+		}
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+
+	switch len(fields) {
+	case 0:
+		return "No changes"
+	case 1:
+		return "Changed " + fields[0]
+	}
+	return "Changed " + strings.Join(fields[:len(fields)-1], ", ") + " and " + fields[len(fields)-1]
+}
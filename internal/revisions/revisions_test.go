@@ -0,0 +1,137 @@
+package revisions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/meal-planner/backend/internal/models"
+)
+
+func testSnapshot() models.RecipeSnapshot {
+	return models.RecipeSnapshot{
+		Name:     "Pancakes",
+		Category: "Breakfast",
+		PrepTime: 10,
+		CookTime: 15,
+		Servings: 4,
+		Ingredients: models.RecipeIngredients{
+			{Quantity: 2, Unit: "cup", Name: "flour"},
+			{Quantity: 2, Name: "eggs"},
+			{Quantity: 1.5, Unit: "cup", Name: "milk"},
+		},
+		Instructions: models.StringList{"Mix.", "Cook."},
+		Nutrition:    models.RecipeNutrition{Calories: 250, Protein: 8},
+		Tags:         models.StringList{"sweet"},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(s *models.RecipeSnapshot)
+		want   []Change
+	}{
+		{
+			name:   "no changes",
+			change: func(s *models.RecipeSnapshot) {},
+			want:   nil,
+		},
+		{
+			name: "scalar fields",
+			change: func(s *models.RecipeSnapshot) {
+				s.Name = "Fluffy pancakes"
+				s.Servings = 6
+				s.IsPublic = true
+			},
+			want: []Change{
+				{Field: "name", From: "Pancakes", To: "Fluffy pancakes"},
+				{Field: "servings", From: 4, To: 6},
+				{Field: "isPublic", From: false, To: true},
+			},
+		},
+		{
+			name: "changed ingredient quantity",
+			change: func(s *models.RecipeSnapshot) {
+				s.Ingredients[2].Quantity = 2
+			},
+			want: []Change{
+				{Field: "ingredients", Added: []string{"2 cup milk"}, Removed: []string{"1 1/2 cup milk"}},
+			},
+		},
+		{
+			name: "reordered steps and added tag",
+			change: func(s *models.RecipeSnapshot) {
+				s.Instructions = models.StringList{"Rest the batter.", "Mix.", "Cook."}
+				s.Tags = append(s.Tags, "kids")
+			},
+			want: []Change{
+				{Field: "instructions", Added: []string{"Rest the batter."}},
+				{Field: "tags", Added: []string{"kids"}},
+			},
+		},
+		{
+			name: "reordered steps only",
+			change: func(s *models.RecipeSnapshot) {
+				s.Instructions = models.StringList{"Cook.", "Mix."}
+			},
+			want: []Change{
+				{Field: "instructions", Reordered: true},
+			},
+		},
+		{
+			name: "reordered ingredients",
+			change: func(s *models.RecipeSnapshot) {
+				s.Ingredients[0], s.Ingredients[1] = s.Ingredients[1], s.Ingredients[0]
+			},
+			want: []Change{
+				{Field: "ingredients", Reordered: true},
+			},
+		},
+		{
+			name: "nutrition and allergen overrides",
+			change: func(s *models.RecipeSnapshot) {
+				s.Nutrition.Calories = 300
+				s.AllergenOverrides.FreeOf = []string{"dairy"}
+			},
+			want: []Change{
+				{Field: "nutrition.calories", From: 250.0, To: 300.0},
+				{Field: "allergenOverrides.freeOf", Added: []string{"dairy"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := testSnapshot()
+			to := testSnapshot()
+			tt.change(&to)
+			if got := Diff(from, to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		fields []string
+		want   string
+	}{
+		{nil, "No changes"},
+		{[]string{"name"}, "Changed name"},
+		{[]string{"name", "ingredients"}, "Changed name and ingredients"},
+		{[]string{"servings", "nutrition.calories", "nutrition.fat", "allergenOverrides.contains"}, "Changed servings, nutrition and allergens"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			var changes []Change
+			for _, f := range tt.fields {
+				changes = append(changes, Change{Field: f})
+			}
+			if got := Summarize(changes); got != tt.want {
+				t.Errorf("Summarize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,18 +2,21 @@ package router
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/meal-planner/backend/internal/config"
 	"github.com/meal-planner/backend/internal/handlers"
+	"github.com/meal-planner/backend/internal/mailer"
 	"github.com/meal-planner/backend/internal/middleware"
 	"github.com/meal-planner/backend/internal/repository"
 	"github.com/meal-planner/backend/internal/services"
+	"github.com/meal-planner/backend/internal/storage"
 	"gorm.io/gorm"
 )
 
 // Setup initializes and configures the router
-func Setup(db *gorm.DB, cfg *config.Config) *gin.Engine {
+func Setup(db *gorm.DB, cfg *config.Config, store storage.Store) *gin.Engine {
 	// Set Gin mode based on environment
 	if cfg.IsProduction() {
 		gin.SetMode(gin.ReleaseMode)
@@ -34,6 +37,11 @@ func Setup(db *gorm.DB, cfg *config.Config) *gin.Engine {
 		})
 	})
 
+	// Uploaded files kept on the local filesystem are served by the API
+	if local, ok := store.(*storage.Local); ok && strings.HasPrefix(local.BaseURL, "/") {
+		router.Static(local.BaseURL, local.Dir)
+	}
+
 	// API info endpoint
 	router.GET("/api", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -52,23 +60,133 @@ func Setup(db *gorm.DB, cfg *config.Config) *gin.Engine {
 					"onboarding": "POST /api/auth/onboarding/complete (protected)",
 					"preferences": "PUT /api/auth/preferences (protected)",
 				},
+				"onboarding": gin.H{
+					"progress": "GET /api/onboarding (protected)",
+					"submit": "POST /api/onboarding/steps/:step (protected)",
+					"skip": "POST /api/onboarding/steps/:step/skip (protected)",
+				},
+				"users": gin.H{
+					"goals": "GET|PUT /api/users/goals (protected)",
+					"pantry": "GET|PUT /api/users/pantry {items} (protected; ingredients on hand)",
+					"estimateGoals": "POST /api/users/goals/estimate (protected)",
+					"compareGoals": "POST /api/users/goals/compare {calories, protein, ...} (protected; a day's totals against the goals)",
+				},
+				"households": gin.H{
+					"list": "GET /api/households (protected)",
+					"create": "POST /api/households (protected)",
+					"get": "GET /api/households/:id (protected)",
+					"update": "PUT /api/households/:id (protected)",
+					"delete": "DELETE /api/households/:id (protected)",
+					"invite": "POST /api/households/:id/invitations (protected)",
+					"members": "PUT|DELETE /api/households/:id/members/:userId (protected)",
+					"leave": "POST /api/households/:id/leave (protected)",
+					"transfer": "POST /api/households/:id/transfer (protected)",
+					"activate": "POST /api/households/:id/activate (protected)",
+					"profiles": "GET|POST /api/households/:id/profiles, PUT|DELETE /api/households/:id/profiles/:profileId (protected)",
+					"diners": "GET /api/households/:id/diners?profiles=id1,id2 (protected)",
+					"invitations": "GET /api/invitations, POST /api/invitations/:id/accept|decline (protected)",
+				},
+				"recipes": gin.H{
+					"list": "GET /api/recipes?sort=-rating,name&limit=20&cursor=... (protected)",
+					"search": "GET /api/recipes/search?q=chicken -mushroom (protected)",
+					"cookable": "POST /api/recipes/cookable {ingredients, usePantry, assumeStaples, maxMissing, limit} (protected; accepts the list filters; without ingredients uses your pantry)",
+					"filters": "category, cuisine, difficulty, tags, tagMatch=all|any, maxTotalTime, minCalories, maxCalories, excludeAllergens=true, matchDiet=true, mine=true",
+					"allergens": "detected from ingredients; set allergenOverrides {contains, freeOf} on create/update; allergenWarnings lists the caller's conflicts",
+					"create": "POST /api/recipes (protected)",
+					"get": "GET /api/recipes/:id?units=metric|us|original (protected)",
+					"scaled": "GET /api/recipes/:id/scaled?servings=6&units=metric (protected)",
+					"update": "PUT /api/recipes/:id (protected)",
+					"delete": "DELETE /api/recipes/:id (protected)",
+					"import": "POST /api/recipes/import (protected; HTML body, {\"html\"} JSON or multipart file)",
+					"favorite": "POST|DELETE /api/recipes/:id/favorite (protected)",
+					"favorites": "GET /api/recipes/favorites (protected)",
+					"reviews": "GET|POST /api/recipes/:id/reviews, PUT|DELETE /api/recipes/:id/reviews/:reviewId (protected)",
+					"helpful": "POST|DELETE /api/recipes/:id/reviews/:reviewId/helpful (protected)",
+					"revisions": "GET /api/recipes/:id/revisions, GET /api/recipes/:id/revisions/:number (protected); send changeSummary and baseRevision on update",
+					"diff": "GET /api/recipes/:id/revisions/diff?from=1&to=3 (protected)",
+					"restore": "POST /api/recipes/:id/revisions/:number/restore (protected)",
+					"fork": "POST /api/recipes/:id/fork (protected)",
+					"similar": "GET /api/recipes/:id/similar?limit=10 (protected; accepts the list filters, excludeAllergens and matchDiet default to true)",
+					"cooklang": "GET /api/recipes/:id/cooklang, POST /api/recipes/import/cooklang (protected; .cook body or multipart file)",
+					"cooklangArchive": "POST /api/recipes/import/cooklang/archive?category=Dinner (protected; zip of .cook files as body or multipart file)",
+					"images": "GET /api/recipes/:id/images, POST /api/recipes/:id/images (protected; multipart \"images\" files, JPEG, PNG or GIF)",
+					"image": "DELETE /api/recipes/:id/images/:imageId, POST /api/recipes/:id/images/:imageId/primary (protected)",
+					"upstream": "GET /api/recipes/:id/upstream (protected; changes to a fork's original since forking)",
+				},
+				"collections": gin.H{
+					"list": "GET /api/collections (protected; yours and your households')",
+					"create": "POST /api/collections (protected; visibility private|household|public)",
+					"get": "GET|PUT|DELETE /api/collections/:id (protected)",
+					"recipes": "GET|POST /api/collections/:id/recipes, DELETE /api/collections/:id/recipes/:recipeId (protected)",
+					"reorder": "PUT /api/collections/:id/recipes/order {recipeIds} (protected)",
+					"share": "POST|DELETE /api/collections/:id/share (protected)",
+					"shared": "GET /api/shared/collections/:token, GET /api/shared/collections/:token/recipes (public)",
+				},
+				"foods": gin.H{
+					"search": "GET /api/foods?q=egg&limit=20 (protected)",
+					"get": "GET /api/foods/:id (protected)",
+				},
 			},
 		})
 	})
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
+	onboardingRepo := repository.NewOnboardingRepository(db)
+	householdRepo := repository.NewHouseholdRepository(db)
+	eaterProfileRepo := repository.NewEaterProfileRepository(db)
+	nutritionGoalsRepo := repository.NewNutritionGoalsRepository(db)
+	recipeRepo := repository.NewRecipeRepository(db)
+	favoriteRepo := repository.NewFavoriteRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	revisionRepo := repository.NewRecipeRevisionRepository(db)
+	collectionRepo := repository.NewCollectionRepository(db)
+	imageRepo := repository.NewRecipeImageRepository(db)
+	foodRepo := repository.NewFoodRepository(db)
+	neighborRepo := repository.NewRecipeNeighborRepository(db)
+
+	// Initialize mailer
+	mail := mailer.New(cfg)
 
 	// Initialize services
 	authService := services.NewAuthService(userRepo, cfg)
 	userService := services.NewUserService(userRepo, cfg)
+	onboardingService := services.NewOnboardingService(userRepo, onboardingRepo)
+	householdService := services.NewHouseholdService(householdRepo, userRepo, mail, cfg)
+	eaterProfileService := services.NewEaterProfileService(eaterProfileRepo, householdService)
+	nutritionGoalsService := services.NewNutritionGoalsService(nutritionGoalsRepo)
+	foodService := services.NewFoodService(foodRepo)
+	recipeService := services.NewRecipeService(recipeRepo, revisionRepo, userRepo, favoriteRepo, foodService)
+	favoriteService := services.NewFavoriteService(favoriteRepo, recipeService)
+	reviewService := services.NewReviewService(reviewRepo, userRepo, recipeService)
+	revisionService := services.NewRevisionService(revisionRepo, recipeService)
+	collectionService := services.NewCollectionService(collectionRepo, householdService, recipeService, cfg)
+	imageService := services.NewImageService(imageRepo, recipeService, store)
+	cooklangService := services.NewCooklangService(recipeService)
+	similarityService := services.NewSimilarityService(neighborRepo, recipeService)
+	pantryService := services.NewPantryService(recipeRepo, userRepo, recipeService)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	userHandler := handlers.NewUserHandler(userService)
+	onboardingHandler := handlers.NewOnboardingHandler(onboardingService)
+	householdHandler := handlers.NewHouseholdHandler(householdService)
+	eaterProfileHandler := handlers.NewEaterProfileHandler(eaterProfileService)
+	nutritionGoalsHandler := handlers.NewNutritionGoalsHandler(nutritionGoalsService)
+	recipeHandler := handlers.NewRecipeHandler(recipeService)
+	favoriteHandler := handlers.NewFavoriteHandler(favoriteService)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+	revisionHandler := handlers.NewRevisionHandler(revisionService, recipeService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+	imageHandler := handlers.NewImageHandler(imageService)
+	cooklangHandler := handlers.NewCooklangHandler(cooklangService)
+	similarityHandler := handlers.NewSimilarityHandler(similarityService)
+	pantryHandler := handlers.NewPantryHandler(pantryService)
+	foodHandler := handlers.NewFoodHandler(foodService)
 
 	// API routes
 	api := router.Group("/api")
+	api.Use(middleware.HouseholdMembershipMiddleware(householdService))
 	{
 		// Auth routes (public)
 		auth := api.Group("/auth")
@@ -88,9 +206,145 @@ func Setup(db *gorm.DB, cfg *config.Config) *gin.Engine {
 				protected.PUT("/preferences", userHandler.UpdatePreferences)
 
 				// Onboarding
-				protected.POST("/onboarding/complete", userHandler.CompleteOnboarding)
+				protected.POST("/onboarding/complete", onboardingHandler.CompleteOnboarding)
 			}
 		}
+
+		// Onboarding routes (protected)
+		onboarding := api.Group("/onboarding")
+		onboarding.Use(middleware.AuthMiddleware(cfg))
+		{
+			onboarding.GET("", onboardingHandler.GetProgress)
+			onboarding.POST("/steps/:step", onboardingHandler.SubmitStep)
+			onboarding.POST("/steps/:step/skip", onboardingHandler.SkipStep)
+		}
+
+		// User routes (protected)
+		users := api.Group("/users")
+		users.Use(middleware.AuthMiddleware(cfg))
+		{
+			users.GET("/goals", nutritionGoalsHandler.GetGoals)
+			users.PUT("/goals", nutritionGoalsHandler.UpdateGoals)
+			users.POST("/goals/estimate", nutritionGoalsHandler.EstimateGoals)
+			users.POST("/goals/compare", nutritionGoalsHandler.CompareGoals)
+			users.GET("/pantry", pantryHandler.GetPantry)
+			users.PUT("/pantry", pantryHandler.UpdatePantry)
+		}
+
+		// Household routes (protected)
+		households := api.Group("/households")
+		households.Use(middleware.AuthMiddleware(cfg))
+		{
+			households.GET("", householdHandler.ListHouseholds)
+			households.POST("", householdHandler.CreateHousehold)
+			households.GET("/:id", householdHandler.GetHousehold)
+			households.PUT("/:id", householdHandler.UpdateHousehold)
+			households.DELETE("/:id", householdHandler.DeleteHousehold)
+			households.POST("/:id/invitations", householdHandler.Invite)
+			households.GET("/:id/invitations", householdHandler.ListInvitations)
+			households.DELETE("/:id/invitations/:invitationId", householdHandler.RevokeInvitation)
+			households.PUT("/:id/members/:userId", householdHandler.ChangeMemberRole)
+			households.DELETE("/:id/members/:userId", householdHandler.RemoveMember)
+			households.POST("/:id/leave", householdHandler.Leave)
+			households.POST("/:id/transfer", householdHandler.TransferOwnership)
+			households.POST("/:id/activate", householdHandler.Activate)
+
+			// Eater profiles
+			households.GET("/:id/profiles", eaterProfileHandler.ListProfiles)
+			households.POST("/:id/profiles", eaterProfileHandler.CreateProfile)
+			households.PUT("/:id/profiles/:profileId", eaterProfileHandler.UpdateProfile)
+			households.DELETE("/:id/profiles/:profileId", eaterProfileHandler.DeleteProfile)
+			households.GET("/:id/diners", eaterProfileHandler.GetDiners)
+		}
+
+		// Recipe routes (protected)
+		recipes := api.Group("/recipes")
+		recipes.Use(middleware.AuthMiddleware(cfg))
+		{
+			recipes.GET("", recipeHandler.ListRecipes)
+			recipes.POST("", recipeHandler.CreateRecipe)
+			recipes.GET("/search", recipeHandler.SearchRecipes)
+			recipes.POST("/cookable", pantryHandler.CookableRecipes)
+			recipes.POST("/import", recipeHandler.ImportRecipe)
+			recipes.GET("/:id", recipeHandler.GetRecipe)
+			recipes.GET("/:id/scaled", recipeHandler.ScaleRecipe)
+			recipes.PUT("/:id", recipeHandler.UpdateRecipe)
+			recipes.DELETE("/:id", recipeHandler.DeleteRecipe)
+			recipes.POST("/:id/fork", recipeHandler.ForkRecipe)
+			recipes.GET("/:id/similar", similarityHandler.SimilarRecipes)
+
+			// Cooklang import and export
+			recipes.GET("/:id/cooklang", cooklangHandler.ExportRecipe)
+			recipes.POST("/import/cooklang", cooklangHandler.ImportRecipe)
+			recipes.POST("/import/cooklang/archive", cooklangHandler.ImportArchive)
+
+			// Images
+			recipes.GET("/:id/images", imageHandler.ListImages)
+			recipes.POST("/:id/images", imageHandler.UploadImages)
+			recipes.DELETE("/:id/images/:imageId", imageHandler.DeleteImage)
+			recipes.POST("/:id/images/:imageId/primary", imageHandler.SetPrimaryImage)
+
+			// Favorites
+			recipes.GET("/favorites", favoriteHandler.ListFavorites)
+			recipes.POST("/:id/favorite", favoriteHandler.AddFavorite)
+			recipes.DELETE("/:id/favorite", favoriteHandler.RemoveFavorite)
+
+			// Reviews
+			recipes.GET("/:id/reviews", reviewHandler.ListReviews)
+			recipes.POST("/:id/reviews", reviewHandler.CreateReview)
+			recipes.PUT("/:id/reviews/:reviewId", reviewHandler.UpdateReview)
+			recipes.DELETE("/:id/reviews/:reviewId", reviewHandler.DeleteReview)
+			recipes.POST("/:id/reviews/:reviewId/helpful", reviewHandler.MarkHelpful)
+			recipes.DELETE("/:id/reviews/:reviewId/helpful", reviewHandler.UnmarkHelpful)
+
+			// Revision history
+			recipes.GET("/:id/revisions", revisionHandler.ListRevisions)
+			recipes.GET("/:id/revisions/diff", revisionHandler.DiffRevisions)
+			recipes.GET("/:id/revisions/:number", revisionHandler.GetRevision)
+			recipes.POST("/:id/revisions/:number/restore", revisionHandler.RestoreRevision)
+			recipes.GET("/:id/upstream", revisionHandler.GetUpstreamChanges)
+		}
+
+		// Recipe collections (protected)
+		collections := api.Group("/collections")
+		collections.Use(middleware.AuthMiddleware(cfg))
+		{
+			collections.GET("", collectionHandler.ListCollections)
+			collections.POST("", collectionHandler.CreateCollection)
+			collections.GET("/:id", collectionHandler.GetCollection)
+			collections.PUT("/:id", collectionHandler.UpdateCollection)
+			collections.DELETE("/:id", collectionHandler.DeleteCollection)
+			collections.GET("/:id/recipes", collectionHandler.ListCollectionRecipes)
+			collections.POST("/:id/recipes", collectionHandler.AddCollectionRecipe)
+			collections.PUT("/:id/recipes/order", collectionHandler.ReorderCollectionRecipes)
+			collections.DELETE("/:id/recipes/:recipeId", collectionHandler.RemoveCollectionRecipe)
+			collections.POST("/:id/share", collectionHandler.ShareCollection)
+			collections.DELETE("/:id/share", collectionHandler.UnshareCollection)
+		}
+
+		// Shared collection links (public; the token is the secret)
+		shared := api.Group("/shared")
+		{
+			shared.GET("/collections/:token", collectionHandler.GetSharedCollection)
+			shared.GET("/collections/:token/recipes", collectionHandler.ListSharedCollectionRecipes)
+		}
+
+		// Food composition database (protected)
+		foods := api.Group("/foods")
+		foods.Use(middleware.AuthMiddleware(cfg))
+		{
+			foods.GET("", foodHandler.SearchFoods)
+			foods.GET("/:id", foodHandler.GetFood)
+		}
+
+		// Invitations addressed to the current user (protected)
+		invitations := api.Group("/invitations")
+		invitations.Use(middleware.AuthMiddleware(cfg))
+		{
+			invitations.GET("", householdHandler.ListMyInvitations)
+			invitations.POST("/:id/accept", householdHandler.AcceptInvitation)
+			invitations.POST("/:id/decline", householdHandler.DeclineInvitation)
+		}
 	}
 
 	return router
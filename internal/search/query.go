@@ -0,0 +1,135 @@
+// Package search parses user search input into Postgres full-text queries.
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Term is a single search term: one word, or a quoted phrase
+type Term struct {
+	Words    []string
+	Excluded bool
+}
+
+// Query is a parsed search query. All included terms must match; excluded
+// terms must not.
+//
+// Syntax:
+//
+//	chicken rice      both words, each matched as a prefix ("chick" finds "chicken")
+//	+chicken          same as chicken; accepted for clarity
+//	-mushroom         recipes must not contain mushroom
+//	"green curry"     words must appear next to each other
+//	-"blue cheese"    excludes the phrase
+type Query struct {
+	Terms []Term
+}
+
+// Parse parses search input. Punctuation inside terms is treated as a word
+// separator, so the result is always safe to pass to to_tsquery.
+func Parse(input string) Query {
+	var q Query
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		excluded := false
+		switch runes[i] {
+		case '-':
+			excluded = true
+			i++
+		case '+':
+			i++
+		}
+
+		var raw string
+		if i < len(runes) && runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			raw = string(runes[i+1 : end])
+			i = end + 1
+		} else {
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) {
+				end++
+			}
+			raw = string(runes[i:end])
+			i = end
+		}
+
+		if words := splitWords(raw); len(words) > 0 {
+			q.Terms = append(q.Terms, Term{Words: words, Excluded: excluded})
+		}
+	}
+
+	return q
+}
+
+// IsEmpty reports whether the query has no terms
+func (q Query) IsEmpty() bool {
+	return len(q.Terms) == 0
+}
+
+// HasIncluded reports whether the query has at least one non-excluded term
+func (q Query) HasIncluded() bool {
+	for _, t := range q.Terms {
+		if !t.Excluded {
+			return true
+		}
+	}
+	return false
+}
+
+// TSQuery renders the query in to_tsquery syntax. Included words match as
+// prefixes; excluded words match exactly, so "-nut" does not exclude nutmeg.
+func (q Query) TSQuery() string {
+	parts := make([]string, 0, len(q.Terms))
+	for _, t := range q.Terms {
+		if t.Excluded {
+			parts = append(parts, "!"+t.render(false))
+		} else {
+			parts = append(parts, t.render(true))
+		}
+	}
+	return strings.Join(parts, " & ")
+}
+
+// HighlightTSQuery renders only the included terms, any of which may match,
+// for use with ts_headline
+func (q Query) HighlightTSQuery() string {
+	parts := make([]string, 0, len(q.Terms))
+	for _, t := range q.Terms {
+		if !t.Excluded {
+			parts = append(parts, t.render(true))
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+func (t Term) render(prefix bool) string {
+	words := make([]string, len(t.Words))
+	for i, w := range t.Words {
+		if prefix {
+			w += ":*"
+		}
+		words[i] = w
+	}
+	if len(words) == 1 {
+		return words[0]
+	}
+	return "(" + strings.Join(words, " <-> ") + ")"
+}
+
+// splitWords lowercases s and splits it into runs of letters and digits
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,49 @@
+package search
+
+import "testing"
+
+func TestParseTSQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		highlight string
+	}{
+		{name: "empty", input: "   ", want: "", highlight: ""},
+		{name: "single word", input: "Chicken", want: "chicken:*", highlight: "chicken:*"},
+		{name: "multiple words", input: "chicken rice", want: "chicken:* & rice:*", highlight: "chicken:* | rice:*"},
+		{name: "required marker", input: "+chicken", want: "chicken:*", highlight: "chicken:*"},
+		{name: "excluded word", input: "chicken -mushroom", want: "chicken:* & !mushroom", highlight: "chicken:*"},
+		{name: "phrase", input: `"green curry"`, want: "(green:* <-> curry:*)", highlight: "(green:* <-> curry:*)"},
+		{name: "excluded phrase", input: `pasta -"blue cheese"`, want: "pasta:* & !(blue <-> cheese)", highlight: "pasta:*"},
+		{name: "unterminated quote", input: `"pad thai`, want: "(pad:* <-> thai:*)", highlight: "(pad:* <-> thai:*)"},
+		{name: "punctuation is stripped", input: "mac&cheese pb'j :* !x", want: "(mac:* <-> cheese:*) & (pb:* <-> j:*) & x:*", highlight: "(mac:* <-> cheese:*) | (pb:* <-> j:*) | x:*"},
+		{name: "lone operators are ignored", input: "- + -- soup", want: "soup:*", highlight: "soup:*"},
+		{name: "only exclusions", input: "-pork", want: "!pork", highlight: ""},
+		{name: "unicode letters", input: "jalapeño crème", want: "jalapeño:* & crème:*", highlight: "jalapeño:* | crème:*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := Parse(tt.input)
+			if got := q.TSQuery(); got != tt.want {
+				t.Errorf("TSQuery() = %q, want %q", got, tt.want)
+			}
+			if got := q.HighlightTSQuery(); got != tt.highlight {
+				t.Errorf("HighlightTSQuery() = %q, want %q", got, tt.highlight)
+			}
+		})
+	}
+}
+
+func TestQueryHasIncluded(t *testing.T) {
+	if Parse("-pork -beef").HasIncluded() {
+		t.Error("HasIncluded() = true for exclusions only")
+	}
+	if !Parse("tofu -pork").HasIncluded() {
+		t.Error("HasIncluded() = false with an included term")
+	}
+	if !Parse("").IsEmpty() {
+		t.Error("IsEmpty() = false for empty input")
+	}
+}
@@ -146,7 +146,7 @@ func (s *authService) RefreshToken(token string) (string, error) {
 	}
 
 	// Generate new token
-	newToken, err := utils.GenerateToken(claims.UserID, claims.Email, s.config.JWTSecret, s.config.GetJWTExpiration())
+	newToken, err := utils.GenerateTokenWithHousehold(claims.UserID, claims.Email, claims.HouseholdID, s.config.JWTSecret, s.config.GetJWTExpiration())
 	if err != nil {
 		return "", err
 	}
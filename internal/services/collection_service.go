@@ -0,0 +1,408 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/config"
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/pagination"
+	"github.com/meal-planner/backend/internal/repository"
+)
+
+// MaxCollectionRecipes caps the number of recipes in one collection
+const MaxCollectionRecipes = 500
+
+var (
+	ErrCollectionNotFound  = errors.New("collection not found")
+	ErrCollectionForbidden = errors.New("you do not have permission to modify this collection")
+)
+
+// CollectionPagination is the pagination and sort allowlist for listing
+// collections
+var CollectionPagination = pagination.Config{
+	Sortable: map[string]string{
+		"name":        "name",
+		"recipeCount": "recipe_count",
+		"createdAt":   "created_at",
+		"updatedAt":   "updated_at",
+	},
+	DefaultSort: "-updatedAt",
+}
+
+// CollectionRecipePagination is the pagination and sort allowlist for the
+// recipes of a collection, in their manual order by default
+var CollectionRecipePagination = pagination.Config{
+	Sortable: map[string]string{
+		"position": "collection_recipes.position",
+		"addedAt":  "collection_recipes.created_at",
+	},
+	DefaultSort: "position",
+	KeyColumn:   "collection_recipes.recipe_id",
+}
+
+// CollectionInput holds the editable fields of a collection
+type CollectionInput struct {
+	Name          string
+	Description   string
+	CoverImageURL string
+	Visibility    string
+	// HouseholdID is required for household visibility
+	HouseholdID string
+}
+
+// CollectionService manages recipe collections. Only a collection's owner
+// may change it; others may view it according to its visibility or through
+// its share link. Recipes in a collection are only listed to viewers who
+// may see them, so sharing a collection never reveals a private recipe.
+type CollectionService interface {
+	// List lists the user's collections and their households' collections
+	List(userID string, page *pagination.Params) ([]models.Collection, *pagination.Pagination, error)
+	Get(userID, collectionID string) (*models.Collection, error)
+	Create(userID string, input *CollectionInput) (*models.Collection, error)
+	Update(userID, collectionID string, input *CollectionInput) (*models.Collection, error)
+	Delete(userID, collectionID string) error
+
+	ListRecipes(userID, collectionID string, page *pagination.Params) ([]models.CollectionRecipe, *pagination.Pagination, error)
+	// AddRecipe and RemoveRecipe are idempotent and return the collection
+	AddRecipe(userID, collectionID, recipeID string) (*models.Collection, error)
+	RemoveRecipe(userID, collectionID, recipeID string) (*models.Collection, error)
+	// ReorderRecipes sets the order of the collection's recipes, which must
+	// list every recipe in the collection once
+	ReorderRecipes(userID, collectionID string, recipeIDs []string) error
+
+	// Share creates the collection's share link, or returns the existing
+	// one. Unshare revokes it; sharing again creates a new link.
+	Share(userID, collectionID string) (*models.Collection, error)
+	Unshare(userID, collectionID string) (*models.Collection, error)
+
+	// GetShared and ListSharedRecipes read a collection through its share
+	// link, without an account. Only public recipes are listed.
+	GetShared(token string) (*models.Collection, error)
+	ListSharedRecipes(token string, page *pagination.Params) ([]models.CollectionRecipe, *pagination.Pagination, error)
+}
+
+type collectionService struct {
+	collectionRepo   repository.CollectionRepository
+	householdService HouseholdService
+	recipeService    RecipeService
+	config           *config.Config
+}
+
+func NewCollectionService(collectionRepo repository.CollectionRepository, householdService HouseholdService, recipeService RecipeService, cfg *config.Config) CollectionService {
+	return &collectionService{
+		collectionRepo:   collectionRepo,
+		householdService: householdService,
+		recipeService:    recipeService,
+		config:           cfg,
+	}
+}
+
+func (s *collectionService) List(userID string, page *pagination.Params) ([]models.Collection, *pagination.Pagination, error) {
+	households, err := s.householdService.ListForUser(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter := repository.CollectionFilter{UserID: userID}
+	for _, h := range households {
+		filter.HouseholdIDs = append(filter.HouseholdIDs, h.ID)
+	}
+
+	collections, result, err := s.collectionRepo.List(filter, page)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range collections {
+		s.presentTo(userID, &collections[i])
+	}
+	return collections, result, nil
+}
+
+func (s *collectionService) Get(userID, collectionID string) (*models.Collection, error) {
+	collection, err := s.findVisible(userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	s.presentTo(userID, collection)
+	return collection, nil
+}
+
+func (s *collectionService) Create(userID string, input *CollectionInput) (*models.Collection, error) {
+	collection := &models.Collection{OwnerID: userID}
+	if err := s.applyInput(userID, collection, input); err != nil {
+		return nil, err
+	}
+	if err := s.collectionRepo.Create(collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+func (s *collectionService) Update(userID, collectionID string, input *CollectionInput) (*models.Collection, error) {
+	collection, err := s.findOwned(userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyInput(userID, collection, input); err != nil {
+		return nil, err
+	}
+	if err := s.collectionRepo.Update(collection); err != nil {
+		return nil, err
+	}
+	s.presentTo(userID, collection)
+	return collection, nil
+}
+
+func (s *collectionService) Delete(userID, collectionID string) error {
+	if _, err := s.findOwned(userID, collectionID); err != nil {
+		return err
+	}
+	return s.collectionRepo.Delete(collectionID)
+}
+
+func (s *collectionService) ListRecipes(userID, collectionID string, page *pagination.Params) ([]models.CollectionRecipe, *pagination.Pagination, error) {
+	if _, err := s.findVisible(userID, collectionID); err != nil {
+		return nil, nil, err
+	}
+	filter, err := s.recipeService.VisibleFilter(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, result, err := s.collectionRepo.ListRecipes(collectionID, filter, page)
+	if err != nil {
+		return nil, nil, err
+	}
+	var recipes []*models.Recipe
+	for i := range entries {
+		if entries[i].Recipe != nil {
+			recipes = append(recipes, entries[i].Recipe)
+		}
+	}
+	if err := s.recipeService.WarnAllergens(userID, recipes); err != nil {
+		return nil, nil, err
+	}
+	return entries, result, nil
+}
+
+func (s *collectionService) AddRecipe(userID, collectionID, recipeID string) (*models.Collection, error) {
+	if _, err := s.findOwned(userID, collectionID); err != nil {
+		return nil, err
+	}
+	// Only recipes the user can see may be collected
+	if _, err := s.recipeService.Get(userID, recipeID); err != nil {
+		return nil, err
+	}
+	err := s.collectionRepo.AddRecipe(collectionID, recipeID, MaxCollectionRecipes)
+	if errors.Is(err, repository.ErrCollectionFull) {
+		return nil, newValidationError("a collection can hold at most %d recipes", MaxCollectionRecipes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(userID, collectionID)
+}
+
+func (s *collectionService) RemoveRecipe(userID, collectionID, recipeID string) (*models.Collection, error) {
+	// Removing is allowed even if the recipe has since become private
+	if _, err := s.findOwned(userID, collectionID); err != nil {
+		return nil, err
+	}
+	if err := s.collectionRepo.RemoveRecipe(collectionID, recipeID); err != nil {
+		return nil, err
+	}
+	return s.Get(userID, collectionID)
+}
+
+func (s *collectionService) ReorderRecipes(userID, collectionID string, recipeIDs []string) error {
+	if _, err := s.findOwned(userID, collectionID); err != nil {
+		return err
+	}
+	saved, err := s.collectionRepo.ReorderRecipes(collectionID, recipeIDs)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		return newValidationError("recipeIds must list every recipe in the collection exactly once")
+	}
+	return nil
+}
+
+func (s *collectionService) Share(userID, collectionID string) (*models.Collection, error) {
+	collection, err := s.findOwned(userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection.ShareToken == nil {
+		token, err := newShareToken()
+		if err != nil {
+			return nil, err
+		}
+		collection.ShareToken = &token
+		if err := s.collectionRepo.Update(collection); err != nil {
+			return nil, err
+		}
+	}
+	s.presentTo(userID, collection)
+	return collection, nil
+}
+
+func (s *collectionService) Unshare(userID, collectionID string) (*models.Collection, error) {
+	collection, err := s.findOwned(userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection.ShareToken != nil {
+		collection.ShareToken = nil
+		if err := s.collectionRepo.Update(collection); err != nil {
+			return nil, err
+		}
+	}
+	s.presentTo(userID, collection)
+	return collection, nil
+}
+
+func (s *collectionService) GetShared(token string) (*models.Collection, error) {
+	collection, err := s.findShared(token)
+	if err != nil {
+		return nil, err
+	}
+	s.presentTo("", collection)
+	return collection, nil
+}
+
+func (s *collectionService) ListSharedRecipes(token string, page *pagination.Params) ([]models.CollectionRecipe, *pagination.Pagination, error) {
+	collection, err := s.findShared(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.collectionRepo.ListRecipes(collection.ID, repository.RecipeFilter{PublicOnly: true}, page)
+}
+
+func (s *collectionService) findShared(token string) (*models.Collection, error) {
+	if token == "" {
+		return nil, ErrCollectionNotFound
+	}
+	collection, err := s.collectionRepo.FindByShareToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, ErrCollectionNotFound
+	}
+	return collection, nil
+}
+
+// findVisible loads a collection the user may view. Collections the user
+// may not view are reported as not found so their existence is not
+// revealed.
+func (s *collectionService) findVisible(userID, collectionID string) (*models.Collection, error) {
+	collection, err := s.collectionRepo.FindByID(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, ErrCollectionNotFound
+	}
+
+	switch {
+	case collection.IsOwnedBy(userID), collection.Visibility == models.CollectionPublic:
+		return collection, nil
+	case collection.Visibility == models.CollectionHousehold && collection.HouseholdID != nil:
+		_, err := s.householdService.Authorize(userID, *collection.HouseholdID, models.HouseholdRoleViewer)
+		if err == nil {
+			return collection, nil
+		}
+		if !errors.Is(err, ErrHouseholdNotFound) && !errors.Is(err, ErrHouseholdAccessDenied) {
+			return nil, err
+		}
+	}
+	return nil, ErrCollectionNotFound
+}
+
+// findOwned loads a collection the user may modify
+func (s *collectionService) findOwned(userID, collectionID string) (*models.Collection, error) {
+	collection, err := s.findVisible(userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if !collection.IsOwnedBy(userID) {
+		return nil, ErrCollectionForbidden
+	}
+	return collection, nil
+}
+
+// presentTo sets the share link for the owner and hides the share token
+// from everyone else
+func (s *collectionService) presentTo(userID string, collection *models.Collection) {
+	if !collection.IsOwnedBy(userID) || collection.ShareToken == nil {
+		collection.ShareToken = nil
+		return
+	}
+	collection.ShareURL = fmt.Sprintf("%s/shared/collections/%s", strings.TrimRight(s.config.FrontendURL, "/"), *collection.ShareToken)
+}
+
+// applyInput validates the input and copies it onto the collection
+func (s *collectionService) applyInput(userID string, collection *models.Collection, input *CollectionInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return newValidationError("name is required")
+	}
+	if len(name) > 255 {
+		return newValidationError("name must be at most 255 characters")
+	}
+
+	description := strings.TrimSpace(input.Description)
+	if len(description) > 2000 {
+		return newValidationError("description must be at most 2000 characters")
+	}
+
+	coverImageURL := strings.TrimSpace(input.CoverImageURL)
+	if coverImageURL != "" {
+		u, err := url.Parse(coverImageURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return newValidationError("coverImageUrl must be an http or https URL")
+		}
+	}
+
+	visibility := models.CollectionPrivate
+	if strings.TrimSpace(input.Visibility) != "" {
+		var ok bool
+		visibility, ok = matchRecipeOption(input.Visibility, models.CollectionVisibilities)
+		if !ok {
+			return newValidationError("visibility must be one of: %s", strings.Join(models.CollectionVisibilities, ", "))
+		}
+	}
+
+	var householdID *string
+	if visibility == models.CollectionHousehold {
+		id := strings.TrimSpace(input.HouseholdID)
+		if id == "" {
+			return newValidationError("householdId is required for household visibility")
+		}
+		// Sharing into a household adds to it, which viewers may not do
+		if _, err := s.householdService.Authorize(userID, id, models.HouseholdRoleMember); err != nil {
+			return err
+		}
+		householdID = &id
+	}
+
+	collection.Name = name
+	collection.Description = description
+	collection.CoverImageURL = coverImageURL
+	collection.Visibility = visibility
+	collection.HouseholdID = householdID
+	return nil
+}
+
+// newShareToken returns a random, URL-safe share link secret
+func newShareToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/cooklang"
+	"github.com/meal-planner/backend/internal/recipeimport"
+)
+
+// Cooklang archive import limits
+const (
+	MaxCooklangArchiveBytes = 20 << 20
+	MaxCooklangArchiveFiles = 200
+)
+
+// CooklangArchiveFile is the outcome of importing one file of an archive.
+// Error is set when the file could not be imported.
+type CooklangArchiveFile struct {
+	File     string   `json:"file"`
+	RecipeID string   `json:"recipeId,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Warnings []string `json:"warnings"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// CooklangArchiveResult is the outcome of an archive import
+type CooklangArchiveResult struct {
+	Imported int                   `json:"imported"`
+	Failed   int                   `json:"failed"`
+	Files    []CooklangArchiveFile `json:"files"`
+}
+
+// CooklangService imports recipes from and exports them to Cooklang
+// documents (.cook files)
+type CooklangService interface {
+	// Export returns the recipe as a Cooklang document and a file name for it
+	Export(userID, recipeID string) ([]byte, string, error)
+
+	// ImportDraft reads a Cooklang document into a draft. Nothing is saved;
+	// the draft is returned for the user to review and create.
+	ImportDraft(src io.Reader) (*recipeimport.Result, error)
+
+	// ImportArchive creates a private recipe of the user for each .cook
+	// file of a zip archive. Files that cannot be imported are reported
+	// and skipped. category is used for files that do not name a course.
+	// When an unexpected error stops the import, the result so far, with
+	// the recipes already created, is returned along with the error.
+	ImportArchive(userID string, archive io.ReaderAt, size int64, category string) (*CooklangArchiveResult, error)
+}
+
+type cooklangService struct {
+	recipeService RecipeService
+}
+
+func NewCooklangService(recipeService RecipeService) CooklangService {
+	return &cooklangService{
+		recipeService: recipeService,
+	}
+}
+
+func (s *cooklangService) Export(userID, recipeID string) ([]byte, string, error) {
+	recipe, err := s.recipeService.Get(userID, recipeID)
+	if err != nil {
+		return nil, "", err
+	}
+	return cooklang.Export(recipe), cooklang.FileName(recipe.Name), nil
+}
+
+func (s *cooklangService) ImportDraft(src io.Reader) (*recipeimport.Result, error) {
+	result, err := cooklang.Import(src)
+	if err != nil {
+		return nil, cooklangError(err)
+	}
+	return result, nil
+}
+
+func (s *cooklangService) ImportArchive(userID string, archive io.ReaderAt, size int64, category string) (*CooklangArchiveResult, error) {
+	if size > MaxCooklangArchiveBytes {
+		return nil, newValidationError("archive must be at most %d MB", MaxCooklangArchiveBytes>>20)
+	}
+	reader, err := zip.NewReader(archive, size)
+	if err != nil {
+		return nil, newValidationError("archive is not a valid zip file")
+	}
+
+	var files []*zip.File
+	for _, f := range reader.File {
+		if isCooklangFile(f) {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return nil, newValidationError("archive has no .cook files")
+	}
+	if len(files) > MaxCooklangArchiveFiles {
+		return nil, newValidationError("archive can have at most %d .cook files", MaxCooklangArchiveFiles)
+	}
+
+	result := &CooklangArchiveResult{Files: make([]CooklangArchiveFile, 0, len(files))}
+	for _, f := range files {
+		entry, err := s.importArchiveFile(userID, f, category)
+		if err != nil {
+			return result, err
+		}
+		if entry.Error != "" {
+			result.Failed++
+		} else {
+			result.Imported++
+		}
+		result.Files = append(result.Files, *entry)
+	}
+	return result, nil
+}
+
+// importArchiveFile imports one file of an archive. Problems with the file
+// are reported in the entry; only unexpected errors are returned.
+func (s *cooklangService) importArchiveFile(userID string, f *zip.File, category string) (*CooklangArchiveFile, error) {
+	entry := &CooklangArchiveFile{File: f.Name, Warnings: []string{}}
+	if f.UncompressedSize64 > cooklang.MaxSize {
+		entry.Error = cooklang.ErrTooLarge.Error()
+		return entry, nil
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		entry.Error = "file could not be read from the archive"
+		return entry, nil
+	}
+	result, err := cooklang.Import(rc)
+	rc.Close()
+	if err != nil {
+		entry.Error = err.Error()
+		return entry, nil
+	}
+	entry.Warnings = result.Warnings
+
+	draft := result.Draft
+	if draft.Name == "" {
+		draft.Name = strings.TrimSuffix(path.Base(f.Name), path.Ext(f.Name))
+	}
+	if draft.Category == "" {
+		draft.Category = category
+	}
+
+	input := InputFromDraft(&draft)
+	input.ChangeSummary = "Imported from " + path.Base(f.Name)
+	recipe, err := s.recipeService.Create(userID, input)
+	if err != nil {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			entry.Error = validationErr.Message
+			return entry, nil
+		}
+		return nil, err
+	}
+	entry.RecipeID, entry.Name = recipe.ID, recipe.Name
+	return entry, nil
+}
+
+// isCooklangFile reports whether an archive entry is a .cook file, leaving
+// out hidden files and the resource forks macOS adds to archives
+func isCooklangFile(f *zip.File) bool {
+	if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(f.Name), ".cook") {
+		return false
+	}
+	for _, part := range strings.Split(f.Name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return false
+		}
+	}
+	return true
+}
+
+// cooklangError reports a document that cannot be read as a validation error
+func cooklangError(err error) error {
+	if errors.Is(err, cooklang.ErrInvalidUTF8) || errors.Is(err, cooklang.ErrTooLarge) ||
+		errors.Is(err, cooklang.ErrUnterminatedFrontMatter) {
+		return newValidationError("%s", err.Error())
+	}
+	return err
+}
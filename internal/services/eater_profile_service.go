@@ -0,0 +1,237 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/repository"
+)
+
+var (
+	ErrEaterProfileNotFound = errors.New("eater profile not found")
+)
+
+// EaterProfileInput holds the editable fields of an eater profile
+type EaterProfileInput struct {
+	Name              string
+	AgeGroup          string
+	UserID            *string
+	IsGuest           bool
+	Allergies         []string
+	Diets             []string
+	Dislikes          []string
+	PortionMultiplier *float64
+}
+
+type EaterProfileService interface {
+	List(userID, householdID string) ([]models.EaterProfile, error)
+	Create(userID, householdID string, input *EaterProfileInput) (*models.EaterProfile, error)
+	Update(userID, householdID, profileID string, input *EaterProfileInput) (*models.EaterProfile, error)
+	Delete(userID, householdID, profileID string) error
+
+	// SummarizeDiners returns the union of restrictions and the total portion
+	// factor for the given profiles, or for every profile in the household
+	// when profileIDs is empty. Planning and shopping use it per meal.
+	SummarizeDiners(userID, householdID string, profileIDs []string) (*models.DinerSummary, error)
+}
+
+type eaterProfileService struct {
+	profileRepo      repository.EaterProfileRepository
+	householdService HouseholdService
+}
+
+func NewEaterProfileService(profileRepo repository.EaterProfileRepository, householdService HouseholdService) EaterProfileService {
+	return &eaterProfileService{
+		profileRepo:      profileRepo,
+		householdService: householdService,
+	}
+}
+
+func (s *eaterProfileService) List(userID, householdID string) ([]models.EaterProfile, error) {
+	if _, err := s.householdService.Authorize(userID, householdID, models.HouseholdRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.profileRepo.FindByHousehold(householdID)
+}
+
+func (s *eaterProfileService) Create(userID, householdID string, input *EaterProfileInput) (*models.EaterProfile, error) {
+	if _, err := s.householdService.Authorize(userID, householdID, models.HouseholdRoleMember); err != nil {
+		return nil, err
+	}
+
+	profile := &models.EaterProfile{HouseholdID: householdID}
+	if err := s.applyInput(profile, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.profileRepo.Create(profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (s *eaterProfileService) Update(userID, householdID, profileID string, input *EaterProfileInput) (*models.EaterProfile, error) {
+	if _, err := s.householdService.Authorize(userID, householdID, models.HouseholdRoleMember); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.profileRepo.FindByID(householdID, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrEaterProfileNotFound
+	}
+
+	if err := s.applyInput(profile, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.profileRepo.Update(profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (s *eaterProfileService) Delete(userID, householdID, profileID string) error {
+	if _, err := s.householdService.Authorize(userID, householdID, models.HouseholdRoleMember); err != nil {
+		return err
+	}
+
+	profile, err := s.profileRepo.FindByID(householdID, profileID)
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		return ErrEaterProfileNotFound
+	}
+	return s.profileRepo.Delete(householdID, profileID)
+}
+
+func (s *eaterProfileService) SummarizeDiners(userID, householdID string, profileIDs []string) (*models.DinerSummary, error) {
+	if _, err := s.householdService.Authorize(userID, householdID, models.HouseholdRoleViewer); err != nil {
+		return nil, err
+	}
+
+	var profiles []models.EaterProfile
+	var err error
+	if len(profileIDs) == 0 {
+		profiles, err = s.profileRepo.FindByHousehold(householdID)
+	} else {
+		profiles, err = s.profileRepo.FindByIDs(householdID, profileIDs)
+		if err == nil && len(profiles) != len(uniqueStrings(profileIDs)) {
+			return nil, ErrEaterProfileNotFound
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return models.SummarizeDiners(profiles), nil
+}
+
+// applyInput validates the input and copies it onto the profile
+func (s *eaterProfileService) applyInput(profile *models.EaterProfile, input *EaterProfileInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return newValidationError("name is required")
+	}
+	if len(name) > 255 {
+		return newValidationError("name must be at most 255 characters")
+	}
+
+	ageGroup := strings.ToLower(strings.TrimSpace(input.AgeGroup))
+	if ageGroup == "" {
+		ageGroup = models.AgeGroupAdult
+	}
+	if !models.IsValidAgeGroup(ageGroup) {
+		return newValidationError("ageGroup must be one of: adult, teen, child, toddler")
+	}
+
+	// A new profile gets its age group's portion; an update that leaves
+	// the multiplier out keeps the current one
+	portion := profile.PortionMultiplier
+	if profile.ID == "" {
+		portion = models.DefaultPortionMultiplier(ageGroup)
+	}
+	if input.PortionMultiplier != nil {
+		portion = *input.PortionMultiplier
+		if portion < 0.1 || portion > 5 {
+			return newValidationError("portionMultiplier must be between 0.1 and 5")
+		}
+	}
+
+	// A profile may represent a household member; check they belong to it
+	if input.UserID != nil && *input.UserID != "" {
+		if _, err := s.householdService.Authorize(*input.UserID, profile.HouseholdID, models.HouseholdRoleViewer); err != nil {
+			if errors.Is(err, ErrHouseholdNotFound) {
+				return newValidationError("userId must be a member of the household")
+			}
+			return err
+		}
+		profile.UserID = input.UserID
+	} else {
+		profile.UserID = nil
+	}
+
+	allergies, err := cleanStringList("allergies", input.Allergies, 50)
+	if err != nil {
+		return err
+	}
+	diets, err := cleanStringList("diets", input.Diets, 20)
+	if err != nil {
+		return err
+	}
+	dislikes, err := cleanStringList("dislikes", input.Dislikes, 100)
+	if err != nil {
+		return err
+	}
+
+	profile.Name = name
+	profile.AgeGroup = ageGroup
+	profile.IsGuest = input.IsGuest
+	profile.PortionMultiplier = portion
+	profile.Allergies = allergies
+	profile.Diets = diets
+	profile.Dislikes = dislikes
+	return nil
+}
+
+// cleanStringList trims and de-duplicates (case-insensitively) a list of
+// user-supplied strings, enforcing a maximum number of entries
+func cleanStringList(field string, values []string, max int) (models.StringList, error) {
+	cleaned := make(models.StringList, 0, len(values))
+	seen := make(map[string]bool)
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if len(v) > 100 {
+			return nil, newValidationError("%s entries must be at most 100 characters", field)
+		}
+		key := strings.ToLower(v)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, v)
+	}
+	if len(cleaned) > max {
+		return nil, newValidationError("%s can have at most %d entries", field, max)
+	}
+	return cleaned, nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"fmt"
+)
+
+// ValidationError reports invalid input supplied by the caller
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// newValidationError creates a ValidationError with a formatted message
+func newValidationError(format string, args ...interface{}) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/pagination"
+	"github.com/meal-planner/backend/internal/repository"
+)
+
+// FavoritePagination is the pagination allowlist for the favorites feed
+var FavoritePagination = pagination.Config{
+	Sortable: map[string]string{
+		"favoritedAt": "favorites.created_at",
+	},
+	DefaultSort: "-favoritedAt",
+	KeyColumn:   "favorites.recipe_id",
+}
+
+type FavoriteService interface {
+	// Add and Remove are idempotent and return the recipe's favorite count
+	Add(userID, recipeID string) (int, error)
+	Remove(userID, recipeID string) (int, error)
+	List(userID string, page *pagination.Params) ([]models.Favorite, *pagination.Pagination, error)
+}
+
+type favoriteService struct {
+	favoriteRepo  repository.FavoriteRepository
+	recipeService RecipeService
+}
+
+func NewFavoriteService(favoriteRepo repository.FavoriteRepository, recipeService RecipeService) FavoriteService {
+	return &favoriteService{
+		favoriteRepo:  favoriteRepo,
+		recipeService: recipeService,
+	}
+}
+
+func (s *favoriteService) Add(userID, recipeID string) (int, error) {
+	// Only recipes the user can see may be favorited
+	if _, err := s.recipeService.Get(userID, recipeID); err != nil {
+		return 0, err
+	}
+	return s.favoriteRepo.Add(userID, recipeID)
+}
+
+func (s *favoriteService) Remove(userID, recipeID string) (int, error) {
+	// Removing is allowed even if the recipe has since become private
+	return s.favoriteRepo.Remove(userID, recipeID)
+}
+
+func (s *favoriteService) List(userID string, page *pagination.Params) ([]models.Favorite, *pagination.Pagination, error) {
+	filter, err := s.recipeService.VisibleFilter(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	favorites, result, err := s.favoriteRepo.ListByUser(userID, filter, page)
+	if err != nil {
+		return nil, nil, err
+	}
+	var recipes []*models.Recipe
+	for i := range favorites {
+		if favorites[i].Recipe != nil {
+			favorites[i].Recipe.IsFavorite = true
+			recipes = append(recipes, favorites[i].Recipe)
+		}
+	}
+	if err := s.recipeService.WarnAllergens(userID, recipes); err != nil {
+		return nil, nil, err
+	}
+	return favorites, result, nil
+}
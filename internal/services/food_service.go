@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/foods"
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/repository"
+)
+
+var ErrFoodNotFound = errors.New("food not found")
+
+// Food search result limits
+const (
+	DefaultFoodSearchLimit = 20
+	MaxFoodSearchLimit     = 100
+)
+
+type FoodService interface {
+	// Search finds foods by name for choosing an ingredient's food by hand
+	Search(query string, limit int) ([]models.Food, error)
+	Get(id string) (*models.Food, error)
+
+	// ComputeNutrition links the recipe's ingredients to foods and computes
+	// its per-serving nutrition. Foods chosen by hand are kept; other
+	// ingredients are matched by name. When no ingredient can be weighed the
+	// recipe's hand-entered nutrition is left as it is.
+	ComputeNutrition(recipe *models.Recipe) error
+}
+
+type foodService struct {
+	foodRepo repository.FoodRepository
+}
+
+func NewFoodService(foodRepo repository.FoodRepository) FoodService {
+	return &foodService{foodRepo: foodRepo}
+}
+
+func (s *foodService) Search(query string, limit int) ([]models.Food, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, newValidationError("q is required")
+	}
+	if limit <= 0 {
+		limit = DefaultFoodSearchLimit
+	}
+	if limit > MaxFoodSearchLimit {
+		limit = MaxFoodSearchLimit
+	}
+	return s.foodRepo.Search(query, limit)
+}
+
+func (s *foodService) Get(id string) (*models.Food, error) {
+	food, err := s.foodRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if food == nil {
+		return nil, ErrFoodNotFound
+	}
+	return food, nil
+}
+
+func (s *foodService) ComputeNutrition(recipe *models.Recipe) error {
+	var manualIDs []string
+	for _, ing := range recipe.Ingredients {
+		if ing.FoodMatch == models.FoodMatchManual {
+			manualIDs = append(manualIDs, ing.FoodID)
+		}
+	}
+	matched, err := s.foodRepo.FindByIDs(manualIDs)
+	if err != nil {
+		return err
+	}
+
+	// Ingredients named alike share one lookup
+	byName := make(map[string]*models.Food)
+	for i := range recipe.Ingredients {
+		ing := &recipe.Ingredients[i]
+		if ing.FoodMatch == models.FoodMatchManual {
+			if matched[ing.FoodID] == nil {
+				return newValidationError("ingredient %q: food %s not found", ing.Name, ing.FoodID)
+			}
+			continue
+		}
+
+		ing.FoodID, ing.FoodMatch = "", ""
+		if ing.Quantity <= 0 {
+			continue
+		}
+		key := strings.ToLower(ing.Name)
+		food, seen := byName[key]
+		if !seen {
+			candidates, err := s.foodRepo.Candidates(foods.SearchTerms(ing.Name))
+			if err != nil {
+				return err
+			}
+			best, _ := foods.BestMatch(ing.Name, candidates)
+			if best != nil {
+				food = best
+			}
+			byName[key] = food
+		}
+		if food != nil {
+			ing.FoodID, ing.FoodMatch = food.ID, models.FoodMatchAuto
+			matched[food.ID] = food
+		}
+	}
+
+	result := foods.Compute(recipe.Ingredients, matched, recipe.Servings)
+	recipe.NutritionComputed = result.Weighed > 0
+	recipe.NutritionCoverage = result.Coverage
+	recipe.NutritionConfidence = result.Confidence
+	if recipe.NutritionComputed {
+		recipe.Nutrition = result.Nutrition
+	}
+	return nil
+}
@@ -0,0 +1,473 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/meal-planner/backend/internal/config"
+	"github.com/meal-planner/backend/internal/locale"
+	"github.com/meal-planner/backend/internal/mailer"
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/repository"
+	"github.com/meal-planner/backend/internal/utils"
+)
+
+const InvitationTTL = 7 * 24 * time.Hour
+
+var (
+	ErrHouseholdNotFound        = errors.New("household not found")
+	ErrHouseholdAccessDenied    = errors.New("you do not have access to this household")
+	ErrHouseholdMemberNotFound  = errors.New("household member not found")
+	ErrAlreadyHouseholdMember   = errors.New("user is already a member of this household")
+	ErrOwnerCannotLeave         = errors.New("the owner must transfer ownership before leaving the household")
+	ErrInvitationNotFound       = errors.New("invitation not found")
+	ErrInvitationNotPending     = errors.New("invitation is no longer pending")
+	ErrInvitationExpired        = errors.New("invitation has expired")
+	ErrInvitationAlreadyPending = errors.New("an invitation for this email is already pending")
+)
+
+type HouseholdService interface {
+	Create(userID, name string) (*models.Household, error)
+	ListForUser(userID string) ([]models.Household, error)
+	Get(userID, householdID string) (*models.Household, error)
+	Rename(userID, householdID, name string) (*models.Household, error)
+	Delete(userID, householdID string) error
+
+	Invite(userID, householdID, email string, role models.HouseholdRole) (*models.HouseholdInvitation, error)
+	ListInvitations(userID, householdID string) ([]models.HouseholdInvitation, error)
+	RevokeInvitation(userID, householdID, invitationID string) error
+	ListMyInvitations(userID string) ([]models.HouseholdInvitation, error)
+	RespondToInvitation(userID, invitationID string, accept bool) (*models.HouseholdInvitation, error)
+
+	ChangeMemberRole(userID, householdID, memberID string, role models.HouseholdRole) (*models.HouseholdMember, error)
+	RemoveMember(userID, householdID, memberID string) error
+	Leave(userID, householdID string) error
+	TransferOwnership(userID, householdID, newOwnerID string) (*models.Household, error)
+	Activate(userID, householdID string) (string, error)
+
+	// Authorize checks that the user belongs to the household with at least
+	// the given role. Features scoped to a household (meal plans, recipe
+	// collections, shopping lists) use it to guard access.
+	Authorize(userID, householdID string, minRole models.HouseholdRole) (*models.HouseholdMember, error)
+}
+
+type householdService struct {
+	householdRepo repository.HouseholdRepository
+	userRepo      repository.UserRepository
+	mailer        mailer.Mailer
+	config        *config.Config
+}
+
+func NewHouseholdService(householdRepo repository.HouseholdRepository, userRepo repository.UserRepository, m mailer.Mailer, cfg *config.Config) HouseholdService {
+	return &householdService{
+		householdRepo: householdRepo,
+		userRepo:      userRepo,
+		mailer:        m,
+		config:        cfg,
+	}
+}
+
+func (s *householdService) Create(userID, name string) (*models.Household, error) {
+	name, err := validateHouseholdName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	household := &models.Household{
+		Name:    name,
+		OwnerID: userID,
+	}
+	owner := &models.HouseholdMember{
+		UserID: userID,
+		Role:   models.HouseholdRoleOwner,
+	}
+	if err := s.householdRepo.Create(household, owner); err != nil {
+		return nil, err
+	}
+
+	return s.householdRepo.FindByID(household.ID)
+}
+
+func (s *householdService) ListForUser(userID string) ([]models.Household, error) {
+	return s.householdRepo.FindByUser(userID)
+}
+
+func (s *householdService) Get(userID, householdID string) (*models.Household, error) {
+	if _, err := s.Authorize(userID, householdID, models.HouseholdRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.findHousehold(householdID)
+}
+
+func (s *householdService) Rename(userID, householdID, name string) (*models.Household, error) {
+	if _, err := s.Authorize(userID, householdID, models.HouseholdRoleOwner); err != nil {
+		return nil, err
+	}
+
+	name, err := validateHouseholdName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	household, err := s.findHousehold(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	household.Name = name
+	if err := s.householdRepo.Update(household); err != nil {
+		return nil, err
+	}
+	return household, nil
+}
+
+func (s *householdService) Delete(userID, householdID string) error {
+	if _, err := s.Authorize(userID, householdID, models.HouseholdRoleOwner); err != nil {
+		return err
+	}
+	return s.householdRepo.Delete(householdID)
+}
+
+func (s *householdService) Invite(userID, householdID, email string, role models.HouseholdRole) (*models.HouseholdInvitation, error) {
+	if _, err := s.Authorize(userID, householdID, models.HouseholdRoleOwner); err != nil {
+		return nil, err
+	}
+
+	email = repository.NormalizeEmail(email)
+	if err := utils.ValidateEmail(email); err != nil {
+		return nil, &ValidationError{Message: err.Error()}
+	}
+	if role == "" {
+		role = models.HouseholdRoleMember
+	}
+	if !role.IsValid() || role == models.HouseholdRoleOwner {
+		return nil, newValidationError("role must be one of: member, viewer")
+	}
+
+	household, err := s.findHousehold(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject invitations for people who already belong to the household
+	for _, member := range household.Members {
+		if member.User != nil && strings.EqualFold(member.User.Email, email) {
+			return nil, ErrAlreadyHouseholdMember
+		}
+	}
+
+	existing, err := s.householdRepo.FindPendingInvitation(householdID, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && !existing.IsExpired() {
+		return nil, ErrInvitationAlreadyPending
+	}
+
+	invitation := &models.HouseholdInvitation{
+		HouseholdID: householdID,
+		Email:       email,
+		Role:        role,
+		InvitedByID: userID,
+		Status:      models.InvitationPending,
+		ExpiresAt:   time.Now().UTC().Add(InvitationTTL),
+	}
+	if err := s.householdRepo.CreateInvitation(invitation); err != nil {
+		return nil, err
+	}
+
+	s.sendInvitationEmail(household, invitation)
+	return invitation, nil
+}
+
+func (s *householdService) ListInvitations(userID, householdID string) ([]models.HouseholdInvitation, error) {
+	if _, err := s.Authorize(userID, householdID, models.HouseholdRoleOwner); err != nil {
+		return nil, err
+	}
+	return s.householdRepo.FindInvitationsByHousehold(householdID)
+}
+
+func (s *householdService) RevokeInvitation(userID, householdID, invitationID string) error {
+	if _, err := s.Authorize(userID, householdID, models.HouseholdRoleOwner); err != nil {
+		return err
+	}
+
+	invitation, err := s.householdRepo.FindInvitationByID(invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation == nil || invitation.HouseholdID != householdID {
+		return ErrInvitationNotFound
+	}
+	if invitation.Status != models.InvitationPending {
+		return ErrInvitationNotPending
+	}
+
+	invitation.Status = models.InvitationRevoked
+	return s.householdRepo.UpdateInvitation(invitation)
+}
+
+func (s *householdService) ListMyInvitations(userID string) ([]models.HouseholdInvitation, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.householdRepo.FindInvitationsByEmail(user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]models.HouseholdInvitation, 0, len(invitations))
+	for _, invitation := range invitations {
+		if !invitation.IsExpired() {
+			pending = append(pending, invitation)
+		}
+	}
+	return pending, nil
+}
+
+func (s *householdService) RespondToInvitation(userID, invitationID string, accept bool) (*models.HouseholdInvitation, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.householdRepo.FindInvitationByID(invitationID)
+	if err != nil {
+		return nil, err
+	}
+	// Invitations are only visible to the address they were sent to
+	if invitation == nil || !strings.EqualFold(invitation.Email, user.Email) {
+		return nil, ErrInvitationNotFound
+	}
+	if invitation.Status != models.InvitationPending {
+		return nil, ErrInvitationNotPending
+	}
+	if invitation.IsExpired() {
+		return nil, ErrInvitationExpired
+	}
+
+	now := time.Now().UTC()
+	invitation.RespondedAt = &now
+
+	if !accept {
+		invitation.Status = models.InvitationDeclined
+		if err := s.householdRepo.UpdateInvitation(invitation); err != nil {
+			return nil, err
+		}
+		return invitation, nil
+	}
+
+	existing, err := s.householdRepo.FindMember(invitation.HouseholdID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrAlreadyHouseholdMember
+	}
+
+	invitation.Status = models.InvitationAccepted
+	member := &models.HouseholdMember{
+		HouseholdID: invitation.HouseholdID,
+		UserID:      userID,
+		Role:        invitation.Role,
+	}
+	if err := s.householdRepo.AcceptInvitation(invitation, member); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (s *householdService) ChangeMemberRole(userID, householdID, memberID string, role models.HouseholdRole) (*models.HouseholdMember, error) {
+	if _, err := s.Authorize(userID, householdID, models.HouseholdRoleOwner); err != nil {
+		return nil, err
+	}
+	if !role.IsValid() || role == models.HouseholdRoleOwner {
+		return nil, newValidationError("role must be one of: member, viewer (use transfer to change the owner)")
+	}
+
+	member, err := s.householdRepo.FindMember(householdID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrHouseholdMemberNotFound
+	}
+	if member.Role == models.HouseholdRoleOwner {
+		return nil, newValidationError("the owner's role can only change through an ownership transfer")
+	}
+
+	member.Role = role
+	if err := s.householdRepo.UpdateMember(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *householdService) RemoveMember(userID, householdID, memberID string) error {
+	if userID == memberID {
+		return s.Leave(userID, householdID)
+	}
+	if _, err := s.Authorize(userID, householdID, models.HouseholdRoleOwner); err != nil {
+		return err
+	}
+
+	member, err := s.householdRepo.FindMember(householdID, memberID)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return ErrHouseholdMemberNotFound
+	}
+	return s.householdRepo.RemoveMember(householdID, memberID)
+}
+
+func (s *householdService) Leave(userID, householdID string) error {
+	member, err := s.Authorize(userID, householdID, models.HouseholdRoleViewer)
+	if err != nil {
+		return err
+	}
+	if member.Role == models.HouseholdRoleOwner {
+		return ErrOwnerCannotLeave
+	}
+	return s.householdRepo.RemoveMember(householdID, userID)
+}
+
+func (s *householdService) TransferOwnership(userID, householdID, newOwnerID string) (*models.Household, error) {
+	if _, err := s.Authorize(userID, householdID, models.HouseholdRoleOwner); err != nil {
+		return nil, err
+	}
+	if newOwnerID == userID {
+		return nil, newValidationError("you already own this household")
+	}
+
+	member, err := s.householdRepo.FindMember(householdID, newOwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrHouseholdMemberNotFound
+	}
+
+	if err := s.householdRepo.TransferOwnership(householdID, userID, newOwnerID); err != nil {
+		return nil, err
+	}
+	return s.findHousehold(householdID)
+}
+
+// Activate issues a token whose claims select the household as the user's
+// active household
+func (s *householdService) Activate(userID, householdID string) (string, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return "", err
+	}
+	if householdID != "" {
+		if _, err := s.Authorize(userID, householdID, models.HouseholdRoleViewer); err != nil {
+			return "", err
+		}
+	}
+	return utils.GenerateTokenWithHousehold(user.ID, user.Email, householdID, s.config.JWTSecret, s.config.GetJWTExpiration())
+}
+
+func (s *householdService) Authorize(userID, householdID string, minRole models.HouseholdRole) (*models.HouseholdMember, error) {
+	if householdID == "" {
+		return nil, ErrHouseholdNotFound
+	}
+
+	member, err := s.householdRepo.FindMember(householdID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		// Don't reveal whether a household the user can't see exists
+		return nil, ErrHouseholdNotFound
+	}
+	if !member.Role.AtLeast(minRole) {
+		return nil, ErrHouseholdAccessDenied
+	}
+	return member, nil
+}
+
+func (s *householdService) findHousehold(householdID string) (*models.Household, error) {
+	household, err := s.householdRepo.FindByID(householdID)
+	if err != nil {
+		return nil, err
+	}
+	if household == nil {
+		return nil, ErrHouseholdNotFound
+	}
+	return household, nil
+}
+
+func (s *householdService) findUser(userID string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// sendInvitationEmail notifies the invitee. Delivery failures are logged but
+// don't fail the invitation, which stays visible in the invitee's inbox.
+func (s *householdService) sendInvitationEmail(household *models.Household, invitation *models.HouseholdInvitation) {
+	inviterName := "A Meal Planner user"
+	if inviter, err := s.userRepo.FindByID(invitation.InvitedByID); err == nil && inviter != nil {
+		inviterName = inviter.Name
+		if inviterName == "" {
+			inviterName = inviter.Email
+		}
+	}
+
+	// Render the expiry in the invitee's own zone and locale when they already have an account
+	timeZone, localeTag := locale.DefaultTimeZone, locale.DefaultLocale
+	if invitee, err := s.userRepo.FindByEmail(invitation.Email); err == nil && invitee != nil && invitee.Preferences != nil {
+		if invitee.Preferences.TimeZone != "" {
+			timeZone = invitee.Preferences.TimeZone
+		}
+		if invitee.Preferences.Locale != "" {
+			localeTag = invitee.Preferences.Locale
+		}
+	}
+	expiresAt := locale.FormatDateTime(invitation.ExpiresAt, localeTag, locale.LoadLocation(timeZone))
+
+	link := fmt.Sprintf("%s/invitations/%s", strings.TrimRight(s.config.FrontendURL, "/"), invitation.ID)
+	msg := mailer.Message{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("%s invited you to join %s", inviterName, household.Name),
+		Body: fmt.Sprintf(
+			"%s has invited you to join the household \"%s\" on Meal Planner as a %s.\n\n"+
+				"Accept or decline the invitation here:\n%s\n\n"+
+				"This invitation expires on %s.\n",
+			inviterName, household.Name, invitation.Role, link, expiresAt,
+		),
+	}
+
+	if err := s.mailer.Send(msg); err != nil {
+		log.Printf("Failed to send household invitation %s: %v", invitation.ID, err)
+	}
+}
+
+func validateHouseholdName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", newValidationError("name is required")
+	}
+	if len(name) > 255 {
+		return "", newValidationError("name must be at most 255 characters")
+	}
+	// Names appear in email subjects, where a line break would start a new
+	// header
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return "", newValidationError("name must not contain control characters")
+	}
+	return name, nil
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/meal-planner/backend/internal/imaging"
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/repository"
+	"github.com/meal-planner/backend/internal/storage"
+)
+
+// Recipe image upload limits
+const (
+	MaxImageBytes        = 10 << 20
+	MaxImagesPerUpload   = 10
+	MaxImagesPerRecipe   = 20
+	imageVariantMimeType = "image/jpeg"
+)
+
+var ErrImageNotFound = errors.New("image not found")
+
+// ImageUpload is one uploaded file
+type ImageUpload struct {
+	Filename string
+	Data     []byte
+}
+
+// ImageService manages the uploaded images of recipes. Each upload is
+// stored in the sizes of imaging.Variants; only the recipe's editors may
+// upload, delete or choose the primary image.
+type ImageService interface {
+	// Upload processes and stores images, appending them to the recipe. If
+	// any file is not a usable image nothing is stored.
+	Upload(userID, recipeID string, uploads []ImageUpload) ([]models.RecipeImage, error)
+	List(userID, recipeID string) ([]models.RecipeImage, error)
+	// SetPrimary makes an image the one shown for the recipe and returns
+	// the recipe's images
+	SetPrimary(userID, recipeID, imageID string) ([]models.RecipeImage, error)
+	Delete(userID, recipeID, imageID string) error
+}
+
+type imageService struct {
+	imageRepo     repository.RecipeImageRepository
+	recipeService RecipeService
+	store         storage.Store
+}
+
+func NewImageService(imageRepo repository.RecipeImageRepository, recipeService RecipeService, store storage.Store) ImageService {
+	return &imageService{
+		imageRepo:     imageRepo,
+		recipeService: recipeService,
+		store:         store,
+	}
+}
+
+func (s *imageService) Upload(userID, recipeID string, uploads []ImageUpload) ([]models.RecipeImage, error) {
+	if _, err := s.recipeService.GetEditable(userID, recipeID); err != nil {
+		return nil, err
+	}
+	if len(uploads) == 0 {
+		return nil, newValidationError("at least one image is required")
+	}
+	if len(uploads) > MaxImagesPerUpload {
+		return nil, newValidationError("at most %d images can be uploaded at once", MaxImagesPerUpload)
+	}
+	// Checked again when the images are saved; this check avoids
+	// processing files that could not be kept
+	count, err := s.imageRepo.Count(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if int(count)+len(uploads) > MaxImagesPerRecipe {
+		return nil, newValidationError("a recipe can have at most %d images", MaxImagesPerRecipe)
+	}
+
+	// Process every file before storing any, so one bad file fails the
+	// whole upload
+	results := make([]*imaging.Result, len(uploads))
+	for i, upload := range uploads {
+		if len(upload.Data) > MaxImageBytes {
+			return nil, newValidationError("%s: images must be at most %d MB", upload.Filename, MaxImageBytes>>20)
+		}
+		result, err := imaging.Process(upload.Data, imaging.Variants)
+		if errors.Is(err, imaging.ErrUnsupported) {
+			return nil, newValidationError("%s: images must be JPEG, PNG or GIF", upload.Filename)
+		}
+		if errors.Is(err, imaging.ErrTooLarge) {
+			return nil, newValidationError("%s: images must be at most %d megapixels", upload.Filename, imaging.MaxPixels/1_000_000)
+		}
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+
+	images := make([]models.RecipeImage, 0, len(results))
+	var stored []string
+	for _, result := range results {
+		image, keys, err := s.putVariants(recipeID, userID, result)
+		stored = append(stored, keys...)
+		if err != nil {
+			s.deleteBlobs(stored)
+			return nil, err
+		}
+		images = append(images, *image)
+	}
+	if err := s.imageRepo.Create(recipeID, images, MaxImagesPerRecipe); err != nil {
+		s.deleteBlobs(stored)
+		if errors.Is(err, repository.ErrTooManyImages) {
+			return nil, newValidationError("a recipe can have at most %d images", MaxImagesPerRecipe)
+		}
+		return nil, err
+	}
+	return images, nil
+}
+
+func (s *imageService) List(userID, recipeID string) ([]models.RecipeImage, error) {
+	if _, err := s.recipeService.Get(userID, recipeID); err != nil {
+		return nil, err
+	}
+	return s.imageRepo.ListByRecipe(recipeID)
+}
+
+func (s *imageService) SetPrimary(userID, recipeID, imageID string) ([]models.RecipeImage, error) {
+	if _, err := s.findEditable(userID, recipeID, imageID); err != nil {
+		return nil, err
+	}
+	if err := s.imageRepo.SetPrimary(recipeID, imageID); err != nil {
+		return nil, err
+	}
+	return s.imageRepo.ListByRecipe(recipeID)
+}
+
+func (s *imageService) Delete(userID, recipeID, imageID string) error {
+	image, err := s.findEditable(userID, recipeID, imageID)
+	if err != nil {
+		return err
+	}
+	if err := s.imageRepo.Delete(image); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(image.Keys))
+	for _, key := range image.Keys {
+		keys = append(keys, key)
+	}
+	s.deleteBlobs(keys)
+	return nil
+}
+
+// findEditable loads an image of a recipe the user may modify
+func (s *imageService) findEditable(userID, recipeID, imageID string) (*models.RecipeImage, error) {
+	if _, err := s.recipeService.GetEditable(userID, recipeID); err != nil {
+		return nil, err
+	}
+	image, err := s.imageRepo.FindByID(recipeID, imageID)
+	if err != nil {
+		return nil, err
+	}
+	if image == nil {
+		return nil, ErrImageNotFound
+	}
+	return image, nil
+}
+
+// putVariants stores the variants of a processed image under a random
+// name, so image URLs cannot be guessed and never need cache busting. The
+// keys written are returned even on failure so they can be cleaned up.
+func (s *imageService) putVariants(recipeID, userID string, result *imaging.Result) (*models.RecipeImage, []string, error) {
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return nil, nil, err
+	}
+
+	image := &models.RecipeImage{
+		UploadedByID: userID,
+		Width:        result.Width,
+		Height:       result.Height,
+		BlurHash:     result.BlurHash,
+		URLs:         models.StringMap{},
+		Keys:         models.StringMap{},
+	}
+	var keys []string
+	for _, variant := range result.Variants {
+		key := fmt.Sprintf("recipes/%s/%s-%s.jpg", recipeID, hex.EncodeToString(name), variant.Name)
+		if err := s.store.Put(key, bytes.NewReader(variant.Data), imageVariantMimeType); err != nil {
+			return nil, keys, err
+		}
+		keys = append(keys, key)
+		image.Keys[variant.Name] = key
+		image.URLs[variant.Name] = s.store.URL(key)
+	}
+	return image, keys, nil
+}
+
+// deleteBlobs removes stored files. Failures are logged but not returned;
+// an orphaned file is harmless.
+func (s *imageService) deleteBlobs(keys []string) {
+	for _, key := range keys {
+		if err := s.store.Delete(key); err != nil {
+			log.Printf("Failed to delete image %s: %v", key, err)
+		}
+	}
+}
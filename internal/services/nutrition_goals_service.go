@@ -0,0 +1,210 @@
+package services
+
+import (
+	"math"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/nutrition"
+	"github.com/meal-planner/backend/internal/repository"
+)
+
+// NutritionGoalsInput holds the fields accepted when setting nutrition goals.
+// Unset fields fall back to the preset (maintenance by default); calories
+// are estimated from Metrics when given and not set explicitly.
+type NutritionGoalsInput struct {
+	Preset      string
+	Calories    *int
+	MacroUnit   string
+	Protein     *float64
+	Carbs       *float64
+	Fat         *float64
+	Fiber       *float64
+	SodiumLimit *float64
+	SugarLimit  *float64
+	Metrics     *nutrition.BodyMetrics
+}
+
+type NutritionGoalsService interface {
+	GetGoals(userID string) (*models.NutritionGoals, bool, error)
+	UpdateGoals(userID string, input *NutritionGoalsInput) (*models.NutritionGoals, error)
+	Estimate(metrics nutrition.BodyMetrics, preset string) (*nutrition.Targets, error)
+	CompareWithGoals(userID string, totals nutrition.Totals) ([]nutrition.Comparison, error)
+}
+
+type nutritionGoalsService struct {
+	goalsRepo repository.NutritionGoalsRepository
+}
+
+func NewNutritionGoalsService(goalsRepo repository.NutritionGoalsRepository) NutritionGoalsService {
+	return &nutritionGoalsService{
+		goalsRepo: goalsRepo,
+	}
+}
+
+// GetGoals returns the user's goals, or maintenance defaults when none are
+// stored. The boolean reports whether the goals were explicitly set.
+func (s *nutritionGoalsService) GetGoals(userID string) (*models.NutritionGoals, bool, error) {
+	goals, err := s.goalsRepo.FindByUser(userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if goals != nil {
+		return goals, true, nil
+	}
+
+	preset, _ := nutrition.FindPreset("maintenance")
+	goals = goalsFromTargets(preset.Targets(0))
+	goals.UserID = userID
+	goals.Preset = preset.Key
+	goals.MacroUnit = models.MacroUnitPercent
+	return goals, false, nil
+}
+
+func (s *nutritionGoalsService) UpdateGoals(userID string, input *NutritionGoalsInput) (*models.NutritionGoals, error) {
+	presetKey := strings.TrimSpace(input.Preset)
+	if presetKey == "" {
+		presetKey = "maintenance"
+	}
+	preset, ok := nutrition.FindPreset(presetKey)
+	if !ok {
+		return nil, &ValidationError{Message: nutrition.ErrUnknownPreset.Error()}
+	}
+
+	// Calories: explicit value, else estimated from body metrics, else the preset default
+	var targets *nutrition.Targets
+	if input.Metrics != nil {
+		if err := input.Metrics.Validate(); err != nil {
+			return nil, &ValidationError{Message: err.Error()}
+		}
+		targets = preset.Targets(nutrition.TDEE(*input.Metrics))
+	} else {
+		targets = preset.Targets(0)
+	}
+	if input.Calories != nil {
+		if *input.Calories < 800 || *input.Calories > 10000 {
+			return nil, newValidationError("calories must be between 800 and 10000")
+		}
+		targets.Calories = *input.Calories
+		targets.SetMacroPercents(targets.ProteinPercent, targets.CarbsPercent, targets.FatPercent)
+	}
+
+	macroUnit := strings.ToLower(strings.TrimSpace(input.MacroUnit))
+	if macroUnit == "" {
+		macroUnit = models.MacroUnitPercent
+	}
+	if input.Protein != nil || input.Carbs != nil || input.Fat != nil {
+		if input.Protein == nil || input.Carbs == nil || input.Fat == nil {
+			return nil, newValidationError("protein, carbs and fat must be set together")
+		}
+		protein, carbs, fat := *input.Protein, *input.Carbs, *input.Fat
+		if protein < 0 || carbs < 0 || fat < 0 {
+			return nil, newValidationError("macro targets must not be negative")
+		}
+
+		switch macroUnit {
+		case models.MacroUnitPercent:
+			if math.Abs(protein+carbs+fat-100) > 1 {
+				return nil, newValidationError("macro percentages must add up to 100")
+			}
+			targets.SetMacroPercents(protein, carbs, fat)
+		case models.MacroUnitGrams:
+			targets.SetMacroGrams(protein, carbs, fat)
+		default:
+			return nil, newValidationError("macroUnit must be one of: grams, percent")
+		}
+	} else if macroUnit != models.MacroUnitPercent && macroUnit != models.MacroUnitGrams {
+		return nil, newValidationError("macroUnit must be one of: grams, percent")
+	}
+
+	if input.Fiber != nil {
+		if *input.Fiber < 0 || *input.Fiber > 150 {
+			return nil, newValidationError("fiber must be between 0 and 150 grams")
+		}
+		targets.FiberGrams = *input.Fiber
+	}
+	if input.SodiumLimit != nil {
+		if *input.SodiumLimit < 0 || *input.SodiumLimit > 10000 {
+			return nil, newValidationError("sodiumLimit must be between 0 and 10000 mg")
+		}
+		targets.SodiumLimitMg = *input.SodiumLimit
+	}
+	if input.SugarLimit != nil {
+		if *input.SugarLimit < 0 || *input.SugarLimit > 500 {
+			return nil, newValidationError("sugarLimit must be between 0 and 500 grams")
+		}
+		targets.SugarLimitGrams = *input.SugarLimit
+	}
+
+	goals := goalsFromTargets(targets)
+	goals.UserID = userID
+	goals.Preset = preset.Key
+	goals.MacroUnit = macroUnit
+	if m := input.Metrics; m != nil {
+		goals.Age = &m.Age
+		goals.Sex = &m.Sex
+		goals.HeightCm = &m.HeightCm
+		goals.WeightKg = &m.WeightKg
+		goals.ActivityLevel = &m.ActivityLevel
+	}
+
+	if err := s.goalsRepo.Save(goals); err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+func (s *nutritionGoalsService) Estimate(metrics nutrition.BodyMetrics, preset string) (*nutrition.Targets, error) {
+	if preset == "" {
+		preset = "maintenance"
+	}
+	targets, err := nutrition.Estimate(metrics, preset)
+	if err != nil {
+		return nil, &ValidationError{Message: err.Error()}
+	}
+	return targets, nil
+}
+
+// CompareWithGoals compares a day's nutrient totals with the user's targets
+func (s *nutritionGoalsService) CompareWithGoals(userID string, totals nutrition.Totals) ([]nutrition.Comparison, error) {
+	for _, v := range []float64{totals.Calories, totals.Protein, totals.Carbs, totals.Fat, totals.Fiber, totals.Sodium, totals.Sugar} {
+		if v < 0 {
+			return nil, newValidationError("nutrient totals must not be negative")
+		}
+	}
+	goals, _, err := s.GetGoals(userID)
+	if err != nil {
+		return nil, err
+	}
+	return nutrition.Compare(totals, targetsFromGoals(goals)), nil
+}
+
+func goalsFromTargets(t *nutrition.Targets) *models.NutritionGoals {
+	return &models.NutritionGoals{
+		Calories:        t.Calories,
+		ProteinGrams:    t.ProteinGrams,
+		CarbsGrams:      t.CarbsGrams,
+		FatGrams:        t.FatGrams,
+		ProteinPercent:  t.ProteinPercent,
+		CarbsPercent:    t.CarbsPercent,
+		FatPercent:      t.FatPercent,
+		FiberGrams:      t.FiberGrams,
+		SodiumLimitMg:   t.SodiumLimitMg,
+		SugarLimitGrams: t.SugarLimitGrams,
+	}
+}
+
+func targetsFromGoals(g *models.NutritionGoals) *nutrition.Targets {
+	return &nutrition.Targets{
+		Calories:        g.Calories,
+		ProteinGrams:    g.ProteinGrams,
+		CarbsGrams:      g.CarbsGrams,
+		FatGrams:        g.FatGrams,
+		ProteinPercent:  g.ProteinPercent,
+		CarbsPercent:    g.CarbsPercent,
+		FatPercent:      g.FatPercent,
+		FiberGrams:      g.FiberGrams,
+		SodiumLimitMg:   g.SodiumLimitMg,
+		SugarLimitGrams: g.SugarLimitGrams,
+	}
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/repository"
+)
+
+var (
+	ErrOnboardingStepNotFound     = errors.New("onboarding step not found")
+	ErrOnboardingStepNotSkippable = errors.New("onboarding step cannot be skipped")
+)
+
+type OnboardingService interface {
+	GetProgress(userID string) (*models.OnboardingState, error)
+	SubmitStep(userID, stepKey string, data map[string]interface{}) (*models.OnboardingState, error)
+	SkipStep(userID, stepKey string) (*models.OnboardingState, error)
+	CompleteAll(userID string) (*models.User, error)
+}
+
+type onboardingService struct {
+	userRepo       repository.UserRepository
+	onboardingRepo repository.OnboardingRepository
+}
+
+func NewOnboardingService(userRepo repository.UserRepository, onboardingRepo repository.OnboardingRepository) OnboardingService {
+	return &onboardingService{
+		userRepo:       userRepo,
+		onboardingRepo: onboardingRepo,
+	}
+}
+
+func (s *onboardingService) GetProgress(userID string) (*models.OnboardingState, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	progress, _, err := s.findProgress(user)
+	if err != nil {
+		return nil, err
+	}
+	return buildOnboardingState(progress), nil
+}
+
+func (s *onboardingService) SubmitStep(userID, stepKey string, data map[string]interface{}) (*models.OnboardingState, error) {
+	step, ok := findOnboardingStep(stepKey)
+	if !ok {
+		return nil, ErrOnboardingStepNotFound
+	}
+
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cleaned, err := validateStepData(step, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if step.apply != nil {
+		if user.Preferences == nil {
+			user.Preferences = &models.UserPreferences{Theme: "light", Notifications: true}
+		}
+		step.apply(user.Preferences, cleaned)
+	}
+	record := models.OnboardingProgress{
+		UserID:  userID,
+		StepKey: step.Key,
+		Status:  models.OnboardingStepCompleted,
+		Data:    cleaned,
+	}
+	return s.save(user, []models.OnboardingProgress{record}, step.apply != nil)
+}
+
+func (s *onboardingService) SkipStep(userID, stepKey string) (*models.OnboardingState, error) {
+	step, ok := findOnboardingStep(stepKey)
+	if !ok {
+		return nil, ErrOnboardingStepNotFound
+	}
+	if !step.Skippable {
+		return nil, ErrOnboardingStepNotSkippable
+	}
+
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	record := models.OnboardingProgress{
+		UserID:  userID,
+		StepKey: step.Key,
+		Status:  models.OnboardingStepSkipped,
+	}
+	return s.save(user, []models.OnboardingProgress{record}, false)
+}
+
+// CompleteAll skips every step the user has not resolved yet. It backs the
+// legacy POST /api/auth/onboarding/complete endpoint.
+func (s *onboardingService) CompleteAll(userID string) (*models.User, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, _, err := s.findProgress(user)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[string]bool)
+	for i := range progress {
+		resolved[progress[i].StepKey] = progress[i].IsResolved()
+	}
+
+	var records []models.OnboardingProgress
+	for _, step := range onboardingSteps {
+		if resolved[step.Key] {
+			continue
+		}
+		records = append(records, models.OnboardingProgress{
+			UserID:  userID,
+			StepKey: step.Key,
+			Status:  models.OnboardingStepSkipped,
+		})
+	}
+
+	if _, err := s.save(user, records, false); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *onboardingService) findUser(userID string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// findProgress loads the user's step records. Users who finished the old
+// single-flag onboarding have none; every step counts as skipped for them,
+// so they are not sent through it again, and legacy reports that the
+// records were made up rather than loaded.
+func (s *onboardingService) findProgress(user *models.User) (progress []models.OnboardingProgress, legacy bool, err error) {
+	progress, err = s.onboardingRepo.FindByUser(user.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(progress) > 0 || !user.HasCompletedOnboarding {
+		return progress, false, nil
+	}
+	for _, step := range onboardingSteps {
+		progress = append(progress, models.OnboardingProgress{
+			UserID:  user.ID,
+			StepKey: step.Key,
+			Status:  models.OnboardingStepSkipped,
+		})
+	}
+	return progress, true, nil
+}
+
+// save stores step records together with the user's HasCompletedOnboarding
+// flag, kept in line with the resulting state, in one transaction, and
+// returns that state. userChanged saves the user even when the flag stays
+// the same, for steps that set preferences.
+func (s *onboardingService) save(user *models.User, records []models.OnboardingProgress, userChanged bool) (*models.OnboardingState, error) {
+	progress, legacy, err := s.findProgress(user)
+	if err != nil {
+		return nil, err
+	}
+	// The steps a legacy user counts as skipped are stored with the first
+	// change, or the change would be all that is left of their onboarding
+	if legacy {
+		records = mergeProgress(progress, records)
+	}
+	state := buildOnboardingState(mergeProgress(progress, records))
+
+	if user.HasCompletedOnboarding != state.HasCompletedOnboarding {
+		user.HasCompletedOnboarding = state.HasCompletedOnboarding
+		userChanged = true
+	}
+	var changedUser *models.User
+	if userChanged {
+		changedUser = user
+	}
+	if err := s.onboardingRepo.Save(records, changedUser); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// mergeProgress returns progress with the records of updates replacing
+// those of the same step
+func mergeProgress(progress, updates []models.OnboardingProgress) []models.OnboardingProgress {
+	merged := make([]models.OnboardingProgress, 0, len(progress)+len(updates))
+	replaced := make(map[string]bool, len(updates))
+	for _, u := range updates {
+		replaced[u.StepKey] = true
+	}
+	for _, p := range progress {
+		if !replaced[p.StepKey] {
+			merged = append(merged, p)
+		}
+	}
+	return append(merged, updates...)
+}
+
+// buildOnboardingState derives onboarding progress from the recorded steps.
+// Onboarding is complete once every defined step is completed or skipped.
+func buildOnboardingState(progress []models.OnboardingProgress) *models.OnboardingState {
+	byKey := make(map[string]*models.OnboardingProgress, len(progress))
+	for i := range progress {
+		byKey[progress[i].StepKey] = &progress[i]
+	}
+
+	state := &models.OnboardingState{
+		Steps:      make([]models.OnboardingStepState, 0, len(onboardingSteps)),
+		TotalSteps: len(onboardingSteps),
+	}
+
+	for _, step := range onboardingSteps {
+		stepState := models.OnboardingStepState{
+			OnboardingStep: step.OnboardingStep,
+			Status:         models.OnboardingStepPending,
+		}
+
+		if record, ok := byKey[step.Key]; ok && record.IsResolved() {
+			stepState.Status = record.Status
+			stepState.Data = record.Data
+			if record.Status == models.OnboardingStepCompleted {
+				completedAt := record.UpdatedAt.UTC()
+				if completedAt.IsZero() {
+					completedAt = time.Now().UTC()
+				}
+				stepState.CompletedAt = &completedAt
+			}
+			state.ResolvedSteps++
+		} else if state.CurrentStep == "" {
+			state.CurrentStep = step.Key
+		}
+
+		state.Steps = append(state.Steps, stepState)
+	}
+
+	state.HasCompletedOnboarding = state.ResolvedSteps == state.TotalSteps
+	return state
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/models"
+)
+
+// onboardingStepDefinition is a server-side onboarding step. Steps are served
+// to clients together with their field schema, so new steps can be added here
+// without a frontend release.
+type onboardingStepDefinition struct {
+	models.OnboardingStep
+
+	// apply copies validated step data onto the user's preferences (optional)
+	apply func(prefs *models.UserPreferences, data models.JSONMap)
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+// onboardingSteps lists the onboarding flow in the order it is presented
+var onboardingSteps = []onboardingStepDefinition{
+	{
+		OnboardingStep: models.OnboardingStep{
+			Key:         "diet_allergies",
+			Title:       "Diet & allergies",
+			Description: "Tell us about any diets you follow and foods you must avoid.",
+			Skippable:   true,
+			Fields: []models.OnboardingField{
+				{
+					Name:    "dietary",
+					Label:   "Dietary preferences",
+					Type:    "multiselect",
+					Options: []string{"Vegetarian", "Vegan", "Pescatarian", "Keto", "Paleo", "Gluten-Free", "Dairy-Free", "Low-Carb"},
+				},
+				{
+					Name:        "allergies",
+					Label:       "Allergies",
+					Type:        "multiselect",
+					Options:     []string{"Peanuts", "Tree Nuts", "Dairy", "Eggs", "Soy", "Wheat", "Fish", "Shellfish", "Sesame"},
+					AllowCustom: true,
+				},
+			},
+		},
+		apply: func(prefs *models.UserPreferences, data models.JSONMap) {
+			prefs.Dietary = stringListField(data, "dietary")
+			prefs.Allergies = stringListField(data, "allergies")
+		},
+	},
+	{
+		OnboardingStep: models.OnboardingStep{
+			Key:         "household",
+			Title:       "Your household",
+			Description: "How many people are you usually cooking for?",
+			Skippable:   true,
+			Fields: []models.OnboardingField{
+				{
+					Name:     "householdSize",
+					Label:    "Household size",
+					Type:     "number",
+					Required: true,
+					Min:      floatPtr(1),
+					Max:      floatPtr(20),
+					Integer:  true,
+				},
+				{
+					Name:    "cookingSkill",
+					Label:   "Cooking skill",
+					Type:    "select",
+					Options: []string{"Beginner", "Intermediate", "Advanced"},
+				},
+			},
+		},
+		apply: func(prefs *models.UserPreferences, data models.JSONMap) {
+			if size, ok := data["householdSize"].(float64); ok {
+				prefs.HouseholdSize = int(size)
+			}
+			if skill, ok := data["cookingSkill"].(string); ok {
+				prefs.CookingSkill = skill
+			}
+		},
+	},
+	{
+		OnboardingStep: models.OnboardingStep{
+			Key:         "goals",
+			Title:       "Your goals",
+			Description: "What would you like meal planning to help you with?",
+			Skippable:   true,
+			Fields: []models.OnboardingField{
+				{
+					Name:     "goals",
+					Label:    "Goals",
+					Type:     "multiselect",
+					Required: true,
+					Options:  []string{"Eat healthier", "Lose weight", "Build muscle", "Save time", "Save money", "Reduce food waste", "Try new recipes"},
+				},
+			},
+		},
+		apply: func(prefs *models.UserPreferences, data models.JSONMap) {
+			prefs.Goals = stringListField(data, "goals")
+		},
+	},
+	{
+		OnboardingStep: models.OnboardingStep{
+			Key:         "cuisines",
+			Title:       "Favorite cuisines",
+			Description: "Pick the cuisines you enjoy most.",
+			Skippable:   true,
+			Fields: []models.OnboardingField{
+				{
+					Name:        "favoriteCuisines",
+					Label:       "Favorite cuisines",
+					Type:        "multiselect",
+					Required:    true,
+					Options:     []string{"American", "Chinese", "French", "Greek", "Indian", "Italian", "Japanese", "Korean", "Mediterranean", "Mexican", "Middle Eastern", "Thai", "Vietnamese"},
+					AllowCustom: true,
+				},
+			},
+		},
+		apply: func(prefs *models.UserPreferences, data models.JSONMap) {
+			prefs.FavoriteCuisines = stringListField(data, "favoriteCuisines")
+		},
+	},
+	{
+		OnboardingStep: models.OnboardingStep{
+			Key:         "first_plan",
+			Title:       "Your first plan",
+			Description: "Choose which meals to plan and when your week starts.",
+			Skippable:   true,
+			Fields: []models.OnboardingField{
+				{
+					Name:     "meals",
+					Label:    "Meals to plan",
+					Type:     "multiselect",
+					Required: true,
+					Options:  []string{"Breakfast", "Lunch", "Dinner", "Snacks"},
+				},
+				{
+					Name:    "weekStart",
+					Label:   "Week starts on",
+					Type:    "select",
+					Options: []string{"Sunday", "Monday"},
+				},
+			},
+		},
+		apply: func(prefs *models.UserPreferences, data models.JSONMap) {
+			prefs.PlannedMeals = stringListField(data, "meals")
+			if day, ok := data["weekStart"].(string); ok {
+				prefs.WeekStart = strings.ToLower(day)
+			}
+		},
+	},
+}
+
+// findOnboardingStep returns the step definition for the given key
+func findOnboardingStep(key string) (*onboardingStepDefinition, bool) {
+	for i := range onboardingSteps {
+		if onboardingSteps[i].Key == key {
+			return &onboardingSteps[i], true
+		}
+	}
+	return nil, false
+}
+
+// validateStepData checks a step payload against the step's field schema and
+// returns the cleaned data, dropping fields the step does not declare
+func validateStepData(step *onboardingStepDefinition, data map[string]interface{}) (models.JSONMap, error) {
+	cleaned := models.JSONMap{}
+
+	for _, field := range step.Fields {
+		value, present := data[field.Name]
+		if !present || value == nil || isEmptyValue(value) {
+			if field.Required {
+				return nil, newValidationError("%s is required", field.Name)
+			}
+			continue
+		}
+
+		switch field.Type {
+		case "text":
+			s, ok := value.(string)
+			if !ok {
+				return nil, newValidationError("%s must be a string", field.Name)
+			}
+			cleaned[field.Name] = strings.TrimSpace(s)
+
+		case "number":
+			n, ok := value.(float64)
+			if !ok || math.IsNaN(n) {
+				return nil, newValidationError("%s must be a number", field.Name)
+			}
+			if field.Integer && n != math.Trunc(n) {
+				return nil, newValidationError("%s must be a whole number", field.Name)
+			}
+			if field.Min != nil && n < *field.Min {
+				return nil, newValidationError("%s must be at least %v", field.Name, *field.Min)
+			}
+			if field.Max != nil && n > *field.Max {
+				return nil, newValidationError("%s must be at most %v", field.Name, *field.Max)
+			}
+			cleaned[field.Name] = n
+
+		case "select":
+			s, ok := value.(string)
+			if !ok {
+				return nil, newValidationError("%s must be a string", field.Name)
+			}
+			option, err := matchOption(field, s)
+			if err != nil {
+				return nil, err
+			}
+			cleaned[field.Name] = option
+
+		case "multiselect":
+			items, ok := value.([]interface{})
+			if !ok {
+				return nil, newValidationError("%s must be a list", field.Name)
+			}
+			values := make([]string, 0, len(items))
+			seen := make(map[string]bool)
+			for _, item := range items {
+				s, ok := item.(string)
+				if !ok {
+					return nil, newValidationError("%s must be a list of strings", field.Name)
+				}
+				option, err := matchOption(field, s)
+				if err != nil {
+					return nil, err
+				}
+				if option == "" || seen[strings.ToLower(option)] {
+					continue
+				}
+				seen[strings.ToLower(option)] = true
+				values = append(values, option)
+			}
+			if field.Required && len(values) == 0 {
+				return nil, newValidationError("%s is required", field.Name)
+			}
+			cleaned[field.Name] = values
+
+		default:
+			return nil, fmt.Errorf("unsupported onboarding field type %q", field.Type)
+		}
+	}
+
+	return cleaned, nil
+}
+
+// matchOption resolves a submitted value against a field's options,
+// case-insensitively, allowing free text when the field permits it
+func matchOption(field models.OnboardingField, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	for _, option := range field.Options {
+		if strings.EqualFold(option, value) {
+			return option, nil
+		}
+	}
+	if field.AllowCustom {
+		if len(value) > 100 {
+			return "", newValidationError("%s values must be at most 100 characters", field.Name)
+		}
+		return value, nil
+	}
+	return "", newValidationError("%q is not a valid option for %s", value, field.Name)
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// stringListField reads a validated multiselect value from step data
+func stringListField(data models.JSONMap, name string) models.StringList {
+	switch v := data[name].(type) {
+	case []string:
+		return models.StringList(v)
+	case []interface{}:
+		list := make(models.StringList, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return models.StringList{}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/meal-planner/backend/internal/models"
+)
+
+func TestValidateStepData(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    string
+		data    map[string]interface{}
+		want    models.JSONMap
+		wantErr string
+	}{
+		{
+			name: "valid household",
+			step: "household",
+			data: map[string]interface{}{"householdSize": 2.0, "cookingSkill": "beginner"},
+			want: models.JSONMap{"householdSize": 2.0, "cookingSkill": "Beginner"},
+		},
+		{
+			name:    "fractional household size",
+			step:    "household",
+			data:    map[string]interface{}{"householdSize": 2.5},
+			wantErr: "householdSize must be a whole number",
+		},
+		{
+			name:    "household size out of range",
+			step:    "household",
+			data:    map[string]interface{}{"householdSize": 21.0},
+			wantErr: "householdSize must be at most 20",
+		},
+		{
+			name:    "household size not a number",
+			step:    "household",
+			data:    map[string]interface{}{"householdSize": "two"},
+			wantErr: "householdSize must be a number",
+		},
+		{
+			name:    "missing required field",
+			step:    "household",
+			data:    map[string]interface{}{"cookingSkill": "Advanced"},
+			wantErr: "householdSize is required",
+		},
+		{
+			name:    "unknown option",
+			step:    "household",
+			data:    map[string]interface{}{"householdSize": 1.0, "cookingSkill": "Chef"},
+			wantErr: `"Chef" is not a valid option for cookingSkill`,
+		},
+		{
+			name: "multiselect drops repeats and undeclared fields",
+			step: "first_plan",
+			data: map[string]interface{}{
+				"meals":   []interface{}{"dinner", "Dinner", "Lunch"},
+				"unknown": true,
+			},
+			want: models.JSONMap{"meals": []string{"Dinner", "Lunch"}},
+		},
+		{
+			name:    "multiselect of blanks",
+			step:    "first_plan",
+			data:    map[string]interface{}{"meals": []interface{}{}},
+			wantErr: "meals is required",
+		},
+		{
+			name: "custom value where allowed",
+			step: "diet_allergies",
+			data: map[string]interface{}{"allergies": []interface{}{"Kiwi"}},
+			want: models.JSONMap{"allergies": []string{"Kiwi"}},
+		},
+		{
+			name:    "multiselect of non-strings",
+			step:    "diet_allergies",
+			data:    map[string]interface{}{"dietary": []interface{}{1.0}},
+			wantErr: "dietary must be a list of strings",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := findOnboardingStep(tt.step)
+			if !ok {
+				t.Fatalf("step %q not found", tt.step)
+			}
+			got, err := validateStepData(step, tt.data)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("validateStepData() error = %v, wantErr %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateStepData() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateStepData() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/pantry"
+	"github.com/meal-planner/backend/internal/repository"
+)
+
+// Pantry and cookable search limits
+const (
+	MaxPantryItems      = 200
+	maxPantryItemLength = 100
+
+	DefaultCookableLimit = 20
+	MaxCookableLimit     = 50
+	// maxCookableCandidates bounds the recipes loaded and matched per
+	// search; the candidates most likely to be well covered are kept
+	maxCookableCandidates = 1000
+)
+
+// CookableParams holds the options of a cookable search
+type CookableParams struct {
+	// Ingredients are the items on hand. When empty, or with UsePantry,
+	// the user's pantry is used.
+	Ingredients []string
+	UsePantry   bool
+	// AssumeStaples counts staples such as salt, oil and water as on hand
+	AssumeStaples bool
+	// MaxMissing drops recipes missing more ingredients; nil keeps all
+	MaxMissing *int
+	Limit      int
+	Filters    RecipeListParams
+}
+
+// PantryService keeps the ingredients users have on hand and finds recipes
+// they can cook with them
+type PantryService interface {
+	Get(userID string) ([]string, error)
+	// Update replaces the user's pantry. Items are trimmed and repeats,
+	// ignoring case, are dropped.
+	Update(userID string, items []string) ([]string, error)
+
+	// Cookable returns the recipes the user can see that use what they
+	// have on hand, by coverage of their required ingredients, each with
+	// the ingredients still missing. Recipes that use nothing on hand are
+	// left out.
+	Cookable(userID string, params CookableParams) ([]models.CookableRecipe, error)
+}
+
+type pantryService struct {
+	recipeRepo    repository.RecipeRepository
+	userRepo      repository.UserRepository
+	recipeService RecipeService
+}
+
+func NewPantryService(recipeRepo repository.RecipeRepository, userRepo repository.UserRepository, recipeService RecipeService) PantryService {
+	return &pantryService{
+		recipeRepo:    recipeRepo,
+		userRepo:      userRepo,
+		recipeService: recipeService,
+	}
+}
+
+func (s *pantryService) Get(userID string) ([]string, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	items := []string{}
+	if user.Preferences != nil && user.Preferences.Pantry != nil {
+		items = user.Preferences.Pantry
+	}
+	return items, nil
+}
+
+func (s *pantryService) Update(userID string, items []string) ([]string, error) {
+	cleaned, err := cleanPantryItems(items)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Preferences == nil {
+		user.Preferences = &models.UserPreferences{}
+	}
+	user.Preferences.Pantry = cleaned
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return cleaned, nil
+}
+
+// cleanPantryItems trims items and drops blanks and repeats
+func cleanPantryItems(items []string) ([]string, error) {
+	cleaned := []string{}
+	seen := make(map[string]bool)
+	for _, item := range items {
+		item = strings.Join(strings.Fields(item), " ")
+		if item == "" || seen[strings.ToLower(item)] {
+			continue
+		}
+		if len(item) > maxPantryItemLength {
+			return nil, newValidationError("pantry items must be at most %d characters", maxPantryItemLength)
+		}
+		seen[strings.ToLower(item)] = true
+		cleaned = append(cleaned, item)
+	}
+	if len(cleaned) > MaxPantryItems {
+		return nil, newValidationError("pantry can have at most %d items", MaxPantryItems)
+	}
+	return cleaned, nil
+}
+
+func (s *pantryService) Cookable(userID string, params CookableParams) ([]models.CookableRecipe, error) {
+	if params.Limit < 1 || params.Limit > MaxCookableLimit {
+		return nil, newValidationError("limit must be between 1 and %d", MaxCookableLimit)
+	}
+	if params.MaxMissing != nil && *params.MaxMissing < 0 {
+		return nil, newValidationError("maxMissing must not be negative")
+	}
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := cleanPantryItems(params.Ingredients)
+	if err != nil {
+		return nil, err
+	}
+	if (len(items) == 0 || params.UsePantry) && user.Preferences != nil {
+		items = append(items, user.Preferences.Pantry...)
+	}
+	if len(items) == 0 {
+		return nil, newValidationError("list the ingredients you have or add them to your pantry")
+	}
+	filter, err := s.recipeService.ListFilter(userID, params.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	onHand := pantry.New(items, params.AssumeStaples)
+	candidates, err := s.recipeRepo.WithIngredients(onHand.SearchTerms(), filter, maxCookableCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []models.CookableRecipe{}
+	for _, recipe := range candidates {
+		match := onHand.Match(recipe.Ingredients)
+		if match.Matched == 0 {
+			continue
+		}
+		if params.MaxMissing != nil && len(match.Missing) > *params.MaxMissing {
+			continue
+		}
+		results = append(results, models.CookableRecipe{
+			Recipe:             recipe,
+			Coverage:           match.Coverage,
+			MatchedCount:       match.Matched,
+			RequiredCount:      match.Required,
+			MissingIngredients: match.Missing,
+		})
+	}
+
+	// Best covered first; then fewest to buy, then best rated
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Coverage != b.Coverage {
+			return a.Coverage > b.Coverage
+		}
+		if len(a.MissingIngredients) != len(b.MissingIngredients) {
+			return len(a.MissingIngredients) < len(b.MissingIngredients)
+		}
+		return a.BayesianRating > b.BayesianRating
+	})
+	if len(results) > params.Limit {
+		results = results[:params.Limit]
+	}
+
+	refs := make([]*models.Recipe, len(results))
+	for i := range results {
+		refs[i] = &results[i].Recipe
+	}
+	if err := s.recipeService.MarkForViewer(userID, refs); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *pantryService) getUser(userID string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
@@ -0,0 +1,1055 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/allergens"
+	"github.com/meal-planner/backend/internal/ingredients"
+	"github.com/meal-planner/backend/internal/locale"
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/pagination"
+	"github.com/meal-planner/backend/internal/recipeimport"
+	"github.com/meal-planner/backend/internal/repository"
+	"github.com/meal-planner/backend/internal/revisions"
+	"github.com/meal-planner/backend/internal/search"
+	"github.com/meal-planner/backend/internal/units"
+)
+
+// UnitsOriginal asks GetInUnits for quantities as written in the recipe
+const UnitsOriginal = "original"
+
+var (
+	ErrRecipeNotFound         = errors.New("recipe not found")
+	ErrRecipeForbidden        = errors.New("you do not have permission to modify this recipe")
+	ErrRecipeRevisionConflict = errors.New("recipe was changed since it was loaded")
+	ErrRevisionNotFound       = errors.New("revision not found")
+)
+
+// RecipePagination is the pagination and sort allowlist for recipe listings.
+// Sorting by rating uses the Bayesian rating; averageRating sorts by the raw
+// average.
+var RecipePagination = pagination.Config{
+	Sortable: map[string]string{
+		"featured":      "is_featured",
+		"name":          "name",
+		"rating":        "bayesian_rating",
+		"averageRating": "rating",
+		"reviewCount":   "review_count",
+		"totalTime":     "total_time",
+		"createdAt":     "created_at",
+	},
+	DefaultSort: "-featured,-createdAt",
+}
+
+// RecipeSearchPagination is the pagination and sort allowlist for recipe
+// search. Results are ranked by relevance, so only page-based paging works.
+var RecipeSearchPagination = pagination.Config{
+	Sortable: map[string]string{
+		"relevance": "rank",
+		"name":      "name",
+		"rating":    "bayesian_rating",
+		"createdAt": "created_at",
+	},
+	DefaultSort: "-relevance,-rating",
+	OffsetOnly:  true,
+}
+
+// RecipeInput holds the editable fields of a recipe
+type RecipeInput struct {
+	Name         string
+	Description  string
+	Category     string
+	Cuisine      string
+	PrepTime     int
+	CookTime     int
+	Servings     int
+	Difficulty   string
+	Ingredients  []models.RecipeIngredient
+	Instructions []string
+	Nutrition    models.RecipeNutrition
+	Tags         []string
+	ImageURL     string
+
+	// AllergenOverrides correct the allergens detected from ingredients
+	AllergenOverrides models.AllergenOverrides
+
+	// Only admins may publish or feature recipes
+	IsPublic   *bool
+	IsFeatured *bool
+
+	// ChangeSummary describes an edit in the recipe's history; when empty
+	// one is generated from the changed fields
+	ChangeSummary string
+	// BaseRevision is the revision the edit was made from. When set, the
+	// update is rejected if the recipe was revised since.
+	BaseRevision *int
+}
+
+// RecipeListParams holds the query options for listing recipes
+type RecipeListParams struct {
+	Categories   []string
+	Cuisines     []string
+	Difficulties []string
+	MaxTotalTime int
+	Tags         []string
+	TagMatch     string // "all" (default) or "any"
+	MinCalories  *float64
+	MaxCalories  *float64
+
+	// Filters derived from the caller's stored preferences. Recipes that
+	// conflict with the caller's allergies are listed with allergenWarnings
+	// unless ExcludeMyAllergens drops them.
+	ExcludeMyAllergens bool
+	MatchMyDiet        bool
+
+	Mine bool
+}
+
+type RecipeService interface {
+	List(userID string, params RecipeListParams, page *pagination.Params) ([]models.Recipe, *pagination.Pagination, error)
+	Get(userID, recipeID string) (*models.Recipe, error)
+	// GetEditable returns a recipe the user may modify, for features that
+	// change a recipe outside of Update, such as image uploads
+	GetEditable(userID, recipeID string) (*models.Recipe, error)
+
+	// GetInUnits returns a recipe with ingredient quantities rendered in a
+	// unit system, "metric" or "us", or as written for "original". An empty
+	// system returns them as written, since a fetched recipe may be edited
+	// and saved back and converted quantities are rounded. The system used
+	// is returned.
+	GetInUnits(userID, recipeID, system string) (*models.Recipe, string, error)
+
+	// Scale returns a recipe with its ingredients scaled to servings and
+	// rendered in a unit system as GetInUnits does. Counted items like eggs
+	// are rounded sensibly and amounts "to taste" are left alone.
+	Scale(userID, recipeID string, servings int, system string) (*models.ScaledRecipe, string, error)
+
+	// ScaleForSlot scales a recipe for a meal-plan slot, using the slot's
+	// servings override when it has one and the pinned revision of the
+	// recipe when the slot pins one
+	ScaleForSlot(userID string, slot models.MealSlot, system string) (*models.ScaledRecipe, string, error)
+
+	// Search finds recipes matching the query, best matches first. See
+	// search.Query for the supported syntax.
+	Search(userID, query string, params RecipeListParams, page *pagination.Params) ([]models.RecipeSearchResult, *pagination.Pagination, error)
+
+	// Facets returns per-value counts for the filters in params
+	Facets(userID string, params RecipeListParams) (*models.RecipeFacets, error)
+	Create(userID string, input *RecipeInput) (*models.Recipe, error)
+	Update(userID, recipeID string, input *RecipeInput) (*models.Recipe, error)
+	Delete(userID, recipeID string) error
+
+	// Fork copies a public recipe, or one of the user's own, into a new
+	// private recipe of the user that links back to the original
+	Fork(userID, recipeID string) (*models.Recipe, error)
+
+	// Restore makes the recipe's fields those of an earlier revision. The
+	// restore is recorded as a new revision, so history is never rewritten.
+	Restore(userID, recipeID string, number int) (*models.Recipe, error)
+
+	// ImportDraft extracts a schema.org recipe from an HTML page. Nothing is
+	// saved; the draft is returned for the user to review and create.
+	ImportDraft(page io.Reader) (*recipeimport.Result, error)
+
+	// WarnAllergens sets AllergenWarnings on recipes loaded elsewhere, such
+	// as the favorites feed, from the user's stored allergies
+	WarnAllergens(userID string, recipes []*models.Recipe) error
+
+	// VisibleFilter returns the filter limiting recipes loaded elsewhere,
+	// such as the favorites feed, to those the user can see
+	VisibleFilter(userID string) (repository.RecipeFilter, error)
+
+	// ListFilter returns the filter for a listing made elsewhere, such as
+	// similar recipes, as List builds it from params
+	ListFilter(userID string, params RecipeListParams) (repository.RecipeFilter, error)
+
+	// MarkForViewer sets IsFavorite and AllergenWarnings on recipes loaded
+	// elsewhere, as listings do
+	MarkForViewer(userID string, recipes []*models.Recipe) error
+}
+
+type recipeService struct {
+	recipeRepo   repository.RecipeRepository
+	revisionRepo repository.RecipeRevisionRepository
+	userRepo     repository.UserRepository
+	favoriteRepo repository.FavoriteRepository
+	foodService  FoodService
+}
+
+func NewRecipeService(recipeRepo repository.RecipeRepository, revisionRepo repository.RecipeRevisionRepository, userRepo repository.UserRepository, favoriteRepo repository.FavoriteRepository, foodService FoodService) RecipeService {
+	return &recipeService{
+		recipeRepo:   recipeRepo,
+		revisionRepo: revisionRepo,
+		userRepo:     userRepo,
+		favoriteRepo: favoriteRepo,
+		foodService:  foodService,
+	}
+}
+
+func (s *recipeService) List(userID string, params RecipeListParams, page *pagination.Params) ([]models.Recipe, *pagination.Pagination, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter, err := listFilter(user, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recipes, result, err := s.recipeRepo.List(filter, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refs := make([]*models.Recipe, len(recipes))
+	for i := range recipes {
+		refs[i] = &recipes[i]
+	}
+	if err := s.markFavorites(userID, refs); err != nil {
+		return nil, nil, err
+	}
+	markAllergenWarnings(user, refs)
+	return recipes, result, nil
+}
+
+func (s *recipeService) Search(userID, query string, params RecipeListParams, page *pagination.Params) ([]models.RecipeSearchResult, *pagination.Pagination, error) {
+	parsed := search.Parse(query)
+	if parsed.IsEmpty() {
+		return nil, nil, newValidationError("search query must contain at least one word")
+	}
+	if !parsed.HasIncluded() {
+		return nil, nil, newValidationError("search query must contain at least one word that is not excluded")
+	}
+	if len(parsed.Terms) > 20 {
+		return nil, nil, newValidationError("search query can have at most 20 terms")
+	}
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter, err := listFilter(user, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results, result, err := s.recipeRepo.Search(parsed.TSQuery(), parsed.HighlightTSQuery(), filter, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refs := make([]*models.Recipe, len(results))
+	for i := range results {
+		results[i].Highlights.Name = escapeHighlight(results[i].Highlights.Name)
+		results[i].Highlights.Description = escapeHighlight(results[i].Highlights.Description)
+		refs[i] = &results[i].Recipe
+	}
+	if err := s.markFavorites(userID, refs); err != nil {
+		return nil, nil, err
+	}
+	markAllergenWarnings(user, refs)
+	return results, result, nil
+}
+
+// markFavorites sets IsFavorite on the recipes with a single query
+func (s *recipeService) markFavorites(userID string, recipes []*models.Recipe) error {
+	ids := make([]string, len(recipes))
+	for i, recipe := range recipes {
+		ids[i] = recipe.ID
+	}
+
+	favorited, err := s.favoriteRepo.FavoritedRecipeIDs(userID, ids)
+	if err != nil {
+		return err
+	}
+	for _, recipe := range recipes {
+		recipe.IsFavorite = favorited[recipe.ID]
+	}
+	return nil
+}
+
+func (s *recipeService) Facets(userID string, params RecipeListParams) (*models.RecipeFacets, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := listFilter(user, params)
+	if err != nil {
+		return nil, err
+	}
+	return s.recipeRepo.Facets(filter)
+}
+
+// listFilter builds the repository filter for a listing, limiting regular
+// users to public recipes and their own
+func listFilter(user *models.User, params RecipeListParams) (repository.RecipeFilter, error) {
+	if params.MaxTotalTime < 0 {
+		return repository.RecipeFilter{}, newValidationError("maxTotalTime must not be negative")
+	}
+	if (params.MinCalories != nil && *params.MinCalories < 0) || (params.MaxCalories != nil && *params.MaxCalories < 0) {
+		return repository.RecipeFilter{}, newValidationError("calories must not be negative")
+	}
+	if params.MinCalories != nil && params.MaxCalories != nil && *params.MinCalories > *params.MaxCalories {
+		return repository.RecipeFilter{}, newValidationError("minCalories must not exceed maxCalories")
+	}
+	tagMatch := strings.ToLower(params.TagMatch)
+	if tagMatch != "" && tagMatch != "all" && tagMatch != "any" {
+		return repository.RecipeFilter{}, newValidationError("tagMatch must be one of: all, any")
+	}
+
+	filter := repository.RecipeFilter{
+		Categories:   params.Categories,
+		Cuisines:     params.Cuisines,
+		Difficulties: params.Difficulties,
+		MaxTotalTime: params.MaxTotalTime,
+		Tags:         params.Tags,
+		MatchAnyTag:  tagMatch == "any",
+		MinCalories:  params.MinCalories,
+		MaxCalories:  params.MaxCalories,
+	}
+	if prefs := user.Preferences; prefs != nil {
+		if params.MatchMyDiet {
+			filter.RequiredTags = prefs.Dietary
+		}
+		if params.ExcludeMyAllergens {
+			for _, allergy := range prefs.Allergies {
+				if group, ok := allergens.Normalize(allergy); ok {
+					filter.ExcludedAllergens = append(filter.ExcludedAllergens, group)
+				} else if term := allergenTerm(allergy); term != "" {
+					filter.ExcludedIngredients = append(filter.ExcludedIngredients, term)
+				}
+			}
+		}
+	}
+	filter.ViewerID = viewerID(user)
+	if params.Mine {
+		filter.CreatedByID = user.ID
+	}
+	return filter, nil
+}
+
+func (s *recipeService) Get(userID, recipeID string) (*models.Recipe, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := s.findVisible(user, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.markFavorites(userID, []*models.Recipe{recipe}); err != nil {
+		return nil, err
+	}
+	markAllergenWarnings(user, []*models.Recipe{recipe})
+	return recipe, nil
+}
+
+func (s *recipeService) GetEditable(userID, recipeID string) (*models.Recipe, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.findEditable(user, recipeID)
+}
+
+func (s *recipeService) GetInUnits(userID, recipeID, system string) (*models.Recipe, string, error) {
+	recipe, err := s.Get(userID, recipeID)
+	if err != nil {
+		return nil, "", err
+	}
+	if system == "" {
+		return recipe, UnitsOriginal, nil
+	}
+	if system, err = s.renderUnits(userID, recipe, system); err != nil {
+		return nil, "", err
+	}
+	return recipe, system, nil
+}
+
+func (s *recipeService) Scale(userID, recipeID string, servings int, system string) (*models.ScaledRecipe, string, error) {
+	return s.ScaleForSlot(userID, models.MealSlot{RecipeID: recipeID, Servings: &servings}, system)
+}
+
+func (s *recipeService) ScaleForSlot(userID string, slot models.MealSlot, system string) (*models.ScaledRecipe, string, error) {
+	recipe, err := s.Get(userID, slot.RecipeID)
+	if err != nil {
+		return nil, "", err
+	}
+	if slot.RevisionID != nil {
+		revision, err := s.revisionRepo.FindByID(*slot.RevisionID)
+		if err != nil {
+			return nil, "", err
+		}
+		if revision == nil || revision.RecipeID != recipe.ID {
+			return nil, "", ErrRevisionNotFound
+		}
+		recipe.ApplySnapshot(*revision.Snapshot)
+		recipe.Revision = revision.Number
+	}
+	servings := slot.ServingsFor(recipe)
+	if servings < 1 || servings > 100 {
+		return nil, "", newValidationError("servings must be between 1 and 100")
+	}
+
+	scaled := scaleRecipe(recipe, servings)
+	if system, err = s.renderUnits(userID, &scaled.Recipe, system); err != nil {
+		return nil, "", err
+	}
+	return scaled, system, nil
+}
+
+// renderUnits converts the recipe's ingredient quantities to the unit system,
+// or to the user's preferred one when system is empty, and returns the
+// system used
+func (s *recipeService) renderUnits(userID string, recipe *models.Recipe, system string) (string, error) {
+	if system == "" {
+		user, err := s.getUser(userID)
+		if err != nil {
+			return "", err
+		}
+		if user.Preferences != nil {
+			system = user.Preferences.UnitSystem
+		}
+	}
+	if system == UnitsOriginal {
+		return system, nil
+	}
+	system, err := locale.NormalizeUnitSystem(system)
+	if err != nil {
+		return "", newValidationError("units must be one of: metric, us, original")
+	}
+
+	for i, ing := range recipe.Ingredients {
+		converted := units.ConvertIngredient(ing.Parsed(), system)
+		ing.Quantity, ing.QuantityMax = converted.Quantity, converted.QuantityMax
+		ing.Unit, ing.Size = converted.Unit, converted.Size
+		recipe.Ingredients[i] = ing
+	}
+	return system, nil
+}
+
+// scaleRecipe scales the recipe's ingredients from its own servings to
+// servings; see units.Scale for how amounts are rounded
+func scaleRecipe(recipe *models.Recipe, servings int) *models.ScaledRecipe {
+	original := recipe.Servings
+	if original < 1 {
+		original = 1
+	}
+	factor := float64(servings) / float64(original)
+
+	scaled := &models.ScaledRecipe{
+		Recipe:           *recipe,
+		OriginalServings: original,
+		ScaleFactor:      factor,
+		NutritionTotal:   recipe.Nutrition.Times(float64(servings)),
+	}
+	scaled.Servings = servings
+	scaled.Ingredients = make(models.RecipeIngredients, len(recipe.Ingredients))
+	for i, ing := range recipe.Ingredients {
+		result := units.Scale(ing.Parsed(), factor)
+		ing.Quantity, ing.QuantityMax, ing.Unit = result.Quantity, result.QuantityMax, result.Unit
+		ing.Grams = math.Round(ing.Grams*factor*10) / 10
+		scaled.Ingredients[i] = ing
+	}
+	return scaled
+}
+
+func (s *recipeService) Create(userID string, input *RecipeInput) (*models.Recipe, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe := &models.Recipe{CreatedByID: &user.ID}
+	if err := applyRecipeInput(recipe, input, user.IsAdmin()); err != nil {
+		return nil, err
+	}
+	if err := s.foodService.ComputeNutrition(recipe); err != nil {
+		return nil, err
+	}
+
+	summary, err := changeSummary(input.ChangeSummary, "Created recipe")
+	if err != nil {
+		return nil, err
+	}
+	snapshot := recipe.Snapshot()
+	revision := &models.RecipeRevision{AuthorID: &user.ID, Summary: summary, Snapshot: &snapshot}
+	if err := s.recipeRepo.Create(recipe, revision); err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
+
+// NewRecipe builds a recipe from input as Create does, without saving it,
+// for bulk tools that validate recipes up front and save them in batches.
+// The caller is trusted to publish and feature recipes.
+func NewRecipe(input *RecipeInput, createdByID *string) (*models.Recipe, error) {
+	recipe := &models.Recipe{CreatedByID: createdByID}
+	if err := applyRecipeInput(recipe, input, true); err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
+
+// InputFromDraft turns an imported draft into the input for creating a
+// recipe. The recipe is private unless the caller sets IsPublic.
+func InputFromDraft(d *recipeimport.Draft) *RecipeInput {
+	return &RecipeInput{
+		Name:         d.Name,
+		Description:  d.Description,
+		Category:     d.Category,
+		Cuisine:      d.Cuisine,
+		PrepTime:     d.PrepTime,
+		CookTime:     d.CookTime,
+		Servings:     d.Servings,
+		Difficulty:   d.Difficulty,
+		Ingredients:  append([]models.RecipeIngredient{}, d.Ingredients...),
+		Instructions: d.Instructions,
+		Nutrition:    d.Nutrition,
+		Tags:         d.Tags,
+		ImageURL:     d.ImageURL,
+	}
+}
+
+func (s *recipeService) Update(userID, recipeID string, input *RecipeInput) (*models.Recipe, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := s.findEditable(user, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if input.BaseRevision != nil && *input.BaseRevision != recipe.Revision {
+		return nil, ErrRecipeRevisionConflict
+	}
+
+	before := recipe.Snapshot()
+	if err := applyRecipeInput(recipe, input, user.IsAdmin()); err != nil {
+		return nil, err
+	}
+	if err := s.foodService.ComputeNutrition(recipe); err != nil {
+		return nil, err
+	}
+
+	after := recipe.Snapshot()
+	changes := revisions.Diff(before, after)
+	// Saving an unchanged recipe records no revision
+	if len(changes) == 0 {
+		return recipe, nil
+	}
+	summary, err := changeSummary(input.ChangeSummary, revisions.Summarize(changes))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.revise(recipe, &models.RecipeRevision{AuthorID: &user.ID, Summary: summary, Snapshot: &after}); err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
+
+func (s *recipeService) Fork(userID, recipeID string) (*models.Recipe, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := s.findVisible(user, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	// Admins can see every recipe, but others' private recipes stay theirs
+	if !original.IsPublic && !original.IsOwnedBy(user.ID) {
+		return nil, ErrRecipeNotFound
+	}
+
+	fork := &models.Recipe{
+		CreatedByID:         &user.ID,
+		ForkedFromID:        &original.ID,
+		ForkedFromRevision:  &original.Revision,
+		NutritionComputed:   original.NutritionComputed,
+		NutritionCoverage:   original.NutritionCoverage,
+		NutritionConfidence: original.NutritionConfidence,
+		// The fork shows the original's image without owning its uploads
+		ImageURL:      original.ImageURL,
+		ImageURLs:     original.ImageURLs,
+		ImageBlurHash: original.ImageBlurHash,
+	}
+	fork.ApplySnapshot(original.Snapshot())
+	fork.IsPublic, fork.IsFeatured = false, false
+
+	snapshot := fork.Snapshot()
+	revision := &models.RecipeRevision{
+		AuthorID: &user.ID,
+		Summary:  "Forked from " + original.Name,
+		Snapshot: &snapshot,
+	}
+	if err := s.recipeRepo.Create(fork, revision); err != nil {
+		return nil, err
+	}
+	return fork, nil
+}
+
+func (s *recipeService) Restore(userID, recipeID string, number int) (*models.Recipe, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, err := s.findEditable(user, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := s.revisionRepo.FindByNumber(recipeID, number)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, ErrRevisionNotFound
+	}
+
+	// Visibility stays as it is for regular users, as it does on update
+	isPublic, isFeatured := recipe.IsPublic, recipe.IsFeatured
+	recipe.ApplySnapshot(*revision.Snapshot)
+	if !user.IsAdmin() {
+		recipe.IsPublic, recipe.IsFeatured = isPublic, isFeatured
+	}
+	if err := s.foodService.ComputeNutrition(recipe); err != nil {
+		return nil, err
+	}
+
+	snapshot := recipe.Snapshot()
+	if err := s.revise(recipe, &models.RecipeRevision{
+		AuthorID:     &user.ID,
+		Summary:      fmt.Sprintf("Restored revision %d", number),
+		RestoredFrom: &revision.Number,
+		Snapshot:     &snapshot,
+	}); err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
+
+// revise saves an edit of the recipe with its revision, failing with
+// ErrRecipeRevisionConflict when another edit was saved first
+func (s *recipeService) revise(recipe *models.Recipe, revision *models.RecipeRevision) error {
+	saved, err := s.recipeRepo.Revise(recipe, revision)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		return ErrRecipeRevisionConflict
+	}
+	return nil
+}
+
+// changeSummary validates a user-written change summary, falling back to a
+// generated one when it is empty
+func changeSummary(summary, generated string) (string, error) {
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return generated, nil
+	}
+	if len(summary) > 500 {
+		return "", newValidationError("changeSummary must be at most 500 characters")
+	}
+	return summary, nil
+}
+
+func (s *recipeService) Delete(userID, recipeID string) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.findEditable(user, recipeID); err != nil {
+		return err
+	}
+	return s.recipeRepo.Delete(recipeID)
+}
+
+func (s *recipeService) ImportDraft(page io.Reader) (*recipeimport.Result, error) {
+	return recipeimport.Parse(page)
+}
+
+func (s *recipeService) WarnAllergens(userID string, recipes []*models.Recipe) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	markAllergenWarnings(user, recipes)
+	return nil
+}
+
+func (s *recipeService) VisibleFilter(userID string) (repository.RecipeFilter, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return repository.RecipeFilter{}, err
+	}
+	return repository.RecipeFilter{ViewerID: viewerID(user)}, nil
+}
+
+func (s *recipeService) ListFilter(userID string, params RecipeListParams) (repository.RecipeFilter, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return repository.RecipeFilter{}, err
+	}
+	return listFilter(user, params)
+}
+
+func (s *recipeService) MarkForViewer(userID string, recipes []*models.Recipe) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.markFavorites(userID, recipes); err != nil {
+		return err
+	}
+	markAllergenWarnings(user, recipes)
+	return nil
+}
+
+// markAllergenWarnings sets AllergenWarnings on the recipes: the allergen
+// groups the user is allergic to, and allergies outside the groups, such as
+// "kiwi", that an ingredient name mentions
+func markAllergenWarnings(user *models.User, recipes []*models.Recipe) {
+	if user.Preferences == nil || len(user.Preferences.Allergies) == 0 {
+		return
+	}
+	allergies := user.Preferences.Allergies
+	for _, recipe := range recipes {
+		warnings := allergens.Conflicts(recipe.Allergens, allergies)
+		for _, allergy := range allergies {
+			if _, ok := allergens.Normalize(allergy); ok {
+				continue
+			}
+			term := allergenTerm(allergy)
+			for _, ing := range recipe.Ingredients {
+				if term != "" && strings.Contains(strings.ToLower(ing.Name), term) {
+					warnings = append(warnings, term)
+					break
+				}
+			}
+		}
+		recipe.AllergenWarnings = warnings
+	}
+}
+
+func (s *recipeService) getUser(userID string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// findVisible loads a recipe the user may read. Private recipes of other
+// users are reported as not found so their existence is not revealed.
+func (s *recipeService) findVisible(user *models.User, recipeID string) (*models.Recipe, error) {
+	recipe, err := s.recipeRepo.FindByID(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if recipe == nil || !canViewRecipe(user, recipe) {
+		return nil, ErrRecipeNotFound
+	}
+	return recipe, nil
+}
+
+// findEditable loads a recipe the user may modify: their own, or any recipe
+// for admins, who curate the public catalog
+func (s *recipeService) findEditable(user *models.User, recipeID string) (*models.Recipe, error) {
+	recipe, err := s.findVisible(user, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin() && !recipe.IsOwnedBy(user.ID) {
+		return nil, ErrRecipeForbidden
+	}
+	return recipe, nil
+}
+
+func canViewRecipe(user *models.User, recipe *models.Recipe) bool {
+	return recipe.IsPublic || recipe.IsOwnedBy(user.ID) || user.IsAdmin()
+}
+
+// viewerID is the RecipeFilter.ViewerID matching canViewRecipe: the user's
+// ID, or empty for admins, who see every recipe
+func viewerID(user *models.User) string {
+	if user.IsAdmin() {
+		return ""
+	}
+	return user.ID
+}
+
+// applyRecipeInput validates the input and copies it onto the recipe.
+// Visibility flags are only honored for admins; recipes created by regular
+// users are always private.
+func applyRecipeInput(recipe *models.Recipe, input *RecipeInput, isAdmin bool) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return newValidationError("name is required")
+	}
+	if len(name) > 255 {
+		return newValidationError("name must be at most 255 characters")
+	}
+
+	category, ok := matchRecipeOption(input.Category, models.RecipeCategories)
+	if !ok {
+		return newValidationError("category must be one of: %s", strings.Join(models.RecipeCategories, ", "))
+	}
+
+	difficulty := "Medium"
+	if strings.TrimSpace(input.Difficulty) != "" {
+		difficulty, ok = matchRecipeOption(input.Difficulty, models.RecipeDifficulties)
+		if !ok {
+			return newValidationError("difficulty must be one of: %s", strings.Join(models.RecipeDifficulties, ", "))
+		}
+	}
+
+	cuisine := strings.TrimSpace(input.Cuisine)
+	if len(cuisine) > 100 {
+		return newValidationError("cuisine must be at most 100 characters")
+	}
+
+	if input.PrepTime < 0 || input.PrepTime > 24*60 {
+		return newValidationError("prepTime must be between 0 and 1440 minutes")
+	}
+	if input.CookTime < 0 || input.CookTime > 7*24*60 {
+		return newValidationError("cookTime must be between 0 and 10080 minutes")
+	}
+
+	servings := input.Servings
+	if servings == 0 {
+		servings = 4
+	}
+	if servings < 1 || servings > 100 {
+		return newValidationError("servings must be between 1 and 100")
+	}
+
+	ingredients, err := cleanRecipeIngredients(input.Ingredients)
+	if err != nil {
+		return err
+	}
+
+	instructions := make(models.StringList, 0, len(input.Instructions))
+	for _, step := range input.Instructions {
+		if step = strings.TrimSpace(step); step != "" {
+			instructions = append(instructions, step)
+		}
+	}
+	if len(instructions) > 100 {
+		return newValidationError("instructions can have at most 100 steps")
+	}
+
+	if err := validateRecipeNutrition(input.Nutrition); err != nil {
+		return err
+	}
+
+	tags, err := cleanStringList("tags", input.Tags, 30)
+	if err != nil {
+		return err
+	}
+
+	contains, err := cleanAllergens("allergenOverrides.contains", input.AllergenOverrides.Contains)
+	if err != nil {
+		return err
+	}
+	freeOf, err := cleanAllergens("allergenOverrides.freeOf", input.AllergenOverrides.FreeOf)
+	if err != nil {
+		return err
+	}
+	if overlap := allergens.Without(contains, allergens.Without(contains, freeOf)); len(overlap) > 0 {
+		return newValidationError("allergen %s cannot be both contained and excluded", overlap[0])
+	}
+
+	recipe.Name = name
+	recipe.Description = strings.TrimSpace(input.Description)
+	recipe.Category = category
+	recipe.Cuisine = cuisine
+	recipe.PrepTime = input.PrepTime
+	recipe.CookTime = input.CookTime
+	recipe.TotalTime = input.PrepTime + input.CookTime
+	recipe.Servings = servings
+	recipe.Difficulty = difficulty
+	recipe.Ingredients = ingredients
+	recipe.Instructions = instructions
+	recipe.Nutrition = input.Nutrition
+	recipe.Tags = tags
+	recipe.AllergenOverrides = models.AllergenOverrides{Contains: contains, FreeOf: freeOf}
+	// The sizes and placeholder of an uploaded image no longer apply once
+	// the image URL is replaced by hand
+	if imageURL := strings.TrimSpace(input.ImageURL); imageURL != recipe.ImageURL {
+		recipe.ImageURL = imageURL
+		recipe.ImageURLs, recipe.ImageBlurHash = nil, ""
+	}
+
+	if isAdmin {
+		if input.IsPublic != nil {
+			recipe.IsPublic = *input.IsPublic
+		}
+		if input.IsFeatured != nil {
+			recipe.IsFeatured = *input.IsFeatured
+		}
+	} else if input.IsPublic != nil && *input.IsPublic && !recipe.IsPublic {
+		return newValidationError("only admins can publish recipes")
+	}
+	if recipe.IsFeatured && !recipe.IsPublic {
+		return newValidationError("only public recipes can be featured")
+	}
+	return nil
+}
+
+// cleanRecipeIngredients validates ingredient lines. A line sent with only a
+// name, such as {"name": "2 cups flour, sifted"}, is parsed into quantity,
+// unit, name and note; units sent explicitly are normalized, and flagged with
+// unknownUnit when they are not recognized. A foodId sent without foodMatch
+// "auto" is a manual food choice; automatic matches are redone on save.
+func cleanRecipeIngredients(input []models.RecipeIngredient) (models.RecipeIngredients, error) {
+	cleaned := make(models.RecipeIngredients, 0, len(input))
+	for _, ing := range input {
+		ing.FoodID = strings.TrimSpace(ing.FoodID)
+		if ing.FoodID != "" && ing.FoodMatch != models.FoodMatchAuto {
+			ing.FoodMatch = models.FoodMatchManual
+		} else {
+			ing.FoodID, ing.FoodMatch = "", ""
+		}
+		ing.Grams = 0
+
+		ing.Name = strings.TrimSpace(ing.Name)
+		ing.Unit = strings.TrimSpace(ing.Unit)
+		ing.Category = strings.TrimSpace(ing.Category)
+		ing.Note = strings.TrimSpace(ing.Note)
+		if ing.Name == "" {
+			if ing.Quantity != 0 || ing.Unit != "" {
+				return nil, newValidationError("ingredient name is required")
+			}
+			continue
+		}
+
+		if ing.Quantity == 0 && ing.Unit == "" && ing.Size == nil {
+			parsed := models.ParseRecipeIngredient(ing.Name)
+			parsed.Category = ing.Category
+			parsed.FoodID, parsed.FoodMatch = ing.FoodID, ing.FoodMatch
+			if ing.Note != "" {
+				parsed.Note = strings.TrimPrefix(parsed.Note+", "+ing.Note, ", ")
+			}
+			if parsed.Name == "" {
+				return nil, newValidationError("ingredient %q has no name", ing.Name)
+			}
+			ing = parsed
+		} else if ing.Unit != "" {
+			unit, ok := ingredients.NormalizeUnit(ing.Unit)
+			if ok {
+				ing.Unit = unit
+			}
+			ing.UnknownUnit = !ok
+		} else {
+			ing.UnknownUnit = false
+		}
+
+		if !validQuantity(ing.Quantity) || !validQuantity(ing.QuantityMax) {
+			return nil, newValidationError("ingredient quantities must be non-negative numbers")
+		}
+		if ing.QuantityMax != 0 && ing.QuantityMax <= ing.Quantity {
+			return nil, newValidationError("ingredient quantityMax must be greater than quantity")
+		}
+		if ing.Size != nil {
+			unit, ok := ingredients.NormalizeUnit(ing.Size.Unit)
+			if !ok || ing.Size.Quantity <= 0 || !validQuantity(ing.Size.Quantity) {
+				return nil, newValidationError("ingredient size needs a positive quantity and a known unit")
+			}
+			ing.Size = &ingredients.Amount{Quantity: ing.Size.Quantity, Unit: unit}
+		}
+		if len(ing.Name) > 255 {
+			return nil, newValidationError("ingredient names must be at most 255 characters")
+		}
+		cleaned = append(cleaned, ing)
+	}
+	if len(cleaned) > 100 {
+		return nil, newValidationError("ingredients can have at most 100 entries")
+	}
+	return cleaned, nil
+}
+
+func validQuantity(q float64) bool {
+	return q >= 0 && !math.IsNaN(q) && !math.IsInf(q, 0)
+}
+
+func validateRecipeNutrition(n models.RecipeNutrition) error {
+	values := map[string]float64{
+		"calories":      n.Calories,
+		"protein":       n.Protein,
+		"carbohydrates": n.Carbohydrates,
+		"fat":           n.Fat,
+		"fiber":         n.Fiber,
+		"sugar":         n.Sugar,
+		"sodium":        n.Sodium,
+		"saturatedFat":  n.SaturatedFat,
+		"cholesterol":   n.Cholesterol,
+		"calcium":       n.Calcium,
+		"iron":          n.Iron,
+		"potassium":     n.Potassium,
+		"vitaminC":      n.VitaminC,
+		"vitaminD":      n.VitaminD,
+	}
+	for name, v := range values {
+		if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return newValidationError("nutrition.%s must be a non-negative number", name)
+		}
+	}
+	return nil
+}
+
+// cleanAllergens normalizes allergen overrides such as "Tree Nuts" to
+// allergen groups
+func cleanAllergens(field string, values []string) ([]string, error) {
+	var groups []string
+	for _, v := range values {
+		group, ok := allergens.Normalize(v)
+		if !ok {
+			return nil, newValidationError("%s must contain only: %s", field, strings.Join(allergens.All, ", "))
+		}
+		groups = append(groups, group)
+	}
+	return allergens.Merge(groups), nil
+}
+
+// allergenTerm turns a stored allergy ("Peanuts") into the term matched
+// against ingredient names ("peanut")
+func allergenTerm(allergy string) string {
+	term := strings.ToLower(strings.TrimSpace(allergy))
+	if len(term) > 3 && strings.HasSuffix(term, "s") {
+		term = strings.TrimSuffix(term, "s")
+	}
+	return term
+}
+
+// escapeHighlight HTML-escapes a ts_headline snippet while keeping the
+// <mark> tags added around matches, so clients can render it as HTML
+func escapeHighlight(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, "&lt;mark&gt;", "<mark>")
+	return strings.ReplaceAll(escaped, "&lt;/mark&gt;", "</mark>")
+}
+
+// matchRecipeOption resolves a value against a fixed list, case-insensitively
+func matchRecipeOption(value string, options []string) (string, bool) {
+	value = strings.TrimSpace(value)
+	for _, option := range options {
+		if strings.EqualFold(option, value) {
+			return option, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/pagination"
+	"github.com/meal-planner/backend/internal/repository"
+)
+
+var (
+	ErrReviewNotFound        = errors.New("review not found")
+	ErrReviewExists          = errors.New("you have already reviewed this recipe")
+	ErrReviewForbidden       = errors.New("you do not have permission to modify this review")
+	ErrCannotReviewOwnRecipe = errors.New("you cannot review your own recipe")
+	ErrCannotVoteOwnReview   = errors.New("you cannot vote on your own review")
+)
+
+// ReviewPagination is the pagination and sort allowlist for recipe reviews
+var ReviewPagination = pagination.Config{
+	Sortable: map[string]string{
+		"createdAt": "created_at",
+		"helpful":   "helpful_count",
+		"rating":    "rating",
+	},
+	DefaultSort: "-createdAt",
+}
+
+// ReviewInput holds the editable fields of a review
+type ReviewInput struct {
+	Rating   int
+	Comment  string
+	PhotoURL string
+}
+
+type ReviewService interface {
+	List(userID, recipeID string, page *pagination.Params) ([]models.Review, *pagination.Pagination, error)
+	Create(userID, recipeID string, input *ReviewInput) (*models.Review, error)
+	Update(userID, recipeID, reviewID string, input *ReviewInput) (*models.Review, error)
+	Delete(userID, recipeID, reviewID string) error
+
+	// Vote and Unvote mark a review as helpful or not, returning its
+	// helpful count. Both are idempotent.
+	Vote(userID, recipeID, reviewID string) (int, error)
+	Unvote(userID, recipeID, reviewID string) (int, error)
+}
+
+type reviewService struct {
+	reviewRepo    repository.ReviewRepository
+	userRepo      repository.UserRepository
+	recipeService RecipeService
+}
+
+func NewReviewService(reviewRepo repository.ReviewRepository, userRepo repository.UserRepository, recipeService RecipeService) ReviewService {
+	return &reviewService{
+		reviewRepo:    reviewRepo,
+		userRepo:      userRepo,
+		recipeService: recipeService,
+	}
+}
+
+func (s *reviewService) List(userID, recipeID string, page *pagination.Params) ([]models.Review, *pagination.Pagination, error) {
+	if _, err := s.recipeService.Get(userID, recipeID); err != nil {
+		return nil, nil, err
+	}
+
+	reviews, result, err := s.reviewRepo.List(recipeID, page)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.annotate(userID, reviews); err != nil {
+		return nil, nil, err
+	}
+	return reviews, result, nil
+}
+
+func (s *reviewService) Create(userID, recipeID string, input *ReviewInput) (*models.Review, error) {
+	recipe, err := s.recipeService.Get(userID, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if recipe.IsOwnedBy(userID) {
+		return nil, ErrCannotReviewOwnRecipe
+	}
+
+	review := &models.Review{RecipeID: recipeID, UserID: userID}
+	if err := applyReviewInput(review, input); err != nil {
+		return nil, err
+	}
+
+	created, err := s.reviewRepo.Create(review)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrReviewExists
+	}
+	return review, nil
+}
+
+func (s *reviewService) Update(userID, recipeID, reviewID string, input *ReviewInput) (*models.Review, error) {
+	review, err := s.findReview(userID, recipeID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != userID {
+		return nil, ErrReviewForbidden
+	}
+
+	if err := applyReviewInput(review, input); err != nil {
+		return nil, err
+	}
+	if err := s.reviewRepo.Update(review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (s *reviewService) Delete(userID, recipeID, reviewID string) error {
+	review, err := s.findReview(userID, recipeID, reviewID)
+	if err != nil {
+		return err
+	}
+
+	// Authors can delete their reviews; admins can moderate any review
+	if review.UserID != userID {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return err
+		}
+		if user == nil || !user.IsAdmin() {
+			return ErrReviewForbidden
+		}
+	}
+	return s.reviewRepo.Delete(review)
+}
+
+func (s *reviewService) Vote(userID, recipeID, reviewID string) (int, error) {
+	review, err := s.findReview(userID, recipeID, reviewID)
+	if err != nil {
+		return 0, err
+	}
+	if review.UserID == userID {
+		return 0, ErrCannotVoteOwnReview
+	}
+	return s.reviewRepo.AddVote(reviewID, userID)
+}
+
+func (s *reviewService) Unvote(userID, recipeID, reviewID string) (int, error) {
+	if _, err := s.findReview(userID, recipeID, reviewID); err != nil {
+		return 0, err
+	}
+	return s.reviewRepo.RemoveVote(reviewID, userID)
+}
+
+// findReview loads a review of a recipe the user can see
+func (s *reviewService) findReview(userID, recipeID, reviewID string) (*models.Review, error) {
+	if _, err := s.recipeService.Get(userID, recipeID); err != nil {
+		return nil, err
+	}
+
+	review, err := s.reviewRepo.FindByID(recipeID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, ErrReviewNotFound
+	}
+	return review, nil
+}
+
+// annotate sets author names and the caller's helpful votes, batched per page
+func (s *reviewService) annotate(userID string, reviews []models.Review) error {
+	reviewIDs := make([]string, len(reviews))
+	authorIDs := make([]string, 0, len(reviews))
+	for i, review := range reviews {
+		reviewIDs[i] = review.ID
+		authorIDs = append(authorIDs, review.UserID)
+	}
+
+	voted, err := s.reviewRepo.VotedReviewIDs(userID, reviewIDs)
+	if err != nil {
+		return err
+	}
+	names, err := s.reviewRepo.AuthorNames(uniqueStrings(authorIDs))
+	if err != nil {
+		return err
+	}
+
+	for i := range reviews {
+		reviews[i].IsHelpful = voted[reviews[i].ID]
+		reviews[i].AuthorName = names[reviews[i].UserID]
+	}
+	return nil
+}
+
+// applyReviewInput validates the input and copies it onto the review
+func applyReviewInput(review *models.Review, input *ReviewInput) error {
+	if input.Rating < 1 || input.Rating > 5 {
+		return newValidationError("rating must be between 1 and 5")
+	}
+
+	comment := strings.TrimSpace(input.Comment)
+	if len(comment) > 5000 {
+		return newValidationError("comment must be at most 5000 characters")
+	}
+
+	photoURL := strings.TrimSpace(input.PhotoURL)
+	if photoURL != "" {
+		u, err := url.Parse(photoURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return newValidationError("photoUrl must be an http or https URL")
+		}
+	}
+
+	review.Rating = input.Rating
+	review.Comment = comment
+	review.PhotoURL = photoURL
+	return nil
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/pagination"
+	"github.com/meal-planner/backend/internal/repository"
+	"github.com/meal-planner/backend/internal/revisions"
+)
+
+// RevisionPagination is the pagination and sort allowlist for a recipe's
+// revision history
+var RevisionPagination = pagination.Config{
+	Sortable: map[string]string{
+		"number": "number",
+	},
+	DefaultSort: "-number",
+}
+
+var ErrUpstreamNotFound = errors.New("the recipe this was forked from is no longer available")
+
+// RevisionDiff lists the fields changed between two revisions of a recipe
+type RevisionDiff struct {
+	From    int                `json:"from"`
+	To      int                `json:"to"`
+	Changes []revisions.Change `json:"changes"`
+}
+
+// UpstreamChanges lists the changes made to a fork's original recipe since
+// it was forked
+type UpstreamChanges struct {
+	RecipeID         string             `json:"recipeId"`
+	Name             string             `json:"name"`
+	ForkedAtRevision int                `json:"forkedAtRevision"`
+	Revision         int                `json:"revision"`
+	Changes          []revisions.Change `json:"changes"`
+}
+
+// RevisionService reads the revision history of recipes the caller may
+// view. Revisions are recorded and restored by RecipeService.
+type RevisionService interface {
+	List(userID, recipeID string, page *pagination.Params) ([]models.RecipeRevision, *pagination.Pagination, error)
+	Get(userID, recipeID string, number int) (*models.RecipeRevision, error)
+
+	// Diff compares two revisions. A zero to compares against the latest
+	// revision and a zero from against the revision before to.
+	Diff(userID, recipeID string, from, to int) (*RevisionDiff, error)
+
+	// Upstream compares a fork's original as it was forked with the
+	// original as it is now
+	Upstream(userID, recipeID string) (*UpstreamChanges, error)
+}
+
+type revisionService struct {
+	revisionRepo  repository.RecipeRevisionRepository
+	recipeService RecipeService
+}
+
+func NewRevisionService(revisionRepo repository.RecipeRevisionRepository, recipeService RecipeService) RevisionService {
+	return &revisionService{
+		revisionRepo:  revisionRepo,
+		recipeService: recipeService,
+	}
+}
+
+func (s *revisionService) List(userID, recipeID string, page *pagination.Params) ([]models.RecipeRevision, *pagination.Pagination, error) {
+	if _, err := s.recipeService.Get(userID, recipeID); err != nil {
+		return nil, nil, err
+	}
+
+	list, result, err := s.revisionRepo.List(recipeID, page)
+	if err != nil {
+		return nil, nil, err
+	}
+	refs := make([]*models.RecipeRevision, len(list))
+	for i := range list {
+		refs[i] = &list[i]
+	}
+	if err := s.setAuthorNames(refs); err != nil {
+		return nil, nil, err
+	}
+	return list, result, nil
+}
+
+func (s *revisionService) Get(userID, recipeID string, number int) (*models.RecipeRevision, error) {
+	if _, err := s.recipeService.Get(userID, recipeID); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.find(recipeID, number)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setAuthorNames([]*models.RecipeRevision{revision}); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+func (s *revisionService) Diff(userID, recipeID string, from, to int) (*RevisionDiff, error) {
+	recipe, err := s.recipeService.Get(userID, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if from < 0 || to < 0 {
+		return nil, newValidationError("from and to must be revision numbers")
+	}
+	if to == 0 {
+		to = recipe.Revision
+	}
+	if from == 0 {
+		from = to - 1
+	}
+
+	// Comparing the first revision with "the one before" shows everything
+	// it added
+	var before models.RecipeSnapshot
+	if from > 0 {
+		revision, err := s.find(recipeID, from)
+		if err != nil {
+			return nil, err
+		}
+		before = *revision.Snapshot
+	}
+	after, err := s.find(recipeID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RevisionDiff{
+		From:    from,
+		To:      to,
+		Changes: revisions.Diff(before, *after.Snapshot),
+	}, nil
+}
+
+func (s *revisionService) Upstream(userID, recipeID string) (*UpstreamChanges, error) {
+	fork, err := s.recipeService.Get(userID, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if fork.ForkedFromID == nil || fork.ForkedFromRevision == nil {
+		return nil, newValidationError("recipe is not a fork")
+	}
+
+	// The original may have been deleted or made private since
+	original, err := s.recipeService.Get(userID, *fork.ForkedFromID)
+	if errors.Is(err, ErrRecipeNotFound) {
+		return nil, ErrUpstreamNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	base, err := s.revisionRepo.FindByNumber(original.ID, *fork.ForkedFromRevision)
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		return nil, ErrUpstreamNotFound
+	}
+
+	return &UpstreamChanges{
+		RecipeID:         original.ID,
+		Name:             original.Name,
+		ForkedAtRevision: base.Number,
+		Revision:         original.Revision,
+		Changes:          revisions.Diff(*base.Snapshot, original.Snapshot()),
+	}, nil
+}
+
+func (s *revisionService) find(recipeID string, number int) (*models.RecipeRevision, error) {
+	revision, err := s.revisionRepo.FindByNumber(recipeID, number)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, ErrRevisionNotFound
+	}
+	return revision, nil
+}
+
+// setAuthorNames sets the author names of the revisions with a single query
+func (s *revisionService) setAuthorNames(list []*models.RecipeRevision) error {
+	authorIDs := make([]string, 0, len(list))
+	for _, revision := range list {
+		if revision.AuthorID != nil {
+			authorIDs = append(authorIDs, *revision.AuthorID)
+		}
+	}
+	names, err := s.revisionRepo.AuthorNames(authorIDs)
+	if err != nil {
+		return err
+	}
+	for _, revision := range list {
+		if revision.AuthorID != nil {
+			revision.AuthorName = names[*revision.AuthorID]
+		}
+	}
+	return nil
+}
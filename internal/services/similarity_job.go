@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/repository"
+	"github.com/meal-planner/backend/internal/similarity"
+)
+
+const (
+	// MaxSimilarRecipes is the length of each cached neighbor list. Lists
+	// are longer than a page of results so filters still leave enough.
+	MaxSimilarRecipes = 50
+
+	// similarityRebuildInterval is how often every list is recomputed, so
+	// that ingredient weights follow the catalog as it grows
+	similarityRebuildInterval = 24 * time.Hour
+
+	// similaritySaveBatch is the number of lists saved per transaction
+	similaritySaveBatch = 500
+
+	// similarityPollOverlap moves each poll's watermark back. updated_at is
+	// set when a save's statement runs, not when its transaction commits, so
+	// a save that commits after a poll can carry an earlier time. Recipes in
+	// the overlap are recomputed twice, which is harmless.
+	similarityPollOverlap = time.Minute
+)
+
+// SimilarityJob keeps the cached similar-recipe lists current. It holds the
+// catalog's features in memory and polls for recipes saved or deleted since
+// its last run, recomputing the lists of those recipes and of the recipes
+// they enter or leave. Recipes saved by other processes, such as the
+// recipe-import command, are picked up the same way.
+type SimilarityJob interface {
+	// Run refreshes the lists every interval until the context is done
+	Run(ctx context.Context)
+}
+
+type similarityJob struct {
+	recipeRepo   repository.RecipeRepository
+	neighborRepo repository.RecipeNeighborRepository
+	interval     time.Duration
+
+	index       *similarity.Index
+	lastRebuild time.Time
+	// watermark is shortly before the last poll started; recipes saved
+	// since are picked up by the next one
+	watermark time.Time
+}
+
+// NewSimilarityJob returns a job that polls every interval, or every minute
+// when interval is not positive
+func NewSimilarityJob(recipeRepo repository.RecipeRepository, neighborRepo repository.RecipeNeighborRepository, interval time.Duration) SimilarityJob {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &similarityJob{
+		recipeRepo:   recipeRepo,
+		neighborRepo: neighborRepo,
+		interval:     interval,
+	}
+}
+
+func (j *similarityJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		var err error
+		if j.index == nil || time.Since(j.lastRebuild) >= similarityRebuildInterval {
+			err = j.rebuild()
+		} else {
+			err = j.refresh()
+		}
+		if err != nil {
+			log.Printf("Failed to refresh similar recipes: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// rebuild loads every recipe into a new index and recomputes every list
+func (j *similarityJob) rebuild() error {
+	started := time.Now()
+	index := similarity.NewIndex(similarity.DefaultWeights)
+	err := j.recipeRepo.ForEach(func(recipe *models.Recipe) error {
+		index.Add(similarity.FromRecipe(recipe))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := j.save(index, index.IDs()); err != nil {
+		return err
+	}
+	if err := j.neighborRepo.DeleteStale(); err != nil {
+		return err
+	}
+
+	j.index = index
+	j.lastRebuild = started
+	j.watermark = started.Add(-similarityPollOverlap)
+	log.Printf("Computed similar recipes for %d recipes in %s", index.Len(), time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+// refresh updates the index with the recipes changed since the last poll
+// and recomputes the lists they affect
+func (j *similarityJob) refresh() error {
+	started := time.Now()
+	changed, deletedIDs, err := j.recipeRepo.ChangedSince(j.watermark)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 && len(deletedIDs) == 0 {
+		j.watermark = started.Add(-similarityPollOverlap)
+		return nil
+	}
+
+	ids := make([]string, 0, len(changed)+len(deletedIDs))
+	for _, id := range deletedIDs {
+		j.index.Remove(id)
+		ids = append(ids, id)
+	}
+	for i := range changed {
+		j.index.Add(similarity.FromRecipe(&changed[i]))
+		ids = append(ids, changed[i].ID)
+	}
+
+	// A changed recipe can enter the lists of its own neighbors and leave
+	// the lists it was on
+	affected := make(map[string]bool)
+	for _, id := range ids {
+		affected[id] = true
+		for _, n := range j.index.Neighbors(id, MaxSimilarRecipes) {
+			affected[n.ID] = true
+		}
+	}
+	referencing, err := j.neighborRepo.ReferencingRecipes(ids)
+	if err != nil {
+		return err
+	}
+	for _, id := range referencing {
+		affected[id] = true
+	}
+
+	affectedIDs := make([]string, 0, len(affected))
+	for id := range affected {
+		affectedIDs = append(affectedIDs, id)
+	}
+	if err := j.save(j.index, affectedIDs); err != nil {
+		return err
+	}
+	j.watermark = started.Add(-similarityPollOverlap)
+	return nil
+}
+
+// save recomputes and stores the lists of the given recipes. Recipes that
+// are no longer in the index get empty lists, removing their rows.
+func (j *similarityJob) save(index *similarity.Index, ids []string) error {
+	lists := make(map[string][]models.RecipeNeighbor, similaritySaveBatch)
+	for _, id := range ids {
+		neighbors := index.Neighbors(id, MaxSimilarRecipes)
+		list := make([]models.RecipeNeighbor, len(neighbors))
+		for i, n := range neighbors {
+			list[i] = models.RecipeNeighbor{RecipeID: id, NeighborID: n.ID, Rank: i + 1, Score: n.Score}
+		}
+		lists[id] = list
+
+		if len(lists) == similaritySaveBatch {
+			if err := j.neighborRepo.Replace(lists); err != nil {
+				return err
+			}
+			lists = make(map[string][]models.RecipeNeighbor, similaritySaveBatch)
+		}
+	}
+	return j.neighborRepo.Replace(lists)
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"github.com/meal-planner/backend/internal/models"
+	"github.com/meal-planner/backend/internal/repository"
+)
+
+// Default and largest number of similar recipes returned
+const (
+	DefaultSimilarLimit = 10
+	MaxSimilarLimit     = MaxSimilarRecipes
+)
+
+// SimilarityService recommends recipes like a given one from the lists the
+// similarity job caches
+type SimilarityService interface {
+	// Similar returns up to limit recipes like the given one that the user
+	// can see, most similar first. The filters in params apply as they do
+	// to listings. A recipe saved moments ago has no list until the job's
+	// next run.
+	Similar(userID, recipeID string, params RecipeListParams, limit int) ([]models.SimilarRecipe, error)
+}
+
+type similarityService struct {
+	neighborRepo  repository.RecipeNeighborRepository
+	recipeService RecipeService
+}
+
+func NewSimilarityService(neighborRepo repository.RecipeNeighborRepository, recipeService RecipeService) SimilarityService {
+	return &similarityService{
+		neighborRepo:  neighborRepo,
+		recipeService: recipeService,
+	}
+}
+
+func (s *similarityService) Similar(userID, recipeID string, params RecipeListParams, limit int) ([]models.SimilarRecipe, error) {
+	if limit < 1 || limit > MaxSimilarLimit {
+		return nil, newValidationError("limit must be between 1 and %d", MaxSimilarLimit)
+	}
+	// Only recipes the user can see have similar recipes to show
+	if _, err := s.recipeService.Get(userID, recipeID); err != nil {
+		return nil, err
+	}
+	filter, err := s.recipeService.ListFilter(userID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.neighborRepo.Similar(recipeID, filter, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*models.Recipe, len(results))
+	for i := range results {
+		refs[i] = &results[i].Recipe
+	}
+	if err := s.recipeService.MarkForViewer(userID, refs); err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []models.SimilarRecipe{}
+	}
+	return results, nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/meal-planner/backend/internal/config"
+	"github.com/meal-planner/backend/internal/locale"
 	"github.com/meal-planner/backend/internal/models"
 	"github.com/meal-planner/backend/internal/repository"
 	"github.com/meal-planner/backend/internal/utils"
@@ -17,7 +18,6 @@ type UserService interface {
 	GetUserByID(userID string) (*models.User, error)
 	UpdateProfile(userID, name, email string) (*models.User, error)
 	ChangePassword(userID, currentPassword, newPassword string) error
-	CompleteOnboarding(userID string) (*models.User, error)
 	UpdatePreferences(userID string, preferences *models.UserPreferences) (*models.User, error)
 }
 
@@ -116,7 +116,7 @@ func (s *userService) ChangePassword(userID, currentPassword, newPassword string
 	return s.userRepo.Update(user)
 }
 
-func (s *userService) CompleteOnboarding(userID string) (*models.User, error) {
+func (s *userService) UpdatePreferences(userID string, preferences *models.UserPreferences) (*models.User, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		return nil, err
@@ -125,7 +125,11 @@ func (s *userService) CompleteOnboarding(userID string) (*models.User, error) {
 		return nil, ErrUserNotFound
 	}
 
-	user.HasCompletedOnboarding = true
+	if err := normalizeRegionalPreferences(preferences); err != nil {
+		return nil, err
+	}
+
+	user.Preferences = preferences
 	if err := s.userRepo.Update(user); err != nil {
 		return nil, err
 	}
@@ -133,19 +137,21 @@ func (s *userService) CompleteOnboarding(userID string) (*models.User, error) {
 	return user, nil
 }
 
-func (s *userService) UpdatePreferences(userID string, preferences *models.UserPreferences) (*models.User, error) {
-	user, err := s.userRepo.FindByID(userID)
-	if err != nil {
-		return nil, err
+// normalizeRegionalPreferences validates the time zone, locale, unit system
+// and week start, filling in defaults for any that are unset
+func normalizeRegionalPreferences(preferences *models.UserPreferences) error {
+	var err error
+	if preferences.TimeZone, err = locale.NormalizeTimeZone(preferences.TimeZone); err != nil {
+		return &ValidationError{Message: err.Error()}
 	}
-	if user == nil {
-		return nil, ErrUserNotFound
+	if preferences.Locale, err = locale.NormalizeLocale(preferences.Locale); err != nil {
+		return &ValidationError{Message: err.Error()}
 	}
-
-	user.Preferences = preferences
-	if err := s.userRepo.Update(user); err != nil {
-		return nil, err
+	if preferences.UnitSystem, err = locale.NormalizeUnitSystem(preferences.UnitSystem); err != nil {
+		return &ValidationError{Message: err.Error()}
 	}
-
-	return user, nil
+	if preferences.WeekStart, err = locale.NormalizeWeekStart(preferences.WeekStart); err != nil {
+		return &ValidationError{Message: err.Error()}
+	}
+	return nil
 }
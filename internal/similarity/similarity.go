@@ -0,0 +1,319 @@
+// Package similarity ranks recipes by how alike they are, for "more like
+// this" recommendations. Ingredients are compared by TF-IDF cosine over
+// normalized names, so sharing saffron counts for more than sharing salt;
+// tags by their Jaccard overlap; cuisine and category by equality.
+package similarity
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/foods"
+	"github.com/meal-planner/backend/internal/models"
+)
+
+// MinScore is the lowest score at which a recipe counts as similar
+const MinScore = 0.1
+
+// maxCandidates bounds the recipes scored for one Neighbors call. Features
+// are visited rarest first, so the bound drops recipes that only share
+// staples like salt.
+const maxCandidates = 2000
+
+// Weights set how much each part of a recipe counts towards its score
+type Weights struct {
+	Ingredients float64
+	Tags        float64
+	Cuisine     float64
+	Category    float64
+}
+
+// DefaultWeights favor ingredients, which say the most about a dish
+var DefaultWeights = Weights{Ingredients: 0.55, Tags: 0.25, Cuisine: 0.12, Category: 0.08}
+
+func (w Weights) total() float64 {
+	return w.Ingredients + w.Tags + w.Cuisine + w.Category
+}
+
+// Features are the parts of a recipe that are compared
+type Features struct {
+	ID string
+	// Family is the recipe a fork was copied from, or the recipe itself.
+	// Recipes of one family are never each other's neighbors.
+	Family      string
+	Ingredients []string
+	Tags        []string
+	Cuisine     string
+	Category    string
+}
+
+// FromRecipe returns the features of a recipe
+func FromRecipe(r *models.Recipe) Features {
+	f := Features{
+		ID:       r.ID,
+		Family:   r.ID,
+		Cuisine:  strings.ToLower(strings.TrimSpace(r.Cuisine)),
+		Category: strings.ToLower(strings.TrimSpace(r.Category)),
+	}
+	if r.ForkedFromID != nil {
+		f.Family = *r.ForkedFromID
+	}
+	names := make([]string, len(r.Ingredients))
+	for i, ing := range r.Ingredients {
+		names[i] = IngredientKey(ing.Name)
+	}
+	f.Ingredients = unique(names)
+	tags := make([]string, len(r.Tags))
+	for i, tag := range r.Tags {
+		tags[i] = strings.ToLower(strings.TrimSpace(tag))
+	}
+	f.Tags = unique(tags)
+	return f
+}
+
+// IngredientKey normalizes an ingredient name for comparison, so that
+// "Large Eggs" and "egg" or "extra-virgin olive oil" and "olive oil" match
+func IngredientKey(name string) string {
+	return strings.Join(foods.Tokens(name), " ")
+}
+
+// unique sorts values and drops blanks and repeats
+func unique(values []string) []string {
+	sort.Strings(values)
+	out := values[:0]
+	for i, v := range values {
+		if v != "" && (i == 0 || v != values[i-1]) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Neighbor is a recipe similar to another, with a score from 0 to 1
+type Neighbor struct {
+	ID    string
+	Score float64
+}
+
+// Index holds the features of a catalog of recipes and finds each one's
+// most similar recipes. It is not safe for concurrent use.
+type Index struct {
+	weights Weights
+	recipes map[string]*Features
+	// ingredients and tags map each value to the recipes that have it
+	ingredients map[string]map[string]bool
+	tags        map[string]map[string]bool
+}
+
+// NewIndex returns an empty index that scores with the given weights
+func NewIndex(weights Weights) *Index {
+	return &Index{
+		weights:     weights,
+		recipes:     make(map[string]*Features),
+		ingredients: make(map[string]map[string]bool),
+		tags:        make(map[string]map[string]bool),
+	}
+}
+
+// Len returns the number of recipes in the index
+func (ix *Index) Len() int {
+	return len(ix.recipes)
+}
+
+// IDs returns the IDs of the recipes in the index, sorted
+func (ix *Index) IDs() []string {
+	ids := make([]string, 0, len(ix.recipes))
+	for id := range ix.recipes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Add adds a recipe, replacing its earlier features
+func (ix *Index) Add(f Features) {
+	ix.Remove(f.ID)
+	ix.recipes[f.ID] = &f
+	post(ix.ingredients, f.Ingredients, f.ID)
+	post(ix.tags, f.Tags, f.ID)
+}
+
+// Remove removes a recipe; removing a missing recipe does nothing
+func (ix *Index) Remove(id string) {
+	f, ok := ix.recipes[id]
+	if !ok {
+		return
+	}
+	delete(ix.recipes, id)
+	unpost(ix.ingredients, f.Ingredients, id)
+	unpost(ix.tags, f.Tags, id)
+}
+
+func post(postings map[string]map[string]bool, values []string, id string) {
+	for _, v := range values {
+		if postings[v] == nil {
+			postings[v] = make(map[string]bool)
+		}
+		postings[v][id] = true
+	}
+}
+
+func unpost(postings map[string]map[string]bool, values []string, id string) {
+	for _, v := range values {
+		delete(postings[v], id)
+		if len(postings[v]) == 0 {
+			delete(postings, v)
+		}
+	}
+}
+
+// Score rates how similar two recipes of the index are, from 0 to 1. It
+// returns 0 when either is missing.
+func (ix *Index) Score(a, b string) float64 {
+	fa, fb := ix.recipes[a], ix.recipes[b]
+	if fa == nil || fb == nil {
+		return 0
+	}
+	return ix.score(fa, fb)
+}
+
+func (ix *Index) score(a, b *Features) float64 {
+	w := ix.weights
+	total := w.total()
+	if total <= 0 {
+		return 0
+	}
+	score := w.Ingredients*ix.cosine(a.Ingredients, b.Ingredients) + w.Tags*jaccard(a.Tags, b.Tags)
+	if a.Cuisine != "" && a.Cuisine == b.Cuisine {
+		score += w.Cuisine
+	}
+	if a.Category != "" && a.Category == b.Category {
+		score += w.Category
+	}
+	return score / total
+}
+
+// idf weighs an ingredient by how few recipes use it
+func (ix *Index) idf(ingredient string) float64 {
+	df := len(ix.ingredients[ingredient])
+	if df == 0 {
+		df = 1
+	}
+	return math.Log(1 + float64(len(ix.recipes))/float64(df))
+}
+
+// cosine compares two sorted ingredient lists as TF-IDF vectors. An
+// ingredient appears once per recipe, so its term frequency is 1.
+func (ix *Index) cosine(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			normA += sq(ix.idf(a[i]))
+			i++
+		case i == len(a) || b[j] < a[i]:
+			normB += sq(ix.idf(b[j]))
+			j++
+		default:
+			weight := sq(ix.idf(a[i]))
+			dot += weight
+			normA += weight
+			normB += weight
+			i++
+			j++
+		}
+	}
+	if dot == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+func sq(x float64) float64 {
+	return x * x
+}
+
+// jaccard compares two sorted tag lists by their overlap
+func jaccard(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case b[j] < a[i]:
+			j++
+		default:
+			shared++
+			i++
+			j++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// Neighbors returns up to k recipes most similar to the given one, best
+// first, leaving out recipes of its family and scores below MinScore. Only
+// recipes sharing an ingredient or tag are considered.
+func (ix *Index) Neighbors(id string, k int) []Neighbor {
+	f := ix.recipes[id]
+	if f == nil || k <= 0 {
+		return nil
+	}
+
+	var neighbors []Neighbor
+	for candidate := range ix.candidates(f) {
+		other := ix.recipes[candidate]
+		if other.Family == f.Family {
+			continue
+		}
+		if score := ix.score(f, other); score >= MinScore {
+			neighbors = append(neighbors, Neighbor{ID: candidate, Score: score})
+		}
+	}
+	sort.Slice(neighbors, func(i, j int) bool {
+		if neighbors[i].Score != neighbors[j].Score {
+			return neighbors[i].Score > neighbors[j].Score
+		}
+		return neighbors[i].ID < neighbors[j].ID
+	})
+	if len(neighbors) > k {
+		neighbors = neighbors[:k]
+	}
+	return neighbors
+}
+
+// candidates collects the recipes sharing an ingredient or tag with f,
+// visiting the rarest values first until maxCandidates is reached
+func (ix *Index) candidates(f *Features) map[string]bool {
+	var postings []map[string]bool
+	for _, v := range f.Ingredients {
+		postings = append(postings, ix.ingredients[v])
+	}
+	for _, v := range f.Tags {
+		postings = append(postings, ix.tags[v])
+	}
+	sort.SliceStable(postings, func(i, j int) bool {
+		return len(postings[i]) < len(postings[j])
+	})
+
+	candidates := make(map[string]bool)
+	for _, ids := range postings {
+		if len(candidates) >= maxCandidates {
+			break
+		}
+		for id := range ids {
+			if id != f.ID {
+				candidates[id] = true
+			}
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,124 @@
+package similarity
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/meal-planner/backend/internal/models"
+)
+
+func testIndex() *Index {
+	ix := NewIndex(DefaultWeights)
+	for _, f := range []Features{
+		{ID: "carbonara", Family: "carbonara", Ingredients: []string{"egg", "guanciale", "pecorino", "salt", "spaghetti"}, Tags: []string{"pasta", "quick"}, Cuisine: "italian", Category: "dinner"},
+		{ID: "cacio", Family: "cacio", Ingredients: []string{"black pepper", "pecorino", "salt", "spaghetti"}, Tags: []string{"pasta", "vegetarian"}, Cuisine: "italian", Category: "dinner"},
+		{ID: "omelette", Family: "omelette", Ingredients: []string{"butter", "egg", "salt"}, Tags: []string{"quick"}, Cuisine: "french", Category: "breakfast"},
+		{ID: "brownies", Family: "brownies", Ingredients: []string{"butter", "chocolate", "egg", "sugar"}, Tags: []string{"baking"}, Category: "desserts"},
+		{ID: "fork", Family: "carbonara", Ingredients: []string{"egg", "guanciale", "pecorino", "salt", "spaghetti"}, Tags: []string{"pasta"}, Cuisine: "italian", Category: "dinner"},
+	} {
+		ix.Add(f)
+	}
+	return ix
+}
+
+func TestFromRecipe(t *testing.T) {
+	parent := "recipe_1"
+	f := FromRecipe(&models.Recipe{
+		ID:           "recipe_2",
+		Cuisine:      " Italian ",
+		Category:     "Dinner",
+		ForkedFromID: &parent,
+		Ingredients: models.RecipeIngredients{
+			{Name: "Large Eggs"},
+			{Name: "extra-virgin olive oil"},
+			{Name: "egg"},
+			{Name: ""},
+		},
+		Tags: models.StringList{"Quick", "pasta", "quick"},
+	})
+
+	want := Features{
+		ID:          "recipe_2",
+		Family:      "recipe_1",
+		Ingredients: []string{"egg", "olive oil"},
+		Tags:        []string{"pasta", "quick"},
+		Cuisine:     "italian",
+		Category:    "dinner",
+	}
+	if !reflect.DeepEqual(f, want) {
+		t.Errorf("FromRecipe() =\n%+v\nwant\n%+v", f, want)
+	}
+}
+
+func TestScore(t *testing.T) {
+	ix := testIndex()
+
+	if got := ix.Score("carbonara", "carbonara"); math.Abs(got-1) > 1e-9 {
+		t.Errorf("Score(self) = %v, want 1", got)
+	}
+	if got := ix.Score("carbonara", "missing"); got != 0 {
+		t.Errorf("Score(missing) = %v, want 0", got)
+	}
+	if a, b := ix.Score("carbonara", "cacio"), ix.Score("cacio", "carbonara"); a != b {
+		t.Errorf("Score is not symmetric: %v, %v", a, b)
+	}
+
+	// Sharing rare pecorino and spaghetti counts for more than sharing
+	// common eggs and salt
+	if cacio, omelette := ix.Score("carbonara", "cacio"), ix.Score("carbonara", "omelette"); cacio <= omelette {
+		t.Errorf("Score(cacio) = %v, want more than Score(omelette) = %v", cacio, omelette)
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	ix := testIndex()
+
+	got := ix.Neighbors("carbonara", 10)
+	var ids []string
+	for i, n := range got {
+		ids = append(ids, n.ID)
+		if n.Score < MinScore || n.Score > 1 {
+			t.Errorf("neighbor %s score = %v, want within [%v, 1]", n.ID, n.Score, MinScore)
+		}
+		if i > 0 && n.Score > got[i-1].Score {
+			t.Errorf("neighbors are not sorted by score: %+v", got)
+		}
+	}
+	// The fork is of the same family and left out; brownies share only
+	// eggs and score below MinScore
+	if want := []string{"cacio", "omelette"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Neighbors() = %v, want %v", ids, want)
+	}
+
+	if got := ix.Neighbors("carbonara", 1); len(got) != 1 || got[0].ID != "cacio" {
+		t.Errorf("Neighbors(k=1) = %+v, want cacio", got)
+	}
+	if got := ix.Neighbors("missing", 10); got != nil {
+		t.Errorf("Neighbors(missing) = %+v, want nil", got)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	ix := testIndex()
+	ix.Remove("cacio")
+	ix.Remove("cacio")
+
+	if ix.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", ix.Len())
+	}
+	for _, n := range ix.Neighbors("carbonara", 10) {
+		if n.ID == "cacio" {
+			t.Errorf("removed recipe is still a neighbor")
+		}
+	}
+	if _, ok := ix.ingredients["black pepper"]; ok {
+		t.Errorf("postings of a removed recipe were kept")
+	}
+
+	// Adding again replaces the features
+	ix.Add(Features{ID: "omelette", Family: "omelette", Ingredients: []string{"egg"}})
+	if ix.ingredients["butter"]["omelette"] {
+		t.Errorf("old postings were kept after Add replaced a recipe")
+	}
+}
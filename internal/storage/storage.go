@@ -0,0 +1,50 @@
+// Package storage stores uploaded files, such as recipe images, as blobs
+// addressed by slash-separated keys. Stores are pluggable; the local
+// filesystem store is used by default and serves its files itself.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/config"
+)
+
+// ErrInvalidKey is returned for keys that are empty, absolute or climb out
+// of the store with ".."
+var ErrInvalidKey = errors.New("storage: invalid key")
+
+// Store stores blobs by key
+type Store interface {
+	// Put writes a blob, replacing any blob with the same key
+	Put(key string, r io.Reader, contentType string) error
+	// Delete removes a blob. Deleting a missing blob is not an error.
+	Delete(key string) error
+	// URL returns the address clients fetch a blob from
+	URL(key string) string
+}
+
+// New returns the store selected by cfg.StorageDriver
+func New(cfg *config.Config) (Store, error) {
+	switch cfg.StorageDriver {
+	case "", "local":
+		return NewLocal(cfg.StorageDir, cfg.StorageBaseURL)
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", cfg.StorageDriver)
+	}
+}
+
+// checkKey validates a key and returns it cleaned
+func checkKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned != key || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
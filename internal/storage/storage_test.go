@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckKey(t *testing.T) {
+	tests := []struct {
+		key   string
+		valid bool
+	}{
+		{"recipes/abc/large.jpg", true},
+		{"thumb.jpg", true},
+		{"", false},
+		{"/etc/passwd", false},
+		{"../secret", false},
+		{"recipes/../../secret", false},
+		{"recipes//large.jpg", false},
+		{"recipes/./large.jpg", false},
+		{"..", false},
+		{`recipes\large.jpg`, false},
+	}
+	for _, tt := range tests {
+		_, err := checkKey(tt.key)
+		if (err == nil) != tt.valid {
+			t.Errorf("checkKey(%q) error = %v, want valid %v", tt.key, err, tt.valid)
+		}
+	}
+}
+
+func TestLocal(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocal(filepath.Join(dir, "uploads"), "/uploads/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := "recipes/r1/large.jpg"
+	if err := store.Put(key, strings.NewReader("image"), "image/jpeg"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "uploads", "recipes", "r1", "large.jpg"))
+	if err != nil || string(data) != "image" {
+		t.Fatalf("stored file = %q, %v", data, err)
+	}
+	if err := store.Put(key, strings.NewReader("replaced"), "image/jpeg"); err != nil {
+		t.Fatalf("Put() replace error = %v", err)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "uploads", "recipes", "r1", "large.jpg"))
+	if string(data) != "replaced" {
+		t.Errorf("replaced file = %q", data)
+	}
+	if got := store.URL(key); got != "/uploads/recipes/r1/large.jpg" {
+		t.Errorf("URL() = %q", got)
+	}
+
+	if err := store.Delete(key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := store.Delete(key); err != nil {
+		t.Errorf("Delete() of a missing blob error = %v", err)
+	}
+	if err := store.Put("../escape", strings.NewReader("x"), ""); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Put() outside the store error = %v, want ErrInvalidKey", err)
+	}
+}
@@ -0,0 +1,114 @@
+package units
+
+import "strings"
+
+// densities maps ingredient keywords to grams per milliliter, from common
+// cup weights (a cup of all-purpose flour is about 125 g, of sugar 200 g).
+// The longest keyword contained in an ingredient name wins, so "brown
+// sugar" is not weighed as "sugar".
+var densities = map[string]float64{
+	"water":      1.0,
+	"broth":      1.0,
+	"stock":      1.0,
+	"milk":       1.03,
+	"buttermilk": 1.03,
+	"cream":      1.0,
+	"sour cream": 1.02,
+	"yogurt":     1.03,
+	"yoghurt":    1.03,
+	"wine":       0.99,
+	"vinegar":    1.01,
+	"juice":      1.04,
+	"soy sauce":  1.15,
+	"ketchup":    1.14,
+	"mayonnaise": 0.95,
+
+	"flour":             0.53,
+	"bread flour":       0.55,
+	"whole wheat flour": 0.51,
+	"almond flour":      0.41,
+	"cornstarch":        0.54,
+	"corn starch":       0.54,
+	"cornmeal":          0.67,
+	"cocoa":             0.36,
+	"breadcrumbs":       0.45,
+	"bread crumbs":      0.45,
+	"panko":             0.25,
+	"oats":              0.38,
+	"rice":              0.78,
+	"quinoa":            0.72,
+	"lentils":           0.81,
+	"couscous":          0.73,
+
+	"sugar":                0.85,
+	"brown sugar":          0.93,
+	"powdered sugar":       0.51,
+	"icing sugar":          0.51,
+	"confectioners sugar":  0.51,
+	"confectioners' sugar": 0.51,
+	"honey":                1.42,
+	"maple syrup":          1.32,
+	"molasses":             1.41,
+	"syrup":                1.33,
+	"chocolate chips":      0.72,
+
+	"salt":            1.22,
+	"kosher salt":     0.64,
+	"sea salt":        1.1,
+	"baking soda":     0.93,
+	"baking powder":   0.81,
+	"cream of tartar": 0.61,
+	"yeast":           0.6,
+	"cinnamon":        0.56,
+	"paprika":         0.46,
+	"cumin":           0.43,
+	"pepper":          0.49,
+
+	"butter":        0.96,
+	"oil":           0.92,
+	"shortening":    0.81,
+	"peanut butter": 1.08,
+	"cheese":        0.47,
+	"parmesan":      0.42,
+	"cream cheese":  0.98,
+	"almonds":       0.6,
+	"walnuts":       0.42,
+	"pecans":        0.42,
+	"raisins":       0.63,
+}
+
+// liquids are measured by volume in metric recipes, not weighed
+var liquids = map[string]bool{
+	"water": true, "broth": true, "stock": true, "milk": true,
+	"buttermilk": true, "cream": true, "wine": true, "vinegar": true,
+	"juice": true, "soy sauce": true, "oil": true, "maple syrup": true,
+	"syrup": true,
+}
+
+// Density returns the density in grams per milliliter of the named
+// ingredient, if one is known
+func Density(ingredient string) (float64, bool) {
+	keyword := densityKeyword(ingredient)
+	if keyword == "" {
+		return 0, false
+	}
+	return densities[keyword], true
+}
+
+// IsLiquid reports whether the named ingredient is a known liquid
+func IsLiquid(ingredient string) bool {
+	return liquids[densityKeyword(ingredient)]
+}
+
+// densityKeyword returns the longest keyword of densities that appears as
+// whole words in the ingredient name
+func densityKeyword(ingredient string) string {
+	name := " " + strings.ToLower(ingredient) + " "
+	best := ""
+	for keyword := range densities {
+		if len(keyword) > len(best) && strings.Contains(name, " "+keyword+" ") {
+			best = keyword
+		}
+	}
+	return best
+}
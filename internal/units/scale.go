@@ -0,0 +1,105 @@
+package units
+
+import (
+	"math"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/ingredients"
+)
+
+// unscaledNotes mark amounts that depend on the cook rather than the yield
+var unscaledNotes = []string{
+	"to taste", "as needed", "as required", "for garnish", "for serving",
+	"for greasing", "for dusting",
+}
+
+// wholeUnits are count units that are not sensibly split
+var wholeUnits = map[string]bool{
+	"clove": true, "ear": true,
+}
+
+// Scale multiplies an ingredient's quantity by factor and rounds the result
+// to something a cook can measure:
+//   - amounts "to taste", "for garnish" and the like are left alone;
+//   - eggs and cloves are rounded to whole numbers, never below one;
+//   - other counted items, like "1 onion" or "1 can", to quarters or halves;
+//   - measures are re-expressed in the friendliest unit of their system,
+//     so 1/4 cup halved becomes 2 tbsp, and rounded with Round.
+//
+// Package sizes such as the 14 oz of "1 (14 oz) can" are not scaled.
+func Scale(ing ingredients.Ingredient, factor float64) ingredients.Ingredient {
+	if factor == 1 || factor <= 0 || ing.Quantity <= 0 || isUnscaled(ing) {
+		return ing
+	}
+
+	if ing.UnknownUnit || DimensionOf(ing.Unit) == Count {
+		round := roundCount
+		if isWhole(ing) {
+			round = roundWhole
+		}
+		ing.Quantity = round(ing.Quantity * factor)
+		if ing.QuantityMax > 0 {
+			ing.QuantityMax = round(ing.QuantityMax * factor)
+			if ing.QuantityMax <= ing.Quantity {
+				ing.QuantityMax = 0
+			}
+		}
+		return ing
+	}
+
+	def := unitDefs[ing.Unit]
+	base := ing.Quantity * factor * def.factor
+	var scaled Quantity
+	if def.metric {
+		scaled = Round(metricQuantity(base, def.dimension))
+	} else {
+		scaled = Round(usQuantity(base, def.dimension))
+	}
+	if ing.QuantityMax > 0 {
+		upper := Round(Quantity{Amount: ing.QuantityMax * factor * def.factor / unitDefs[scaled.Unit].factor, Unit: scaled.Unit})
+		ing.QuantityMax = upper.Amount
+		if ing.QuantityMax <= scaled.Amount {
+			ing.QuantityMax = 0
+		}
+	}
+	ing.Quantity, ing.Unit = scaled.Amount, scaled.Unit
+	return ing
+}
+
+func isUnscaled(ing ingredients.Ingredient) bool {
+	note := strings.ToLower(ing.Note)
+	for _, phrase := range unscaledNotes {
+		if strings.Contains(note, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWhole reports whether the ingredient is counted in whole items, like
+// eggs or garlic cloves
+func isWhole(ing ingredients.Ingredient) bool {
+	if ing.Unit != "" {
+		return wholeUnits[ing.Unit]
+	}
+	for _, word := range strings.Fields(strings.ToLower(ing.Name)) {
+		if word == "egg" || word == "eggs" {
+			return true
+		}
+	}
+	return false
+}
+
+// roundWhole rounds to a whole number of at least one
+func roundWhole(q float64) float64 {
+	return math.Max(1, math.Round(q))
+}
+
+// roundCount rounds to quarters below two and halves above, never to zero
+func roundCount(q float64) float64 {
+	step := 0.5
+	if q < 2 {
+		step = 0.25
+	}
+	return math.Max(step, roundTo(q, step))
+}
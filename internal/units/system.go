@@ -0,0 +1,242 @@
+package units
+
+import (
+	"math"
+	"strings"
+
+	"github.com/meal-planner/backend/internal/ingredients"
+	"github.com/meal-planner/backend/internal/locale"
+)
+
+// metricWeighVolume is the smallest volume, in milliliters, that metric
+// rendering weighs instead of measuring; smaller amounts stay in spoons
+const metricWeighVolume = 59
+
+// ToSystem converts q to the measurement system (locale.UnitSystemMetric or
+// locale.UnitSystemUS), choosing a unit that suits the amount, and rounds
+// it. Amounts already in the system keep their unit, teaspoons and
+// tablespoons count as both, and count units are returned unchanged. In
+// metric, volumes of 1/4 cup or more of dry ingredients are weighed when
+// the ingredient's density is known, as metric recipes do.
+func ToSystem(q Quantity, system, ingredient string) Quantity {
+	def, ok := unitDefs[q.Unit]
+	if !ok || q.Amount <= 0 {
+		return q
+	}
+
+	spoon := q.Unit == ingredients.Teaspoon || q.Unit == ingredients.Tablespoon
+	base := q.Amount * def.factor
+	if system == locale.UnitSystemMetric {
+		switch {
+		case def.dimension == Volume && base >= metricWeighVolume:
+			if density, ok := Density(ingredient); ok && !IsLiquid(ingredient) {
+				return Round(metricQuantity(base*density, Mass))
+			}
+			if def.metric {
+				return Round(q)
+			}
+			return Round(metricQuantity(base, Volume))
+		case def.metric || spoon:
+			return Round(q)
+		default:
+			return Round(metricQuantity(base, def.dimension))
+		}
+	}
+
+	if !def.metric {
+		return Round(q)
+	}
+	return Round(usQuantity(base, def.dimension))
+}
+
+// metricQuantity picks g or kg, ml or l for an amount in grams or milliliters
+func metricQuantity(base float64, dimension Dimension) Quantity {
+	if dimension == Mass {
+		if base >= 1000 {
+			return Quantity{Amount: base / 1000, Unit: ingredients.Kilogram}
+		}
+		return Quantity{Amount: base, Unit: ingredients.Gram}
+	}
+	if base >= 1000 {
+		return Quantity{Amount: base / 1000, Unit: ingredients.Liter}
+	}
+	return Quantity{Amount: base, Unit: ingredients.Milliliter}
+}
+
+// usQuantity picks oz or lb, or tsp, tbsp, cup or quart, for an amount in
+// grams or milliliters
+func usQuantity(base float64, dimension Dimension) Quantity {
+	var unit string
+	switch {
+	case dimension == Mass && base >= unitDefs[ingredients.Pound].factor:
+		unit = ingredients.Pound
+	case dimension == Mass:
+		unit = ingredients.Ounce
+	case base < unitDefs[ingredients.Tablespoon].factor:
+		unit = ingredients.Teaspoon
+	case base < unitDefs[ingredients.Cup].factor/4:
+		unit = ingredients.Tablespoon
+	case base < unitDefs[ingredients.Quart].factor*2:
+		unit = ingredients.Cup
+	default:
+		unit = ingredients.Quart
+	}
+	return Quantity{Amount: base / unitDefs[unit].factor, Unit: unit}
+}
+
+// Round rounds q to an amount a cook can measure: eighths of a teaspoon,
+// half tablespoons, common fractions of cups, quarter ounces, eighths of a pound, and whole or round
+// numbers of grams and milliliters. It never rounds a positive amount to zero.
+func Round(q Quantity) Quantity {
+	var rounded float64
+	switch q.Unit {
+	case ingredients.Teaspoon:
+		if q.Amount < 1 {
+			rounded = roundTo(q.Amount, 0.125)
+		} else {
+			rounded = roundTo(q.Amount, 0.25)
+		}
+	case ingredients.Tablespoon:
+		rounded = roundTo(q.Amount, 0.5)
+	case ingredients.Cup, ingredients.FluidOunce, ingredients.Pint, ingredients.Quart, ingredients.Gallon:
+		rounded = roundFraction(q.Amount)
+	case ingredients.Ounce:
+		if q.Amount < 4 {
+			rounded = roundTo(q.Amount, 0.25)
+		} else {
+			rounded = roundTo(q.Amount, 0.5)
+		}
+	case ingredients.Pound:
+		rounded = roundTo(q.Amount, 0.125)
+	case ingredients.Gram, ingredients.Milliliter:
+		switch {
+		case q.Amount < 1:
+			rounded = roundTo(q.Amount, 0.1)
+		case q.Amount < 20:
+			rounded = roundTo(q.Amount, 1)
+		case q.Amount < 250:
+			rounded = roundTo(q.Amount, 5)
+		default:
+			rounded = roundTo(q.Amount, 10)
+		}
+	case ingredients.Kilogram, ingredients.Liter:
+		rounded = roundTo(q.Amount, 0.05)
+	case ingredients.Milligram, ingredients.Centiliter, ingredients.Deciliter:
+		rounded = roundTo(q.Amount, 1)
+	default:
+		return q
+	}
+
+	if rounded <= 0 && q.Amount > 0 {
+		return q
+	}
+	return Quantity{Amount: rounded, Unit: q.Unit}
+}
+
+// roundTo rounds to a multiple of step, dividing by the inverse so that
+// steps like 0.05 give 1.2 rather than 1.2000000000000002
+func roundTo(amount, step float64) float64 {
+	if step >= 1 {
+		return math.Round(amount/step) * step
+	}
+	return math.Round(amount/step) / math.Round(1/step)
+}
+
+// niceFractions are the fractions measuring cups come in
+var niceFractions = []float64{0, 1.0 / 8, 1.0 / 4, 1.0 / 3, 3.0 / 8, 1.0 / 2, 5.0 / 8, 2.0 / 3, 3.0 / 4, 7.0 / 8, 1}
+
+// roundFraction rounds to the nearest whole number plus a nice fraction
+func roundFraction(amount float64) float64 {
+	whole := math.Floor(amount)
+	frac := amount - whole
+	best := niceFractions[0]
+	for _, f := range niceFractions {
+		if math.Abs(frac-f) < math.Abs(frac-best) {
+			best = f
+		}
+	}
+	return whole + best
+}
+
+// ConvertIngredient renders an ingredient's quantity, range and package size
+// in the measurement system. Ingredients without a quantity or with a count
+// or unknown unit are returned with only their size converted.
+func ConvertIngredient(ing ingredients.Ingredient, system string) ingredients.Ingredient {
+	if ing.Size != nil {
+		size := ToSystem(Quantity{Amount: ing.Size.Quantity, Unit: ing.Size.Unit}, system, ing.Name)
+		ing.Size = &ingredients.Amount{Quantity: size.Amount, Unit: size.Unit}
+	}
+	if ing.Quantity <= 0 || ing.UnknownUnit || DimensionOf(ing.Unit) == Count {
+		return ing
+	}
+
+	converted := ToSystem(Quantity{Amount: ing.Quantity, Unit: ing.Unit}, system, ing.Name)
+	if ing.QuantityMax > 0 {
+		upper, err := Convert(Quantity{Amount: ing.QuantityMax, Unit: ing.Unit}, converted.Unit, ing.Name)
+		if err != nil {
+			return ing
+		}
+		ing.QuantityMax = Round(upper).Amount
+		if ing.QuantityMax <= converted.Amount {
+			ing.QuantityMax = 0
+		}
+	}
+	ing.Quantity, ing.Unit = converted.Amount, converted.Unit
+	return ing
+}
+
+// Combine adds up ingredients like ingredients.Combine, and also merges
+// entries for the same ingredient in different units, e.g. "200 g butter"
+// and "2 tbsp butter", into the unit of the first. Amounts that cannot be
+// converted, such as a volume of an ingredient with no known density, are
+// kept as separate entries. Quantities are not rounded.
+func Combine(items []ingredients.Ingredient) []ingredients.Ingredient {
+	exact := ingredients.Combine(items)
+	var combined []ingredients.Ingredient
+	for _, item := range exact {
+		merged := false
+		for i := range combined {
+			total := &combined[i]
+			if !sameIngredient(*total, item) {
+				continue
+			}
+			sum, err := Add(Quantity{Amount: total.Quantity, Unit: total.Unit}, Quantity{Amount: item.Quantity, Unit: item.Unit}, item.Name)
+			if err != nil {
+				continue
+			}
+			if total.QuantityMax > 0 || item.QuantityMax > 0 {
+				maxSum, err := Add(Quantity{Amount: upperBound(*total), Unit: total.Unit}, Quantity{Amount: upperBound(item), Unit: item.Unit}, item.Name)
+				if err != nil {
+					continue
+				}
+				total.QuantityMax = maxSum.Amount
+			}
+			total.Quantity = sum.Amount
+			merged = true
+			break
+		}
+		if !merged {
+			combined = append(combined, item)
+		}
+	}
+	return combined
+}
+
+// sameIngredient reports whether a and b name the same ingredient in sizes
+// that can be added up
+func sameIngredient(a, b ingredients.Ingredient) bool {
+	if !strings.EqualFold(a.Name, b.Name) || a.UnknownUnit || b.UnknownUnit {
+		return false
+	}
+	if (a.Size == nil) != (b.Size == nil) || (a.Size != nil && *a.Size != *b.Size) {
+		return false
+	}
+	return DimensionOf(a.Unit) != Count && DimensionOf(b.Unit) != Count
+}
+
+func upperBound(i ingredients.Ingredient) float64 {
+	if i.QuantityMax > 0 {
+		return i.QuantityMax
+	}
+	return i.Quantity
+}
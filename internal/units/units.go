@@ -0,0 +1,109 @@
+// Package units converts ingredient quantities between metric and US
+// customary measures, and between volume and weight using per-ingredient
+// densities.
+package units
+
+import (
+	"errors"
+
+	"github.com/meal-planner/backend/internal/ingredients"
+)
+
+var (
+	ErrIncompatible   = errors.New("units cannot be converted")
+	ErrUnknownDensity = errors.New("no density known for ingredient")
+)
+
+// Dimension is what a unit measures
+type Dimension int
+
+const (
+	Count Dimension = iota
+	Volume
+	Mass
+)
+
+// Quantity is an amount of a unit, using the canonical unit names of the
+// ingredients package
+type Quantity struct {
+	Amount float64
+	Unit   string
+}
+
+type unitDef struct {
+	dimension Dimension
+	// factor converts one unit to milliliters or grams
+	factor float64
+	metric bool
+}
+
+var unitDefs = map[string]unitDef{
+	ingredients.Teaspoon:   {Volume, 4.92892, false},
+	ingredients.Tablespoon: {Volume, 14.7868, false},
+	ingredients.FluidOunce: {Volume, 29.5735, false},
+	ingredients.Cup:        {Volume, 236.588, false},
+	ingredients.Pint:       {Volume, 473.176, false},
+	ingredients.Quart:      {Volume, 946.353, false},
+	ingredients.Gallon:     {Volume, 3785.41, false},
+	ingredients.Milliliter: {Volume, 1, true},
+	ingredients.Centiliter: {Volume, 10, true},
+	ingredients.Deciliter:  {Volume, 100, true},
+	ingredients.Liter:      {Volume, 1000, true},
+	ingredients.Ounce:      {Mass, 28.3495, false},
+	ingredients.Pound:      {Mass, 453.592, false},
+	ingredients.Milligram:  {Mass, 0.001, true},
+	ingredients.Gram:       {Mass, 1, true},
+	ingredients.Kilogram:   {Mass, 1000, true},
+}
+
+// DimensionOf returns what unit measures; count units such as "clove" and
+// unknown units are Count
+func DimensionOf(unit string) Dimension {
+	return unitDefs[unit].dimension
+}
+
+// IsMetric reports whether unit is a metric measure
+func IsMetric(unit string) bool {
+	return unitDefs[unit].metric
+}
+
+// Convert converts q to the unit to. Converting between volume and mass uses
+// the density of the named ingredient and fails with ErrUnknownDensity when
+// none is known.
+func Convert(q Quantity, to, ingredient string) (Quantity, error) {
+	if q.Unit == to {
+		return q, nil
+	}
+	// Count units and units not in the table have no defined size
+	from, ok := unitDefs[q.Unit]
+	if !ok {
+		return q, ErrIncompatible
+	}
+	target, ok := unitDefs[to]
+	if !ok {
+		return q, ErrIncompatible
+	}
+
+	base := q.Amount * from.factor
+	if from.dimension != target.dimension {
+		density, ok := Density(ingredient)
+		if !ok {
+			return q, ErrUnknownDensity
+		}
+		if from.dimension == Volume {
+			base *= density
+		} else {
+			base /= density
+		}
+	}
+	return Quantity{Amount: base / target.factor, Unit: to}, nil
+}
+
+// Add adds b to a, converting b to a's unit
+func Add(a, b Quantity, ingredient string) (Quantity, error) {
+	converted, err := Convert(b, a.Unit, ingredient)
+	if err != nil {
+		return a, err
+	}
+	return Quantity{Amount: a.Amount + converted.Amount, Unit: a.Unit}, nil
+}
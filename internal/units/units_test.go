@@ -0,0 +1,321 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/meal-planner/backend/internal/ingredients"
+	"github.com/meal-planner/backend/internal/locale"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name       string
+		q          Quantity
+		to         string
+		ingredient string
+		want       float64
+		wantErr    error
+	}{
+		{"cups to ml", Quantity{1, ingredients.Cup}, ingredients.Milliliter, "", 236.588, nil},
+		{"tbsp to tsp", Quantity{1, ingredients.Tablespoon}, ingredients.Teaspoon, "", 3, nil},
+		{"lb to oz", Quantity{1, ingredients.Pound}, ingredients.Ounce, "", 16, nil},
+		{"kg to lb", Quantity{1, ingredients.Kilogram}, ingredients.Pound, "", 2.20462, nil},
+		{"l to quart", Quantity{1, ingredients.Liter}, ingredients.Quart, "", 1.05669, nil},
+		{"cup of flour to grams", Quantity{1, ingredients.Cup}, ingredients.Gram, "all-purpose flour", 125.39, nil},
+		{"cup of sugar to grams", Quantity{1, ingredients.Cup}, ingredients.Gram, "sugar", 201.1, nil},
+		{"cup of brown sugar to grams", Quantity{1, ingredients.Cup}, ingredients.Gram, "light brown sugar", 220.03, nil},
+		{"grams of butter to tbsp", Quantity{113, ingredients.Gram}, ingredients.Tablespoon, "unsalted butter", 7.96, nil},
+		{"same unit", Quantity{3, "clove"}, "clove", "garlic", 3, nil},
+		{"volume to mass without density", Quantity{1, ingredients.Cup}, ingredients.Gram, "chopped kale", 1, ErrUnknownDensity},
+		{"count unit", Quantity{1, "can"}, ingredients.Gram, "tomatoes", 1, ErrIncompatible},
+		{"unknown unit", Quantity{1, "smidgen"}, ingredients.Teaspoon, "salt", 1, ErrIncompatible},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.q, tt.to, tt.ingredient)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if math.Abs(got.Amount-tt.want) > 0.01 {
+				t.Errorf("Convert() = %v, want %v", got.Amount, tt.want)
+			}
+		})
+	}
+}
+
+func TestDensity(t *testing.T) {
+	tests := []struct {
+		ingredient string
+		want       float64
+		wantOK     bool
+	}{
+		{"Bread Flour", 0.55, true},
+		{"flour", 0.53, true},
+		{"powdered sugar", 0.51, true},
+		{"extra virgin olive oil", 0.92, true},
+		{"peanut butter", 1.08, true},
+		{"butternut squash", 0, false},
+		{"bell peppers", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ingredient, func(t *testing.T) {
+			got, ok := Density(tt.ingredient)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Density(%q) = %v, %v, want %v, %v", tt.ingredient, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestToSystem(t *testing.T) {
+	tests := []struct {
+		name       string
+		q          Quantity
+		system     string
+		ingredient string
+		want       Quantity
+	}{
+		{"flour is weighed in metric", Quantity{2, ingredients.Cup}, locale.UnitSystemMetric, "flour", Quantity{250, ingredients.Gram}},
+		{"milk stays a volume in metric", Quantity{2, ingredients.Cup}, locale.UnitSystemMetric, "milk", Quantity{470, ingredients.Milliliter}},
+		{"unknown density stays a volume", Quantity{1, ingredients.Cup}, locale.UnitSystemMetric, "spinach", Quantity{235, ingredients.Milliliter}},
+		{"spoons stay spoons in metric", Quantity{2, ingredients.Tablespoon}, locale.UnitSystemMetric, "sugar", Quantity{2, ingredients.Tablespoon}},
+		{"pounds to grams", Quantity{1, ingredients.Pound}, locale.UnitSystemMetric, "chicken thighs", Quantity{450, ingredients.Gram}},
+		{"large mass to kg", Quantity{3, ingredients.Pound}, locale.UnitSystemMetric, "potatoes", Quantity{1.35, ingredients.Kilogram}},
+		{"quart to liters", Quantity{2, ingredients.Quart}, locale.UnitSystemMetric, "stock", Quantity{1.9, ingredients.Liter}},
+		{"metric amounts keep their unit", Quantity{7, ingredients.Gram}, locale.UnitSystemMetric, "yeast", Quantity{7, ingredients.Gram}},
+		{"grams to ounces", Quantity{200, ingredients.Gram}, locale.UnitSystemUS, "cheddar", Quantity{7, ingredients.Ounce}},
+		{"grams to pounds", Quantity{500, ingredients.Gram}, locale.UnitSystemUS, "ground beef", Quantity{1.125, ingredients.Pound}},
+		{"ml to cups", Quantity{250, ingredients.Milliliter}, locale.UnitSystemUS, "milk", Quantity{1, ingredients.Cup}},
+		{"small ml to tsp", Quantity{5, ingredients.Milliliter}, locale.UnitSystemUS, "vanilla", Quantity{1, ingredients.Teaspoon}},
+		{"ml to tbsp", Quantity{30, ingredients.Milliliter}, locale.UnitSystemUS, "oil", Quantity{2, ingredients.Tablespoon}},
+		{"liters to cups", Quantity{1, ingredients.Liter}, locale.UnitSystemUS, "water", Quantity{4.25, ingredients.Cup}},
+		{"us amounts keep their unit", Quantity{0.33, ingredients.Cup}, locale.UnitSystemUS, "honey", Quantity{1.0 / 3, ingredients.Cup}},
+		{"count units are unchanged", Quantity{2, "clove"}, locale.UnitSystemMetric, "garlic", Quantity{2, "clove"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToSystem(tt.q, tt.system, tt.ingredient)
+			if got.Unit != tt.want.Unit || math.Abs(got.Amount-tt.want.Amount) > 1e-9 {
+				t.Errorf("ToSystem(%v, %q) = %v, want %v", tt.q, tt.system, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		q    Quantity
+		want float64
+	}{
+		{Quantity{0.3, ingredients.Cup}, 1.0 / 3},
+		{Quantity{1.6, ingredients.Cup}, 1.625},
+		{Quantity{0.2, ingredients.Teaspoon}, 0.25},
+		{Quantity{0.01, ingredients.Teaspoon}, 0.01},
+		{Quantity{1.6, ingredients.Teaspoon}, 1.5},
+		{Quantity{2.9, ingredients.Tablespoon}, 3},
+		{Quantity{3.1, ingredients.Ounce}, 3},
+		{Quantity{9.8, ingredients.Ounce}, 10},
+		{Quantity{0.44, ingredients.Gram}, 0.4},
+		{Quantity{12.4, ingredients.Gram}, 12},
+		{Quantity{228.4, ingredients.Gram}, 230},
+		{Quantity{396.9, ingredients.Gram}, 400},
+		{Quantity{1.27, ingredients.Kilogram}, 1.25},
+		{Quantity{0.01, ingredients.Kilogram}, 0.01},
+		{Quantity{3, "clove"}, 3},
+	}
+
+	for _, tt := range tests {
+		if got := Round(tt.q); math.Abs(got.Amount-tt.want) > 1e-9 {
+			t.Errorf("Round(%v) = %v, want %v", tt.q, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestConvertIngredient(t *testing.T) {
+	tests := []struct {
+		line   string
+		system string
+		want   ingredients.Ingredient
+	}{
+		{
+			line:   "1 (14 oz) can diced tomatoes",
+			system: locale.UnitSystemMetric,
+			want:   ingredients.Ingredient{Quantity: 1, Unit: "can", Size: &ingredients.Amount{Quantity: 400, Unit: ingredients.Gram}, Name: "diced tomatoes"},
+		},
+		{
+			line:   "1-2 cups flour, sifted",
+			system: locale.UnitSystemMetric,
+			want:   ingredients.Ingredient{Quantity: 125, QuantityMax: 250, Unit: ingredients.Gram, Name: "flour", Note: "sifted"},
+		},
+		{
+			line:   "500 ml milk",
+			system: locale.UnitSystemUS,
+			want:   ingredients.Ingredient{Quantity: 2.125, Unit: ingredients.Cup, Name: "milk"},
+		},
+		{
+			line:   "salt to taste",
+			system: locale.UnitSystemMetric,
+			want:   ingredients.Ingredient{Name: "salt", Note: "to taste"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got := ConvertIngredient(ingredients.Parse(tt.line), tt.system)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConvertIngredient(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCombine(t *testing.T) {
+	items := []ingredients.Ingredient{
+		ingredients.Parse("200 g butter"),
+		ingredients.Parse("1 cup kale"),
+		ingredients.Parse("2 tbsp butter, melted"),
+		ingredients.Parse("100 g kale"),
+		ingredients.Parse("1 tsp salt"),
+		ingredients.Parse("1/2 tsp salt"),
+		ingredients.Parse("2 cloves garlic"),
+		ingredients.Parse("1 tbsp garlic"),
+	}
+
+	got := Combine(items)
+	if len(got) != 6 {
+		t.Fatalf("Combine() returned %d items, want 6: %+v", len(got), got)
+	}
+	if got[0].Name != "butter" || got[0].Unit != ingredients.Gram || math.Abs(got[0].Quantity-228.39) > 0.01 {
+		t.Errorf("butter = %+v, want about 228.39 g", got[0])
+	}
+	if got[1].Unit != ingredients.Cup || got[2].Unit != ingredients.Gram {
+		t.Errorf("kale without a density should stay separate, got %+v and %+v", got[1], got[2])
+	}
+	if got[3].Name != "salt" || got[3].Quantity != 1.5 {
+		t.Errorf("salt = %+v, want 1.5 tsp", got[3])
+	}
+	if got[4].Unit != "clove" || got[5].Unit != ingredients.Tablespoon {
+		t.Errorf("count units should stay separate, got %+v and %+v", got[4], got[5])
+	}
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		factor float64
+		want   ingredients.Ingredient
+	}{
+		{
+			name:   "doubles cups",
+			line:   "1 1/2 cups flour",
+			factor: 2,
+			want:   ingredients.Ingredient{Quantity: 3, Unit: ingredients.Cup, Name: "flour"},
+		},
+		{
+			name:   "halving a quarter cup gives tablespoons",
+			line:   "1/4 cup olive oil",
+			factor: 0.5,
+			want:   ingredients.Ingredient{Quantity: 2, Unit: ingredients.Tablespoon, Name: "olive oil"},
+		},
+		{
+			name:   "tablespoons grow into cups",
+			line:   "2 tbsp butter",
+			factor: 2,
+			want:   ingredients.Ingredient{Quantity: 0.25, Unit: ingredients.Cup, Name: "butter"},
+		},
+		{
+			name:   "thirds round to a nice fraction",
+			line:   "1 cup milk",
+			factor: 1.5,
+			want:   ingredients.Ingredient{Quantity: 1.5, Unit: ingredients.Cup, Name: "milk"},
+		},
+		{
+			name:   "six to four",
+			line:   "1 cup rice",
+			factor: 2.0 / 3,
+			want:   ingredients.Ingredient{Quantity: 2.0 / 3, Unit: ingredients.Cup, Name: "rice"},
+		},
+		{
+			name:   "pounds to ounces",
+			line:   "1 lb ground beef",
+			factor: 0.5,
+			want:   ingredients.Ingredient{Quantity: 8, Unit: ingredients.Ounce, Name: "ground beef"},
+		},
+		{
+			name:   "grams to kilograms",
+			line:   "600 g potatoes",
+			factor: 2,
+			want:   ingredients.Ingredient{Quantity: 1.2, Unit: ingredients.Kilogram, Name: "potatoes"},
+		},
+		{
+			name:   "many cups become quarts",
+			line:   "4 cups stock",
+			factor: 3,
+			want:   ingredients.Ingredient{Quantity: 3, Unit: ingredients.Quart, Name: "stock"},
+		},
+		{
+			name:   "eggs round to whole",
+			line:   "3 large eggs",
+			factor: 0.5,
+			want:   ingredients.Ingredient{Quantity: 2, Name: "large eggs"},
+		},
+		{
+			name:   "eggs never below one",
+			line:   "1 egg",
+			factor: 0.25,
+			want:   ingredients.Ingredient{Quantity: 1, Name: "egg"},
+		},
+		{
+			name:   "cloves round to whole",
+			line:   "3 cloves garlic",
+			factor: 1.5,
+			want:   ingredients.Ingredient{Quantity: 5, Unit: "clove", Name: "garlic"},
+		},
+		{
+			name:   "counted items round to quarters",
+			line:   "1 onion",
+			factor: 0.5,
+			want:   ingredients.Ingredient{Quantity: 0.5, Name: "onion"},
+		},
+		{
+			name:   "package size is not scaled",
+			line:   "1 (14 oz) can tomatoes",
+			factor: 1.5,
+			want:   ingredients.Ingredient{Quantity: 1.5, Unit: "can", Size: &ingredients.Amount{Quantity: 14, Unit: ingredients.Ounce}, Name: "tomatoes"},
+		},
+		{
+			name:   "ranges scale both bounds",
+			line:   "2-3 tbsp lemon juice",
+			factor: 2,
+			want:   ingredients.Ingredient{Quantity: 0.25, QuantityMax: 3.0 / 8, Unit: ingredients.Cup, Name: "lemon juice"},
+		},
+		{
+			name:   "to taste stays unscaled",
+			line:   "1/2 tsp salt, or to taste",
+			factor: 3,
+			want:   ingredients.Ingredient{Quantity: 0.5, Unit: ingredients.Teaspoon, Name: "salt", Note: "or to taste"},
+		},
+		{
+			name:   "no quantity stays unscaled",
+			line:   "black pepper",
+			factor: 3,
+			want:   ingredients.Ingredient{Name: "black pepper"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Scale(ingredients.Parse(tt.line), tt.factor)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scale(%q, %v) = %+v, want %+v", tt.line, tt.factor, got, tt.want)
+			}
+		})
+	}
+}
@@ -13,16 +13,24 @@ var (
 )
 
 type JWTClaims struct {
-	UserID string `json:"userId"`
-	Email  string `json:"email"`
+	UserID      string `json:"userId"`
+	Email       string `json:"email"`
+	HouseholdID string `json:"householdId,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // GenerateToken generates a JWT token for a user
 func GenerateToken(userID, email, secret string, expiration time.Duration) (string, error) {
+	return GenerateTokenWithHousehold(userID, email, "", secret, expiration)
+}
+
+// GenerateTokenWithHousehold generates a JWT token that also carries the
+// user's active household
+func GenerateTokenWithHousehold(userID, email, householdID, secret string, expiration time.Duration) (string, error) {
 	claims := JWTClaims{
-		UserID: userID,
-		Email:  email,
+		UserID:      userID,
+		Email:       email,
+		HouseholdID: householdID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -42,12 +42,14 @@ func main() {
 		name     string
 		password string
 		onboarded bool
+		role     string
 	}{
 		{
 			email:    "test@example.com",
 			name:     "Test User",
 			password: "password123",
 			onboarded: true,
+			role:     models.RoleAdmin,
 		},
 		{
 			email:    "demo@example.com",
@@ -74,9 +76,10 @@ func main() {
 			Email:                  testUser.email,
 			Name:                   testUser.name,
 			PasswordHash:           string(hashedPassword),
+			Role:                   testUser.role,
 			HasCompletedOnboarding: testUser.onboarded,
-			CreatedAt:              time.Now(),
-			UpdatedAt:              time.Now(),
+			CreatedAt:              time.Now().UTC(),
+			UpdatedAt:              time.Now().UTC(),
 			Preferences: &models.UserPreferences{
 				Theme:         "light",
 				Notifications: true,
@@ -92,7 +95,7 @@ func main() {
 
 	log.Printf("Successfully seeded %d test users", len(testUsers))
 	log.Println("\nTest Credentials:")
-	log.Println("  Email: test@example.com | Password: password123")
+	log.Println("  Email: test@example.com | Password: password123 (admin)")
 	log.Println("  Email: demo@example.com | Password: demo123")
 	log.Println("  Email: newuser@example.com | Password: newpass123")
 }